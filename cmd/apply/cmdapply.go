@@ -13,6 +13,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/cli-utils/cmd/confirm"
 	"sigs.k8s.io/cli-utils/cmd/flagutils"
 	"sigs.k8s.io/cli-utils/pkg/apply"
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -40,8 +41,13 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		"If true, apply merge patch is calculated on API server instead of client.")
 	cmd.Flags().BoolVar(&r.serverSideOptions.ForceConflicts, "force-conflicts", false,
 		"If true, overwrite applied fields on server if field manager conflict.")
+	cmd.Flags().StringSliceVar(&r.serverSideOptions.ForceConflictsManagers, "force-conflicts-from", nil,
+		"Overwrite applied fields on server if all the conflicting fields are owned by these field managers.")
 	cmd.Flags().StringVar(&r.serverSideOptions.FieldManager, "field-manager", common.DefaultFieldManager,
 		"The client owner of the fields being applied on the server-side.")
+	cmd.Flags().BoolVar(&r.serverSideOptions.MigrateClientSideApply, "migrate-client-side-apply", false,
+		"If true during server-side apply, migrate the fields owned by client-side apply to the field manager "+
+			"and remove the last-applied-configuration annotation.")
 
 	cmd.Flags().StringVar(&r.output, "output", printers.DefaultPrinter(),
 		fmt.Sprintf("Output format, must be one of %s", strings.Join(printers.SupportedPrinters(), ",")))
@@ -53,13 +59,49 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		"Background", "Propagation policy for pruning")
 	cmd.Flags().DurationVar(&r.pruneTimeout, "prune-timeout", time.Duration(0),
 		"Timeout threshold for waiting for all pruned resources to be deleted")
+	cmd.Flags().DurationVar(&r.forceRemoveFinalizersAfter, "force-remove-finalizers-after", 0,
+		"If set, remove the finalizers of pruned objects which are terminating for longer than this duration. "+
+			"This skips the cleanup of the controllers that added the finalizers. Zero means never.")
+	cmd.Flags().BoolVar(&r.recreateOnImmutable, "recreate-on-immutable", false,
+		"If true, delete and re-create objects when the apply fails because an immutable field changed.")
+	cmd.Flags().DurationVar(&r.waitProgressInterval, "wait-progress-interval", 0,
+		"If set, print the status of the objects which are not reconciled yet at this interval while waiting. "+
+			"Zero means never.")
+	cmd.Flags().DurationVar(&r.verifyTimeout, "verify-timeout", 0,
+		"Timeout threshold for each verification check requested by the config.kubernetes.io/verify annotation. "+
+			"The checks are retried until they pass or the timeout expires. Zero means the default of 5m.")
+	cmd.Flags().BoolVar(&r.preflight, "preflight", false,
+		"If true, check the permissions needed to apply, prune and watch the objects before applying, "+
+			"and fail with the list of missing permissions.")
+	cmd.Flags().IntVar(&r.maxPruneCount, "max-prune-count", 0,
+		"Maximum number of inventory objects to prune in one run. Zero means no limit.")
+	cmd.Flags().IntVar(&r.maxPrunePercent, "max-prune-percent", 0,
+		"Maximum percentage of inventory objects to prune in one run. Zero means no limit.")
+	cmd.Flags().BoolVar(&r.allowMassPrune, "allow-mass-prune", false,
+		"If true, prune even if --max-prune-count or --max-prune-percent is exceeded.")
+	cmd.Flags().StringSliceVar(&r.pruneAllowKinds, "prune-allow-kinds", nil,
+		"If set, only objects of these kinds (Kind.group) are pruned.")
+	cmd.Flags().StringSliceVar(&r.pruneDenyKinds, "prune-deny-kinds", nil,
+		"Objects of these kinds (Kind.group) are never pruned, e.g. Secret,PersistentVolumeClaim,Namespace.")
+	cmd.Flags().StringSliceVar(&r.pruneAllowNamespaces, "prune-allow-namespaces", nil,
+		"If set, only objects in these namespaces are pruned.")
+	cmd.Flags().StringSliceVar(&r.pruneDenyNamespaces, "prune-deny-namespaces", nil,
+		"Objects in these namespaces are never pruned.")
+	cmd.Flags().BoolVar(&r.pruneNonEmptyNamespaces, "prune-non-empty-namespaces", false,
+		"If true, prune namespaces even if they contain objects not in the inventory.")
 	cmd.Flags().StringVar(&r.inventoryPolicy, flagutils.InventoryPolicyFlag, flagutils.InventoryPolicyStrict,
 		"It determines the behavior when the resources don't belong to current inventory. Available options "+
 			fmt.Sprintf("%q, %q and %q.", flagutils.InventoryPolicyStrict, flagutils.InventoryPolicyAdopt, flagutils.InventoryPolicyForceAdopt))
+	cmd.Flags().StringVar(&r.errorPolicy, flagutils.ErrorPolicyFlag, flagutils.ErrorPolicyContinue,
+		"It determines the behavior after an object fails to be applied, pruned or reconciled. Available options "+
+			fmt.Sprintf("%q and %q. With %q, the remaining tasks are skipped, except for the inventory update.",
+				flagutils.ErrorPolicyContinue, flagutils.ErrorPolicyAbort, flagutils.ErrorPolicyAbort))
 	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
 		"How long to wait before exiting")
 	cmd.Flags().BoolVar(&r.printStatusEvents, "status-events", false,
 		"Print status events (always enabled for table output)")
+	cmd.Flags().BoolVar(&r.confirm, confirm.Flag, false,
+		"If true, print the plan and wait for confirmation before applying.")
 
 	r.Command = cmd
 	return r
@@ -77,15 +119,30 @@ type Runner struct {
 	invFactory inventory.ClientFactory
 	loader     manifestreader.ManifestLoader
 
-	serverSideOptions      common.ServerSideOptions
-	output                 string
-	reconcileTimeout       time.Duration
-	noPrune                bool
-	prunePropagationPolicy string
-	pruneTimeout           time.Duration
-	inventoryPolicy        string
-	timeout                time.Duration
-	printStatusEvents      bool
+	serverSideOptions          common.ServerSideOptions
+	output                     string
+	reconcileTimeout           time.Duration
+	noPrune                    bool
+	prunePropagationPolicy     string
+	pruneTimeout               time.Duration
+	maxPruneCount              int
+	maxPrunePercent            int
+	allowMassPrune             bool
+	pruneNonEmptyNamespaces    bool
+	pruneAllowKinds            []string
+	pruneDenyKinds             []string
+	pruneAllowNamespaces       []string
+	pruneDenyNamespaces        []string
+	inventoryPolicy            string
+	errorPolicy                string
+	timeout                    time.Duration
+	printStatusEvents          bool
+	forceRemoveFinalizersAfter time.Duration
+	recreateOnImmutable        bool
+	waitProgressInterval       time.Duration
+	verifyTimeout              time.Duration
+	preflight                  bool
+	confirm                    bool
 }
 
 func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
@@ -105,6 +162,15 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	errorPolicy, err := flagutils.ConvertErrorPolicy(r.errorPolicy)
+	if err != nil {
+		return err
+	}
+	prunePolicy, err := flagutils.ConvertPrunePolicy(r.pruneAllowKinds, r.pruneDenyKinds,
+		r.pruneAllowNamespaces, r.pruneDenyNamespaces)
+	if err != nil {
+		return err
+	}
 
 	if found := printers.ValidatePrinterType(r.output); !found {
 		return fmt.Errorf("unknown output type %q", r.output)
@@ -121,6 +187,9 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	objs, err := reader.Read()
+	// The errors of specific objects, like substitution errors, are
+	// reported by the applier, according to the validation policy.
+	objErrs, err := manifestreader.SplitObjectErrors(err)
 	if err != nil {
 		return err
 	}
@@ -129,7 +198,7 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	inv := inventory.WrapInventoryInfoObj(invObj)
+	inv := inventory.WrapInfoObjForClientFactory(r.invFactory, invObj)
 
 	invClient, err := r.invFactory.NewClient(r.factory)
 	if err != nil {
@@ -151,17 +220,36 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 		r.printStatusEvents = true
 	}
 
+	var confirmFunc apply.ConfirmFunc
+	if r.confirm {
+		prompter := &confirm.Prompter{IOStreams: r.ioStreams, Output: r.output}
+		confirmFunc = prompter.Confirm
+	}
+
 	ch := a.Run(ctx, inv, objs, apply.ApplierOptions{
 		ServerSideOptions: r.serverSideOptions,
 		ReconcileTimeout:  r.reconcileTimeout,
 		// If we are not waiting for status, tell the applier to not
 		// emit the events.
-		EmitStatusEvents:       r.printStatusEvents,
-		NoPrune:                r.noPrune,
-		DryRunStrategy:         common.DryRunNone,
-		PrunePropagationPolicy: prunePropPolicy,
-		PruneTimeout:           r.pruneTimeout,
-		InventoryPolicy:        inventoryPolicy,
+		EmitStatusEvents:           r.printStatusEvents,
+		NoPrune:                    r.noPrune,
+		DryRunStrategy:             common.DryRunNone,
+		PrunePropagationPolicy:     prunePropPolicy,
+		PruneTimeout:               r.pruneTimeout,
+		ForceRemoveFinalizersAfter: r.forceRemoveFinalizersAfter,
+		InventoryPolicy:            inventoryPolicy,
+		MaxPruneCount:              r.maxPruneCount,
+		MaxPrunePercent:            r.maxPrunePercent,
+		AllowMassPrune:             r.allowMassPrune,
+		PruneNonEmptyNamespaces:    r.pruneNonEmptyNamespaces,
+		PrunePolicy:                prunePolicy,
+		RecreateOnImmutable:        r.recreateOnImmutable,
+		WaitProgressInterval:       r.waitProgressInterval,
+		VerifyTimeout:              r.verifyTimeout,
+		Preflight:                  r.preflight,
+		ErrorPolicy:                errorPolicy,
+		ConfirmFunc:                confirmFunc,
+		ValidationErrors:           objErrs,
 	})
 
 	// The printer will print updates from the channel. It will block
@@ -0,0 +1,127 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package confirm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/cli-utils/pkg/apply"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/printers"
+)
+
+// Flag is the name of the flag which enables the confirmation prompt.
+const Flag = "confirm"
+
+// Prompter prints the plan of a run with the configured printer and asks
+// the user to confirm it before any task runs. The plan and the prompt are
+// printed to ErrOut, so that the output of the run on Out, like the JSON
+// events, is not mixed with them.
+type Prompter struct {
+	IOStreams genericclioptions.IOStreams
+	// Output is the printer type used to print the plan.
+	Output string
+	// IsTerminal returns true if the passed reader is an interactive
+	// terminal. Defaults to checking for a character device.
+	IsTerminal func(io.Reader) bool
+}
+
+// Confirm implements apply.ConfirmFunc. It prints the plan and reads the
+// answer from stdin. Fails if stdin is not an interactive terminal, or if
+// the context is done before an answer is read.
+func (p *Prompter) Confirm(ctx context.Context, plan apply.Plan) (bool, error) {
+	isTerminal := p.IsTerminal
+	if isTerminal == nil {
+		isTerminal = IsTerminal
+	}
+	if !isTerminal(p.IOStreams.In) {
+		return false, fmt.Errorf("--%s requires an interactive terminal on stdin", Flag)
+	}
+
+	planStreams := genericclioptions.IOStreams{
+		In:     p.IOStreams.In,
+		Out:    p.IOStreams.ErrOut,
+		ErrOut: p.IOStreams.ErrOut,
+	}
+	if err := printers.PrintPlan(p.Output, planStreams, plan.ActionGroups); err != nil {
+		return false, err
+	}
+	p.printSummary(plan)
+
+	// Read the answer in a goroutine, which can not be interrupted, so
+	// that the prompt is abandoned when the context is done.
+	answerCh := make(chan bool, 1)
+	go func() {
+		answerCh <- p.readAnswer()
+	}()
+	select {
+	case confirmed := <-answerCh:
+		return confirmed, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// readAnswer prompts until the user answers yes or no. Returns false at the
+// end of the input.
+func (p *Prompter) readAnswer() bool {
+	reader := bufio.NewReader(p.IOStreams.In)
+	for {
+		fmt.Fprint(p.IOStreams.ErrOut, "Do you want to continue? (yes/no): ")
+		answer, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "yes", "y":
+			return true
+		case "no", "n":
+			return false
+		}
+		if err != nil {
+			// EOF or read error without an answer
+			fmt.Fprintln(p.IOStreams.ErrOut)
+			return false
+		}
+	}
+}
+
+// printSummary prints the number of objects for each action and the
+// objects which will be deleted.
+func (p *Prompter) printSummary(plan apply.Plan) {
+	applyIds := plan.Objects(event.ApplyAction)
+	pruneIds := plan.Objects(event.PruneAction)
+	deleteIds := plan.Objects(event.DeleteAction)
+	fmt.Fprintf(p.IOStreams.ErrOut, "Plan: %d to apply, %d to prune, %d to delete, %d skipped\n",
+		len(applyIds), len(pruneIds), len(deleteIds), len(plan.Skipped))
+	deleteIds = pruneIds.Union(deleteIds)
+	if len(deleteIds) == 0 {
+		return
+	}
+	fmt.Fprintln(p.IOStreams.ErrOut, "Objects to be deleted:")
+	for _, id := range deleteIds {
+		fmt.Fprintf(p.IOStreams.ErrOut, "  %s/%s", strings.ToLower(id.GroupKind.String()), id.Name)
+		if id.Namespace != "" {
+			fmt.Fprintf(p.IOStreams.ErrOut, " (namespace: %s)", id.Namespace)
+		}
+		fmt.Fprintln(p.IOStreams.ErrOut)
+	}
+}
+
+// IsTerminal returns true if the passed reader is a character device,
+// e.g. an interactive terminal.
+func IsTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
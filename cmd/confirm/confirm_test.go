@@ -0,0 +1,125 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package confirm
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/cli-utils/pkg/apply"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/printers"
+)
+
+var (
+	deploymentID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Namespace: "default",
+		Name:      "foo",
+	}
+	configMapID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+		Namespace: "default",
+		Name:      "bar",
+	}
+	plan = apply.Plan{
+		ActionGroups: []event.ActionGroup{
+			{Name: "apply-0", Action: event.ApplyAction, Identifiers: object.ObjMetadataSet{deploymentID}},
+			{Name: "prune-0", Action: event.PruneAction, Identifiers: object.ObjMetadataSet{configMapID}},
+		},
+		Skipped: object.ObjMetadataSet{{Name: "invalid"}},
+	}
+)
+
+func TestPrompterConfirm(t *testing.T) {
+	tests := map[string]struct {
+		input        string
+		noInput      bool
+		terminal     bool
+		expected     bool
+		expectedErr  bool
+		expectedErrs string
+	}{
+		"not a terminal": {
+			input:       "yes\n",
+			terminal:    false,
+			expectedErr: true,
+		},
+		"yes": {
+			input:    "yes\n",
+			terminal: true,
+			expected: true,
+			expectedErrs: "deployment.apps/foo apply pending\n" +
+				"configmap/bar prune pending\n" +
+				"Plan: 1 to apply, 1 to prune, 0 to delete, 1 skipped\n" +
+				"Objects to be deleted:\n" +
+				"  configmap/bar (namespace: default)\n" +
+				"Do you want to continue? (yes/no): ",
+		},
+		"no": {
+			input:    "no\n",
+			terminal: true,
+			expected: false,
+		},
+		"retry until valid answer": {
+			input:    "maybe\ny\n",
+			terminal: true,
+			expected: true,
+		},
+		"end of input": {
+			input:    "",
+			terminal: true,
+			expected: false,
+		},
+		"context done without answer": {
+			noInput:     true,
+			terminal:    true,
+			expectedErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			errOut := &bytes.Buffer{}
+			var in io.Reader = strings.NewReader(tc.input)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.noInput {
+				// Block reads until the end of the test.
+				pr, pw := io.Pipe()
+				defer pw.Close()
+				in = pr
+				cancel()
+			}
+			prompter := &Prompter{
+				IOStreams: genericclioptions.IOStreams{
+					In:     in,
+					Out:    out,
+					ErrOut: errOut,
+				},
+				Output:     printers.EventsPrinter,
+				IsTerminal: func(io.Reader) bool { return tc.terminal },
+			}
+			confirmed, err := prompter.Confirm(ctx, plan)
+			// The plan and the prompt are never printed to Out.
+			assert.Empty(t, out.String())
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, confirmed)
+			if tc.expectedErrs != "" {
+				assert.Equal(t, tc.expectedErrs, errOut.String())
+			}
+		})
+	}
+}
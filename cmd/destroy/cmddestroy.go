@@ -13,6 +13,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/cli-utils/cmd/confirm"
 	"sigs.k8s.io/cli-utils/cmd/flagutils"
 	"sigs.k8s.io/cli-utils/pkg/apply"
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -44,12 +45,30 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 			fmt.Sprintf("%q, %q and %q.", flagutils.InventoryPolicyStrict, flagutils.InventoryPolicyAdopt, flagutils.InventoryPolicyForceAdopt))
 	cmd.Flags().DurationVar(&r.deleteTimeout, "delete-timeout", time.Duration(0),
 		"Timeout threshold for waiting for all deleted resources to complete deletion")
+	cmd.Flags().DurationVar(&r.forceRemoveFinalizersAfter, "force-remove-finalizers-after", 0,
+		"If set, remove the finalizers of deleted objects which are terminating for longer than this duration. "+
+			"This skips the cleanup of the controllers that added the finalizers. Zero means never.")
+	cmd.Flags().DurationVar(&r.waitProgressInterval, "wait-progress-interval", 0,
+		"If set, print the status of the objects which are not deleted yet at this interval while waiting. "+
+			"Zero means never.")
 	cmd.Flags().StringVar(&r.deletePropagationPolicy, "delete-propagation-policy",
 		"Background", "Propagation policy for deletion")
 	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
 		"How long to wait before exiting")
 	cmd.Flags().BoolVar(&r.printStatusEvents, "status-events", false,
 		"Print status events (always enabled for table output)")
+	cmd.Flags().BoolVar(&r.confirm, confirm.Flag, false,
+		"If true, print the plan and wait for confirmation before deleting.")
+	cmd.Flags().BoolVar(&r.deleteNonEmptyNamespaces, "delete-non-empty-namespaces", false,
+		"If true, delete namespaces even if they contain objects not in the inventory.")
+	cmd.Flags().StringSliceVar(&r.deleteAllowKinds, "delete-allow-kinds", nil,
+		"If set, only objects of these kinds (Kind.group) are deleted.")
+	cmd.Flags().StringSliceVar(&r.deleteDenyKinds, "delete-deny-kinds", nil,
+		"Objects of these kinds (Kind.group) are never deleted, e.g. Secret,PersistentVolumeClaim,Namespace.")
+	cmd.Flags().StringSliceVar(&r.deleteAllowNamespaces, "delete-allow-namespaces", nil,
+		"If set, only objects in these namespaces are deleted.")
+	cmd.Flags().StringSliceVar(&r.deleteDenyNamespaces, "delete-deny-namespaces", nil,
+		"Objects in these namespaces are never deleted.")
 
 	r.Command = cmd
 	return r
@@ -69,12 +88,20 @@ type Runner struct {
 	invFactory inventory.ClientFactory
 	loader     manifestreader.ManifestLoader
 
-	output                  string
-	deleteTimeout           time.Duration
-	deletePropagationPolicy string
-	inventoryPolicy         string
-	timeout                 time.Duration
-	printStatusEvents       bool
+	output                     string
+	deleteTimeout              time.Duration
+	deletePropagationPolicy    string
+	inventoryPolicy            string
+	timeout                    time.Duration
+	printStatusEvents          bool
+	forceRemoveFinalizersAfter time.Duration
+	waitProgressInterval       time.Duration
+	confirm                    bool
+	deleteNonEmptyNamespaces   bool
+	deleteAllowKinds           []string
+	deleteDenyKinds            []string
+	deleteAllowNamespaces      []string
+	deleteDenyNamespaces       []string
 }
 
 func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
@@ -94,6 +121,11 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	deletePolicy, err := flagutils.ConvertPrunePolicy(r.deleteAllowKinds, r.deleteDenyKinds,
+		r.deleteAllowNamespaces, r.deleteDenyNamespaces)
+	if err != nil {
+		return err
+	}
 
 	if found := printers.ValidatePrinterType(r.output); !found {
 		return fmt.Errorf("unknown output type %q", r.output)
@@ -112,7 +144,7 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	inv := inventory.WrapInventoryInfoObj(invObj)
+	inv := inventory.WrapInfoObjForClientFactory(r.invFactory, invObj)
 
 	invClient, err := r.invFactory.NewClient(r.factory)
 	if err != nil {
@@ -133,11 +165,22 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 
 	// Run the destroyer. It will return a channel where we can receive updates
 	// to keep track of progress and any issues.
+	var confirmFunc apply.ConfirmFunc
+	if r.confirm {
+		prompter := &confirm.Prompter{IOStreams: r.ioStreams, Output: r.output}
+		confirmFunc = prompter.Confirm
+	}
+
 	ch := d.Run(ctx, inv, apply.DestroyerOptions{
-		DeleteTimeout:           r.deleteTimeout,
-		DeletePropagationPolicy: deletePropPolicy,
-		InventoryPolicy:         inventoryPolicy,
-		EmitStatusEvents:        r.printStatusEvents,
+		DeleteTimeout:              r.deleteTimeout,
+		ForceRemoveFinalizersAfter: r.forceRemoveFinalizersAfter,
+		DeletePropagationPolicy:    deletePropPolicy,
+		InventoryPolicy:            inventoryPolicy,
+		EmitStatusEvents:           r.printStatusEvents,
+		ConfirmFunc:                confirmFunc,
+		DeleteNonEmptyNamespaces:   r.deleteNonEmptyNamespaces,
+		PrunePolicy:                deletePolicy,
+		WaitProgressInterval:       r.waitProgressInterval,
 	})
 
 	// The printer will print updates from the channel. It will block
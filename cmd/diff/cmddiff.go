@@ -23,6 +23,7 @@ const tmpDirPrefix = "diff-cmd"
 // and diff the local config resource against the resource in the cluster.
 func NewCommand(f util.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
 	options := diff.NewDiffOptions(ioStreams)
+	var noRedact bool
 	cmd := &cobra.Command{
 		Use:                   "diff (DIRECTORY | STDIN)",
 		DisableFlagsInUseLine: true,
@@ -32,9 +33,16 @@ func NewCommand(f util.Factory, ioStreams genericclioptions.IOStreams) *cobra.Co
 			cleanupFunc, err := Initialize(options, f, args)
 			defer cleanupFunc()
 			util.CheckErr(err)
-			util.CheckErr(options.Run())
+			if noRedact {
+				util.CheckErr(options.Run())
+			} else {
+				util.CheckErr(RunRedacted(options))
+			}
 		},
 	}
+	cmd.Flags().BoolVar(&noRedact, "no-redact", false,
+		"If true, do not redact the fields listed in the config.kubernetes.io/sensitive-fields annotation. "+
+			"The data of Secrets is always redacted.")
 
 	return cmd
 }
@@ -0,0 +1,143 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/cmd/diff"
+	"k8s.io/utils/exec"
+	"sigs.k8s.io/cli-utils/pkg/redact"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// liveDirPrefix and mergedDirPrefix are the prefixes of the temporary
+	// directories where DiffOptions.Run() writes the versions of the objects.
+	liveDirPrefix   = "LIVE-"
+	mergedDirPrefix = "MERGED-"
+)
+
+// RunRedacted runs DiffOptions.Run(), but redacts the sensitive values of the
+// live and merged objects before the diff program compares them.
+func RunRedacted(o *diff.DiffOptions) error {
+	diffExec := o.Diff.Exec
+	o.Diff.Exec = &redactingExec{Interface: diffExec}
+	defer func() {
+		o.Diff.Exec = diffExec
+	}()
+	return o.Run()
+}
+
+// redactingExec wraps the exec.Interface of the diff program, to redact the
+// objects in the directories passed to the diff program before it runs.
+type redactingExec struct {
+	exec.Interface
+}
+
+// Command redacts the objects in the LIVE and MERGED directories of the
+// arguments, and returns the command. If the redaction fails, the returned
+// command fails to run, so that sensitive values are not shown.
+func (e *redactingExec) Command(cmd string, args ...string) exec.Cmd {
+	c := e.Interface.Command(cmd, args...)
+	if err := redactDirs(args); err != nil {
+		return &failedCmd{Cmd: c, err: err}
+	}
+	return c
+}
+
+// failedCmd is a command that fails to run with the error.
+type failedCmd struct {
+	exec.Cmd
+	err error
+}
+
+// Run returns the error, without running the command.
+func (c *failedCmd) Run() error {
+	return c.err
+}
+
+// redactDirs redacts the objects in the LIVE and MERGED directories of the
+// diff program arguments, which kubectl passes one after the other.
+func redactDirs(args []string) error {
+	for i := 0; i+1 < len(args); i++ {
+		if !strings.HasPrefix(filepath.Base(args[i]), liveDirPrefix) ||
+			!strings.HasPrefix(filepath.Base(args[i+1]), mergedDirPrefix) {
+			continue
+		}
+		entries, err := os.ReadDir(args[i])
+		if err != nil {
+			return fmt.Errorf("failed to redact diff: %w", err)
+		}
+		for _, entry := range entries {
+			err := redactFiles(filepath.Join(args[i], entry.Name()),
+				filepath.Join(args[i+1], entry.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to redact diff: %w", err)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// redactFiles redacts the live and merged versions of an object together,
+// so that changed values are still visible in the diff. The files are only
+// rewritten if the object is sensitive.
+func redactFiles(livePath, mergedPath string) error {
+	live, err := readObject(livePath)
+	if err != nil {
+		return err
+	}
+	merged, err := readObject(mergedPath)
+	if err != nil {
+		return err
+	}
+	if !redact.IsSensitive(live) && !redact.IsSensitive(merged) {
+		return nil
+	}
+	live, merged = redact.Pair(live, merged)
+	if err := writeObject(livePath, live); err != nil {
+		return err
+	}
+	return writeObject(mergedPath, merged)
+}
+
+// readObject reads the object from the YAML file, or returns nil if the file
+// is missing or empty, as for objects which do not exist.
+func readObject(path string) (*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var content map[string]interface{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// writeObject writes the object to the YAML file, or an empty file if the
+// object is nil.
+func writeObject(path string, obj *unstructured.Unstructured) error {
+	var data []byte
+	if obj != nil {
+		var err error
+		data, err = yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0600)
+}
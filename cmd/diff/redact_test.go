@@ -0,0 +1,144 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+var liveSecret = `apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+`
+
+var mergedSecret = `apiVersion: v1
+data:
+  password: bmV3
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+`
+
+var configMap = `apiVersion: v1
+data:
+  mode: debug
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+`
+
+func TestRedactingExec_Command(t *testing.T) {
+	testCases := map[string]struct {
+		live           map[string]string
+		merged         map[string]string
+		expectedLive   map[string]string
+		expectedMerged map[string]string
+		expectedErr    string
+	}{
+		"sensitive values are redacted": {
+			live:   map[string]string{"v1.Secret.default.creds": liveSecret},
+			merged: map[string]string{"v1.Secret.default.creds": mergedSecret},
+			expectedLive: map[string]string{
+				"v1.Secret.default.creds": "apiVersion: v1\ndata:\n  password: '*** (before)'\nkind: Secret\n" +
+					"metadata:\n  name: creds\n  namespace: default\n",
+			},
+			expectedMerged: map[string]string{
+				"v1.Secret.default.creds": "apiVersion: v1\ndata:\n  password: '*** (after)'\nkind: Secret\n" +
+					"metadata:\n  name: creds\n  namespace: default\n",
+			},
+		},
+		"new objects are redacted": {
+			live:   map[string]string{"v1.Secret.default.creds": ""},
+			merged: map[string]string{"v1.Secret.default.creds": mergedSecret},
+			expectedLive: map[string]string{
+				"v1.Secret.default.creds": "",
+			},
+			expectedMerged: map[string]string{
+				"v1.Secret.default.creds": "apiVersion: v1\ndata:\n  password: '***'\nkind: Secret\n" +
+					"metadata:\n  name: creds\n  namespace: default\n",
+			},
+		},
+		"other objects are unchanged": {
+			live:           map[string]string{"v1.ConfigMap.default.config": configMap},
+			merged:         map[string]string{"v1.ConfigMap.default.config": configMap},
+			expectedLive:   map[string]string{"v1.ConfigMap.default.config": configMap},
+			expectedMerged: map[string]string{"v1.ConfigMap.default.config": configMap},
+		},
+		"invalid objects fail the diff": {
+			live:        map[string]string{"v1.Secret.default.creds": "{"},
+			merged:      map[string]string{"v1.Secret.default.creds": mergedSecret},
+			expectedErr: "failed to redact diff",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			liveDir := writeDir(t, liveDirPrefix, tc.live)
+			mergedDir := writeDir(t, mergedDirPrefix, tc.merged)
+
+			runs := 0
+			fakeExec := &testingexec.FakeExec{
+				CommandScript: []testingexec.FakeCommandAction{
+					func(cmd string, args ...string) exec.Cmd {
+						return &testingexec.FakeCmd{
+							RunScript: []testingexec.FakeAction{
+								func() ([]byte, []byte, error) {
+									runs++
+									return nil, nil, nil
+								},
+							},
+						}
+					},
+				},
+			}
+			e := &redactingExec{Interface: fakeExec}
+
+			err := e.Command("diff", "-u", "-N", liveDir, mergedDir).Run()
+
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.Equal(t, 0, runs)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, runs)
+			assert.Equal(t, tc.expectedLive, readDir(t, liveDir))
+			assert.Equal(t, tc.expectedMerged, readDir(t, mergedDir))
+		})
+	}
+}
+
+func writeDir(t *testing.T, prefix string, files map[string]string) string {
+	dir, err := os.MkdirTemp(t.TempDir(), prefix)
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		files[entry.Name()] = string(content)
+	}
+	return files
+}
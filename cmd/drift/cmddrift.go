@@ -0,0 +1,179 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package drift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/cli-utils/cmd/flagutils"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/drift"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/manifestreader"
+)
+
+const (
+	TablePrinter = "table"
+	JSONPrinter  = "json"
+)
+
+// SupportedPrinters returns the output formats supported by the drift command.
+func SupportedPrinters() []string {
+	return []string{TablePrinter, JSONPrinter}
+}
+
+// GetRunner creates and returns the Runner which stores the cobra command.
+func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
+	loader manifestreader.ManifestLoader, ioStreams genericclioptions.IOStreams) *Runner {
+	r := &Runner{
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
+		ioStreams:  ioStreams,
+	}
+	cmd := &cobra.Command{
+		Use:                   "drift (DIRECTORY | STDIN)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Detect objects which have drifted from the applied configuration"),
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  r.RunE,
+	}
+
+	cmd.Flags().StringVar(&r.fieldManager, "field-manager", common.DefaultFieldManager,
+		"The client owner of the fields that were applied on the server-side.")
+	cmd.Flags().BoolVar(&r.fix, "fix", false,
+		"If true, re-apply the drifted objects, as the apply command does.")
+	cmd.Flags().BoolVar(&r.forceConflicts, "force-conflicts", false,
+		"If true, overwrite applied fields on server if field manager conflict, when fixing drift.")
+	cmd.Flags().StringSliceVar(&r.forceConflictsManagers, "force-conflicts-from", nil,
+		"Overwrite applied fields on server if all the conflicting fields are owned by these field managers, when fixing drift.")
+	cmd.Flags().StringVar(&r.output, "output", TablePrinter,
+		fmt.Sprintf("Output format, must be one of %s", strings.Join(SupportedPrinters(), ",")))
+	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
+		"How long to wait before exiting")
+
+	r.Command = cmd
+	return r
+}
+
+// Command creates the Runner, returning the cobra command associated with it.
+func Command(f cmdutil.Factory, invFactory inventory.ClientFactory, loader manifestreader.ManifestLoader,
+	ioStreams genericclioptions.IOStreams) *cobra.Command {
+	return GetRunner(f, invFactory, loader, ioStreams).Command
+}
+
+// Runner encapsulates data necessary to run the drift command.
+type Runner struct {
+	Command    *cobra.Command
+	factory    cmdutil.Factory
+	invFactory inventory.ClientFactory
+	loader     manifestreader.ManifestLoader
+	ioStreams  genericclioptions.IOStreams
+
+	fieldManager           string
+	fix                    bool
+	forceConflicts         bool
+	forceConflictsManagers []string
+	output                 string
+	timeout                time.Duration
+}
+
+// RunE is the function run from the cobra command.
+func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	// If specified, cancel with timeout.
+	if r.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	printer, err := newPrinter(r.output, r.ioStreams)
+	if err != nil {
+		return err
+	}
+
+	reader, err := r.loader.ManifestReader(cmd.InOrStdin(), flagutils.PathFromArgs(args))
+	if err != nil {
+		return err
+	}
+	objs, err := reader.Read()
+	if err != nil {
+		return err
+	}
+
+	invObj, objs, err := inventory.SplitUnstructureds(objs)
+	if err != nil {
+		return err
+	}
+	inv := inventory.WrapInfoObjForClientFactory(r.invFactory, invObj)
+
+	invClient, err := r.invFactory.NewClient(r.factory)
+	if err != nil {
+		return err
+	}
+	detector, err := drift.NewDetector(r.factory, invClient)
+	if err != nil {
+		return err
+	}
+
+	options := drift.Options{
+		FieldManager:           r.fieldManager,
+		ForceConflicts:         r.forceConflicts,
+		ForceConflictsManagers: r.forceConflictsManagers,
+	}
+	results, err := detector.Detect(ctx, inv, objs, options)
+	if err != nil {
+		return err
+	}
+	var fixErr error
+	if r.fix {
+		fixErr = detector.Fix(ctx, inv, objs, results, options)
+	}
+
+	if err := printer.Print(results); err != nil {
+		return err
+	}
+	if fixErr != nil {
+		return fixErr
+	}
+	return ResultErrorFromResults(results)
+}
+
+// ResultErrorFromResults returns a ResultError if any of the objects have
+// drifted or failed drift detection, otherwise nil.
+func ResultErrorFromResults(results drift.Results) error {
+	if results.DriftedCount() > 0 || results.ErrorCount() > 0 {
+		return &ResultError{
+			Drifted: results.DriftedCount(),
+			Failed:  results.ErrorCount(),
+		}
+	}
+	return nil
+}
+
+// ResultError is returned when drift detection completed, but one or more
+// objects have drifted or could not be checked.
+type ResultError struct {
+	Drifted int
+	Failed  int
+}
+
+func (e *ResultError) Error() string {
+	switch {
+	case e.Drifted > 0 && e.Failed > 0:
+		return fmt.Sprintf("%d resources drifted, %d resources failed", e.Drifted, e.Failed)
+	case e.Failed > 0:
+		return fmt.Sprintf("%d resources failed", e.Failed)
+	default:
+		return fmt.Sprintf("%d resources drifted", e.Drifted)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/cli-utils/pkg/drift"
+)
+
+// Printer prints the results of drift detection.
+type Printer interface {
+	Print(results drift.Results) error
+}
+
+func newPrinter(output string, ioStreams genericclioptions.IOStreams) (Printer, error) {
+	switch output {
+	case TablePrinter:
+		return &tablePrinter{ioStreams: ioStreams}, nil
+	case JSONPrinter:
+		return &jsonPrinter{ioStreams: ioStreams}, nil
+	}
+	return nil, fmt.Errorf("unknown output type %q", output)
+}
+
+type tablePrinter struct {
+	ioStreams genericclioptions.IOStreams
+}
+
+// Print writes one row per object, followed by a summary line.
+func (t *tablePrinter) Print(results drift.Results) error {
+	w := tabwriter.NewWriter(t.ioStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tRESOURCE\tSTATUS\tFIELDS\tMANAGERS\tMESSAGE")
+	for _, r := range results {
+		status := string(r.Status)
+		if r.Fixed {
+			status += " (fixed)"
+		}
+		message := ""
+		if r.Error != nil {
+			message = r.Error.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Identifier.Namespace,
+			fmt.Sprintf("%s/%s", r.Identifier.GroupKind.Kind, r.Identifier.Name),
+			status,
+			strings.Join(r.Fields, ","),
+			strings.Join(r.ForeignManagers, ","),
+			message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(t.ioStreams.Out, "%d resources checked: %d drifted, %d failed\n",
+		len(results), results.DriftedCount(), results.ErrorCount())
+	return nil
+}
+
+type jsonPrinter struct {
+	ioStreams genericclioptions.IOStreams
+}
+
+// Print writes one JSON object per line for each object, followed by a
+// summary object.
+func (j *jsonPrinter) Print(results drift.Results) error {
+	for _, r := range results {
+		m := map[string]interface{}{
+			"type":      "drift",
+			"group":     r.Identifier.GroupKind.Group,
+			"kind":      r.Identifier.GroupKind.Kind,
+			"namespace": r.Identifier.Namespace,
+			"name":      r.Identifier.Name,
+			"status":    string(r.Status),
+			"drifted":   r.Drifted(),
+			"fixed":     r.Fixed,
+		}
+		if len(r.Fields) > 0 {
+			m["fields"] = r.Fields
+		}
+		if len(r.ForeignManagers) > 0 {
+			m["managers"] = r.ForeignManagers
+		}
+		if r.Error != nil {
+			m["error"] = r.Error.Error()
+		}
+		if err := j.printLine(m); err != nil {
+			return err
+		}
+	}
+	return j.printLine(map[string]interface{}{
+		"type":    "summary",
+		"count":   len(results),
+		"drifted": results.DriftedCount(),
+		"failed":  results.ErrorCount(),
+	})
+}
+
+func (j *jsonPrinter) printLine(m map[string]interface{}) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(j.ioStreams.Out, string(b))
+	return err
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 )
 
@@ -17,6 +20,12 @@ const (
 	InventoryPolicyForceAdopt = "force-adopt"
 )
 
+const (
+	ErrorPolicyFlag     = "on-error"
+	ErrorPolicyContinue = "continue"
+	ErrorPolicyAbort    = "abort"
+)
+
 // ConvertPropagationPolicy converts a propagationPolicy described as a
 // string to a DeletionPropagation type that is passed into the Applier.
 func ConvertPropagationPolicy(propagationPolicy string) (metav1.DeletionPropagation, error) {
@@ -47,6 +56,52 @@ func ConvertInventoryPolicy(policy string) (inventory.Policy, error) {
 	}
 }
 
+// ConvertErrorPolicy converts an error policy described as a string to an
+// ErrorPolicy that is passed into the Applier.
+func ConvertErrorPolicy(policy string) (taskrunner.ErrorPolicy, error) {
+	switch policy {
+	case ErrorPolicyContinue:
+		return taskrunner.ContinueOnError, nil
+	case ErrorPolicyAbort:
+		return taskrunner.AbortOnError, nil
+	default:
+		return taskrunner.ContinueOnError, fmt.Errorf(
+			"error policy must be one of continue, abort")
+	}
+}
+
+// ConvertPrunePolicy converts the GroupKinds, in the format Kind.group, and
+// namespaces of the prune policy flags to a PrunePolicy that is passed into
+// the Applier.
+func ConvertPrunePolicy(allowKinds, denyKinds, allowNamespaces, denyNamespaces []string) (filter.PrunePolicy, error) {
+	allowGKs, err := convertGroupKinds(allowKinds)
+	if err != nil {
+		return filter.PrunePolicy{}, err
+	}
+	denyGKs, err := convertGroupKinds(denyKinds)
+	if err != nil {
+		return filter.PrunePolicy{}, err
+	}
+	return filter.PrunePolicy{
+		AllowGroupKinds: allowGKs,
+		DenyGroupKinds:  denyGKs,
+		AllowNamespaces: allowNamespaces,
+		DenyNamespaces:  denyNamespaces,
+	}, nil
+}
+
+func convertGroupKinds(kinds []string) ([]schema.GroupKind, error) {
+	var gks []schema.GroupKind
+	for _, kind := range kinds {
+		gk := schema.ParseGroupKind(kind)
+		if gk.Kind == "" {
+			return nil, fmt.Errorf("invalid kind %q: must be in the format Kind.group", kind)
+		}
+		gks = append(gks, gk)
+	}
+	return gks, nil
+}
+
 // PathFromArgs returns the path which is a positional arg from args list
 // returns "-" if there is length of args is 0, which implies no path is provided
 func PathFromArgs(args []string) string {
@@ -5,8 +5,12 @@ package flagutils
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 )
 
@@ -50,3 +54,61 @@ func TestConvertInventoryPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertErrorPolicy(t *testing.T) {
+	testcases := []struct {
+		value  string
+		policy taskrunner.ErrorPolicy
+		err    error
+	}{
+		{
+			value:  "continue",
+			policy: taskrunner.ContinueOnError,
+		},
+		{
+			value:  "abort",
+			policy: taskrunner.AbortOnError,
+		},
+		{
+			value: "random",
+			err:   fmt.Errorf("error policy must be one of continue, abort"),
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			policy, err := ConvertErrorPolicy(tc.value)
+			if tc.err == nil {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				if policy != tc.policy {
+					t.Errorf("expected %v but got %v", tc.policy, policy)
+				}
+			}
+			if err == nil && tc.err != nil {
+				t.Errorf("expected an error, but not happened")
+			}
+		})
+	}
+}
+
+func TestConvertPrunePolicy(t *testing.T) {
+	policy, err := ConvertPrunePolicy([]string{"Deployment.apps"}, []string{"Secret", "PersistentVolumeClaim"},
+		nil, []string{"kube-system"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := filter.PrunePolicy{
+		AllowGroupKinds: []schema.GroupKind{{Group: "apps", Kind: "Deployment"}},
+		DenyGroupKinds:  []schema.GroupKind{{Kind: "Secret"}, {Kind: "PersistentVolumeClaim"}},
+		DenyNamespaces:  []string{"kube-system"},
+	}
+	if !reflect.DeepEqual(expected, policy) {
+		t.Errorf("expected %v but got %v", expected, policy)
+	}
+
+	_, err = ConvertPrunePolicy(nil, []string{".apps"}, nil, nil)
+	if err == nil {
+		t.Errorf("expected an error, but not happened")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventorycmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/manifestreader"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// NewCmdInventory returns the inventory command, which groups the commands
+// managing inventories.
+func NewCmdInventory(factory cmdutil.Factory, invFactory inventory.ClientFactory,
+	loader manifestreader.ManifestLoader, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "inventory",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Manage inventory objects"),
+	}
+	cmd.AddCommand(TransferCommand(factory, invFactory, loader, ioStreams))
+	return cmd
+}
+
+// GetTransferRunner creates and returns the TransferRunner which stores the
+// cobra command.
+func GetTransferRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
+	loader manifestreader.ManifestLoader, ioStreams genericclioptions.IOStreams) *TransferRunner {
+	r := &TransferRunner{
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
+		ioStreams:  ioStreams,
+	}
+	cmd := &cobra.Command{
+		Use:                   "transfer FROM_DIRECTORY TO_DIRECTORY",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Move objects from one inventory to another, without deleting them"),
+		Long: i18n.T(`Move the objects declared in TO_DIRECTORY, which are in the inventory of
+FROM_DIRECTORY, to the inventory of TO_DIRECTORY. The owning-inventory
+annotation of the objects is rewritten and both inventories are updated.
+The objects are never deleted from the cluster.`),
+		Example: i18n.T(`  # Move the objects now declared in the "app" package from the "legacy" package.
+  kapply inventory transfer legacy/ app/`),
+		Args: cobra.ExactArgs(2),
+		RunE: r.RunE,
+	}
+
+	cmd.Flags().BoolVar(&r.dryRun, "dry-run", false,
+		"If true, only print the objects which would be transferred.")
+	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
+		"How long to wait before exiting")
+
+	r.Command = cmd
+	return r
+}
+
+// TransferCommand creates the TransferRunner, returning the cobra command
+// associated with it.
+func TransferCommand(f cmdutil.Factory, invFactory inventory.ClientFactory, loader manifestreader.ManifestLoader,
+	ioStreams genericclioptions.IOStreams) *cobra.Command {
+	return GetTransferRunner(f, invFactory, loader, ioStreams).Command
+}
+
+// TransferRunner encapsulates data necessary to run the transfer command.
+type TransferRunner struct {
+	Command    *cobra.Command
+	factory    cmdutil.Factory
+	invFactory inventory.ClientFactory
+	loader     manifestreader.ManifestLoader
+	ioStreams  genericclioptions.IOStreams
+
+	dryRun  bool
+	timeout time.Duration
+}
+
+// RunE is the function run from the cobra command.
+func (r *TransferRunner) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	// If specified, cancel with timeout.
+	if r.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	from, _, err := r.readPackage(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	to, toObjs, err := r.readPackage(cmd, args[1])
+	if err != nil {
+		return err
+	}
+
+	invClient, err := r.invFactory.NewClient(r.factory)
+	if err != nil {
+		return err
+	}
+	// Transfer the objects declared in the "to" package, which are still
+	// in the "from" inventory.
+	fromIDs, err := invClient.GetClusterObjs(from)
+	if err != nil {
+		return err
+	}
+	ids := fromIDs.Intersection(object.UnstructuredSetToObjMetadataSet(toObjs))
+
+	dryRunStrategy := common.DryRunNone
+	if r.dryRun {
+		dryRunStrategy = common.DryRunClient
+	}
+	transferer, err := inventory.NewTransferer(r.factory, invClient)
+	if err != nil {
+		return err
+	}
+	if err := transferer.Transfer(ctx, from, to, ids, dryRunStrategy); err != nil {
+		return err
+	}
+
+	suffix := ""
+	if r.dryRun {
+		suffix = " (dry-run)"
+	}
+	for _, id := range ids {
+		fmt.Fprintf(r.ioStreams.Out, "%s/%s transferred%s\n",
+			strings.ToLower(id.GroupKind.String()), id.Name, suffix)
+	}
+	fmt.Fprintf(r.ioStreams.Out, "%d object(s) transferred from inventory %q to inventory %q%s\n",
+		len(ids), from.ID(), to.ID(), suffix)
+	return nil
+}
+
+// readPackage reads the manifests from the passed directory, and returns the
+// inventory and the other objects.
+func (r *TransferRunner) readPackage(cmd *cobra.Command, dir string) (inventory.Info, object.UnstructuredSet, error) {
+	reader, err := r.loader.ManifestReader(cmd.InOrStdin(), dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	objs, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	invObj, objs, err := inventory.SplitUnstructureds(objs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return inventory.WrapInfoObjForClientFactory(r.invFactory, invObj), objs, nil
+}
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
 	"k8s.io/component-base/cli"
@@ -20,8 +22,11 @@ import (
 	"sigs.k8s.io/cli-utils/cmd/apply"
 	"sigs.k8s.io/cli-utils/cmd/destroy"
 	"sigs.k8s.io/cli-utils/cmd/diff"
+	"sigs.k8s.io/cli-utils/cmd/drift"
 	"sigs.k8s.io/cli-utils/cmd/initcmd"
+	"sigs.k8s.io/cli-utils/cmd/inventorycmd"
 	"sigs.k8s.io/cli-utils/cmd/preview"
+	"sigs.k8s.io/cli-utils/cmd/ssaupgrade"
 	"sigs.k8s.io/cli-utils/cmd/status"
 	"sigs.k8s.io/cli-utils/pkg/flowcontrol"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
@@ -61,20 +66,60 @@ func main() {
 		ErrOut: os.Stderr,
 	}
 
-	loader := manifestreader.NewManifestLoader(f)
-	invFactory := inventory.ClusterClientFactory{StatusPolicy: inventory.StatusPolicyNone}
+	var ageIdentityFiles []string
+	flags.StringSliceVar(&ageIdentityFiles, "age-identity", nil,
+		"Path of an age identity file used to decrypt the encrypted values of the manifests.")
+	loaderOptions := &manifestreader.LoaderOptions{}
+	flags.BoolVar(&loaderOptions.Substitute, "substitute", false,
+		"If true, substitute ${VAR} and ${VAR:-default} placeholders in the manifests "+
+			"with the values of environment variables. Use $${VAR} for a literal ${VAR}.")
+	flags.StringVar(&loaderOptions.ValuesFile, "values-file", "",
+		"Path of a YAML file with the values of the variables to substitute in the manifests. "+
+			"The values override the environment variables. Enables substitution.")
+	flags.BoolVar(&loaderOptions.StrictSubstitution, "strict-substitution", false,
+		"If true, undefined variables without a default value are an error, "+
+			"instead of being substituted with an empty string.")
+	loader := manifestreader.NewManifestLoaderWithOptions(f, loaderOptions)
+	invFactory := &inventoryFactory{}
+	flags.StringVar(&invFactory.invType, "inventory-type", inventoryTypeConfigMap,
+		fmt.Sprintf("Type of the inventory: %s or %s. The %s inventory tracks the objects "+
+			"with ApplySet labels and annotations on the inventory object.",
+			inventoryTypeConfigMap, inventoryTypeApplySet, inventoryTypeApplySet))
+	flags.StringVar(&invFactory.tooling, "applyset-tooling", inventory.DefaultApplySetTooling,
+		"Tooling annotation set on new ApplySet parent objects. The default lets kubectl manage the set; "+
+			"kubectl refuses to manage ApplySets of other tooling.")
+	flags.BoolVar(&invFactory.storeStatus, "inventory-status", false,
+		"If true, store the status of each object in the inventory, including the fingerprint of the "+
+			"applied configuration. Drift detection skips the dry-run of objects unchanged since they were applied.")
 
-	names := []string{"init", "apply", "destroy", "diff", "preview", "status"}
+	applyCmd := apply.Command(f, invFactory, loader, ioStreams)
+	destroyCmd := destroy.Command(f, invFactory, loader, ioStreams)
+	previewCmd := preview.Command(f, invFactory, loader, ioStreams)
+	for _, c := range []*cobra.Command{applyCmd, destroyCmd, previewCmd} {
+		addTransformFlags(c.Flags(), &loaderOptions.Transform)
+	}
+
+	names := []string{"init", "apply", "destroy", "diff", "drift", "inventory", "preview", "ssa-upgrade", "status"}
 	subCmds := []*cobra.Command{
 		initcmd.NewCmdInit(f, ioStreams),
-		apply.Command(f, invFactory, loader, ioStreams),
-		destroy.Command(f, invFactory, loader, ioStreams),
+		applyCmd,
+		destroyCmd,
 		diff.NewCommand(f, ioStreams),
-		preview.Command(f, invFactory, loader, ioStreams),
-		status.Command(context.TODO(), f, invFactory, status.NewInventoryLoader(loader)),
+		drift.Command(f, invFactory, loader, ioStreams),
+		inventorycmd.NewCmdInventory(f, invFactory, loader, ioStreams),
+		previewCmd,
+		ssaupgrade.Command(f, invFactory, loader, ioStreams),
+		status.Command(context.TODO(), f, invFactory, &status.InventoryLoader{Loader: loader, InvFactory: invFactory}),
 	}
 	for _, subCmd := range subCmds {
-		subCmd.PreRunE = preRunE
+		subCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			// The decryptor is only configured with identities, so objects
+			// marked as encrypted fail to read without them.
+			if len(ageIdentityFiles) > 0 {
+				loaderOptions.Decryptor = &manifestreader.AgeDecryptor{IdentityFiles: ageIdentityFiles}
+			}
+			return preRunE(cmd, args)
+		}
 		updateHelp(names, subCmd)
 		cmd.AddCommand(subCmd)
 	}
@@ -93,6 +138,59 @@ func updateHelp(names []string, c *cobra.Command) {
 	}
 }
 
+const (
+	inventoryTypeConfigMap = "configmap"
+	inventoryTypeApplySet  = "applyset"
+)
+
+// inventoryFactory is the inventory.ClientFactory of the --inventory-type
+// flag. The flags are parsed after the commands are created, so the factory
+// is selected when a client is created.
+type inventoryFactory struct {
+	invType     string
+	tooling     string
+	storeStatus bool
+}
+
+var _ inventory.ClientFactory = &inventoryFactory{}
+var _ inventory.InfoWrapper = &inventoryFactory{}
+
+func (f *inventoryFactory) NewClient(factory util.Factory) (inventory.Client, error) {
+	statusPolicy := inventory.StatusPolicyNone
+	if f.storeStatus {
+		statusPolicy = inventory.StatusPolicyAll
+	}
+	switch f.invType {
+	case inventoryTypeConfigMap:
+		return inventory.ClusterClientFactory{StatusPolicy: statusPolicy}.NewClient(factory)
+	case inventoryTypeApplySet:
+		return inventory.ApplySetClientFactory{StatusPolicy: statusPolicy, Tooling: f.tooling}.NewClient(factory)
+	default:
+		return nil, fmt.Errorf("unknown inventory type %q: must be %s or %s",
+			f.invType, inventoryTypeConfigMap, inventoryTypeApplySet)
+	}
+}
+
+func (f *inventoryFactory) WrapInfo(obj *unstructured.Unstructured) inventory.Info {
+	if f.invType == inventoryTypeApplySet {
+		return inventory.WrapApplySetInfoObj(obj)
+	}
+	return inventory.WrapInventoryInfoObj(obj)
+}
+
+// addTransformFlags adds the flags of the common transformations of the
+// objects in the manifests.
+func addTransformFlags(flags *pflag.FlagSet, o *manifestreader.TransformOptions) {
+	flags.StringVar(&o.Namespace, "override-namespace", "",
+		"If set, override the namespace of all namespaced objects, including the inventory object. "+
+			"The references of the depends-on and apply-time-mutation annotations are updated, "+
+			"and the inventory ID is suffixed with the namespace.")
+	flags.StringToStringVar(&o.Labels, "common-labels", nil,
+		"Labels to add to all objects, for example app.kubernetes.io/managed-by=kapply.")
+	flags.StringToStringVar(&o.Annotations, "common-annotations", nil,
+		"Annotations to add to all objects.")
+}
+
 // newConfigFilerPreRunE returns a cobra command PreRunE function that
 // performs a lookup to determine if server-side throttling is enabled. If so,
 // client-side throttling is disabled in the ConfigFlags.
@@ -49,6 +49,8 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		"If true, preview runs in the server instead of the client.")
 	cmd.Flags().BoolVar(&r.serverSideOptions.ForceConflicts, "force-conflicts", false,
 		"If true during server-side preview, do not report field conflicts.")
+	cmd.Flags().StringSliceVar(&r.serverSideOptions.ForceConflictsManagers, "force-conflicts-from", nil,
+		"During server-side preview, do not report field conflicts if all the conflicting fields are owned by these field managers.")
 	cmd.Flags().StringVar(&r.serverSideOptions.FieldManager, "field-manager", common.DefaultFieldManager,
 		"If true during server-side preview, sets field owner.")
 	cmd.Flags().BoolVar(&previewDestroy, "destroy", previewDestroy, "If true, preview of destroy operations will be displayed.")
@@ -57,6 +59,27 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 	cmd.Flags().StringVar(&r.inventoryPolicy, flagutils.InventoryPolicyFlag, flagutils.InventoryPolicyStrict,
 		"It determines the behavior when the resources don't belong to current inventory. Available options "+
 			fmt.Sprintf("%q, %q and %q.", flagutils.InventoryPolicyStrict, flagutils.InventoryPolicyAdopt, flagutils.InventoryPolicyForceAdopt))
+	cmd.Flags().BoolVar(&r.recreateOnImmutable, "recreate-on-immutable", false,
+		"If true during server-side preview, report objects with changed immutable fields as replaced.")
+	cmd.Flags().BoolVar(&r.preflight, "preflight", false,
+		"If true, check the permissions needed to apply, prune and watch the objects, "+
+			"and fail with the list of missing permissions. Ignored with --destroy.")
+	cmd.Flags().IntVar(&r.maxPruneCount, "max-prune-count", 0,
+		"Maximum number of inventory objects to prune in one run. Zero means no limit.")
+	cmd.Flags().IntVar(&r.maxPrunePercent, "max-prune-percent", 0,
+		"Maximum percentage of inventory objects to prune in one run. Zero means no limit.")
+	cmd.Flags().BoolVar(&r.allowMassPrune, "allow-mass-prune", false,
+		"If true, prune even if --max-prune-count or --max-prune-percent is exceeded.")
+	cmd.Flags().StringSliceVar(&r.pruneAllowKinds, "prune-allow-kinds", nil,
+		"If set, only objects of these kinds (Kind.group) are pruned.")
+	cmd.Flags().StringSliceVar(&r.pruneDenyKinds, "prune-deny-kinds", nil,
+		"Objects of these kinds (Kind.group) are never pruned, e.g. Secret,PersistentVolumeClaim,Namespace.")
+	cmd.Flags().StringSliceVar(&r.pruneAllowNamespaces, "prune-allow-namespaces", nil,
+		"If set, only objects in these namespaces are pruned.")
+	cmd.Flags().StringSliceVar(&r.pruneDenyNamespaces, "prune-deny-namespaces", nil,
+		"Objects in these namespaces are never pruned.")
+	cmd.Flags().BoolVar(&r.pruneNonEmptyNamespaces, "prune-non-empty-namespaces", false,
+		"If true, prune (or delete with --destroy) namespaces even if they contain objects not in the inventory.")
 	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
 		"How long to wait before exiting")
 
@@ -78,10 +101,20 @@ type Runner struct {
 	loader     manifestreader.ManifestLoader
 	ioStreams  genericclioptions.IOStreams
 
-	serverSideOptions common.ServerSideOptions
-	output            string
-	inventoryPolicy   string
-	timeout           time.Duration
+	serverSideOptions       common.ServerSideOptions
+	output                  string
+	inventoryPolicy         string
+	timeout                 time.Duration
+	maxPruneCount           int
+	maxPrunePercent         int
+	allowMassPrune          bool
+	pruneNonEmptyNamespaces bool
+	pruneAllowKinds         []string
+	pruneDenyKinds          []string
+	pruneAllowNamespaces    []string
+	pruneDenyNamespaces     []string
+	recreateOnImmutable     bool
+	preflight               bool
 }
 
 // RunE is the function run from the cobra command.
@@ -105,6 +138,11 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	prunePolicy, err := flagutils.ConvertPrunePolicy(r.pruneAllowKinds, r.pruneDenyKinds,
+		r.pruneAllowNamespaces, r.pruneDenyNamespaces)
+	if err != nil {
+		return err
+	}
 
 	reader, err := r.loader.ManifestReader(cmd.InOrStdin(), flagutils.PathFromArgs(args))
 	if err != nil {
@@ -116,6 +154,9 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	}
 
 	objs, err := reader.Read()
+	// The errors of specific objects, like substitution errors, are
+	// reported by the applier, according to the validation policy.
+	objErrs, err := manifestreader.SplitObjectErrors(err)
 	if err != nil {
 		return err
 	}
@@ -124,7 +165,7 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	inv := inventory.WrapInventoryInfoObj(invObj)
+	inv := inventory.WrapInfoObjForClientFactory(r.invFactory, invObj)
 
 	invClient, err := r.invFactory.NewClient(r.factory)
 	if err != nil {
@@ -149,11 +190,19 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 		// Run the applier. It will return a channel where we can receive updates
 		// to keep track of progress and any issues.
 		ch = a.Run(ctx, inv, objs, apply.ApplierOptions{
-			EmitStatusEvents:  false,
-			NoPrune:           noPrune,
-			DryRunStrategy:    drs,
-			ServerSideOptions: r.serverSideOptions,
-			InventoryPolicy:   inventoryPolicy,
+			EmitStatusEvents:        false,
+			NoPrune:                 noPrune,
+			DryRunStrategy:          drs,
+			ServerSideOptions:       r.serverSideOptions,
+			InventoryPolicy:         inventoryPolicy,
+			MaxPruneCount:           r.maxPruneCount,
+			MaxPrunePercent:         r.maxPrunePercent,
+			AllowMassPrune:          r.allowMassPrune,
+			PruneNonEmptyNamespaces: r.pruneNonEmptyNamespaces,
+			PrunePolicy:             prunePolicy,
+			RecreateOnImmutable:     r.recreateOnImmutable,
+			ValidationErrors:        objErrs,
+			Preflight:               r.preflight,
 		})
 	} else {
 		d, err := apply.NewDestroyerBuilder().
@@ -164,8 +213,10 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		ch = d.Run(ctx, inv, apply.DestroyerOptions{
-			InventoryPolicy: inventoryPolicy,
-			DryRunStrategy:  drs,
+			InventoryPolicy:          inventoryPolicy,
+			DryRunStrategy:           drs,
+			DeleteNonEmptyNamespaces: r.pruneNonEmptyNamespaces,
+			PrunePolicy:              prunePolicy,
 		})
 	}
 
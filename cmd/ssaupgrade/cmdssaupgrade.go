@@ -0,0 +1,132 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package ssaupgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/cli-utils/cmd/flagutils"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/manifestreader"
+	"sigs.k8s.io/cli-utils/pkg/ssaupgrade"
+)
+
+// GetRunner creates and returns the Runner which stores the cobra command.
+func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
+	loader manifestreader.ManifestLoader, ioStreams genericclioptions.IOStreams) *Runner {
+	r := &Runner{
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
+		ioStreams:  ioStreams,
+	}
+	cmd := &cobra.Command{
+		Use:                   "ssa-upgrade (DIRECTORY | STDIN)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Migrate the objects in the inventory from client-side apply to server-side apply"),
+		Long: i18n.T(`Migrate the field ownership of every object in the inventory from the
+client-side apply managers to the server-side apply field manager, and
+remove the last-applied-configuration annotation. Run this once when
+switching a package to --server-side, so fields removed from the
+configuration are removed from the objects.`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: r.RunE,
+	}
+
+	cmd.Flags().StringVar(&r.fieldManager, "field-manager", common.DefaultFieldManager,
+		"The client owner of the fields being applied on the server-side.")
+	cmd.Flags().BoolVar(&r.dryRun, "dry-run", false,
+		"If true, only print the objects which would be upgraded.")
+	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
+		"How long to wait before exiting")
+
+	r.Command = cmd
+	return r
+}
+
+// Command creates the Runner, returning the cobra command associated with it.
+func Command(f cmdutil.Factory, invFactory inventory.ClientFactory, loader manifestreader.ManifestLoader,
+	ioStreams genericclioptions.IOStreams) *cobra.Command {
+	return GetRunner(f, invFactory, loader, ioStreams).Command
+}
+
+// Runner encapsulates data necessary to run the ssa-upgrade command.
+type Runner struct {
+	Command    *cobra.Command
+	factory    cmdutil.Factory
+	invFactory inventory.ClientFactory
+	loader     manifestreader.ManifestLoader
+	ioStreams  genericclioptions.IOStreams
+
+	fieldManager string
+	dryRun       bool
+	timeout      time.Duration
+}
+
+// RunE is the function run from the cobra command.
+func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	// If specified, cancel with timeout.
+	if r.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	// Retrieve the inventory object.
+	reader, err := r.loader.ManifestReader(cmd.InOrStdin(), flagutils.PathFromArgs(args))
+	if err != nil {
+		return err
+	}
+	objs, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	invObj, _, err := inventory.SplitUnstructureds(objs)
+	if err != nil {
+		return err
+	}
+	inv := inventory.WrapInfoObjForClientFactory(r.invFactory, invObj)
+
+	invClient, err := r.invFactory.NewClient(r.factory)
+	if err != nil {
+		return err
+	}
+	upgrader, err := ssaupgrade.NewUpgrader(r.factory, invClient)
+	if err != nil {
+		return err
+	}
+
+	dryRunStrategy := common.DryRunNone
+	suffix := ""
+	if r.dryRun {
+		dryRunStrategy = common.DryRunClient
+		suffix = " (dry-run)"
+	}
+	results, err := upgrader.Upgrade(ctx, inv, r.fieldManager, dryRunStrategy)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		id := fmt.Sprintf("%s/%s", strings.ToLower(result.Identifier.GroupKind.String()), result.Identifier.Name)
+		if result.Error != nil {
+			fmt.Fprintf(r.ioStreams.Out, "%s upgrade failed: %v\n", id, result.Error)
+			continue
+		}
+		fmt.Fprintf(r.ioStreams.Out, "%s %s%s\n", id, strings.ToLower(string(result.Status)), suffix)
+	}
+	if count := results.ErrorCount(); count > 0 {
+		return fmt.Errorf("failed to upgrade %d object(s)", count)
+	}
+	return nil
+}
@@ -344,6 +344,9 @@ type Loader interface {
 
 type InventoryLoader struct {
 	Loader manifestreader.ManifestLoader
+	// InvFactory selects the Info wrapper of the inventory object. Defaults
+	// to the ConfigMap wrapper.
+	InvFactory inventory.ClientFactory
 }
 
 func NewInventoryLoader(loader manifestreader.ManifestLoader) *InventoryLoader {
@@ -371,6 +374,6 @@ func (ir *InventoryLoader) GetInvInfo(cmd *cobra.Command, args []string) (invent
 	if err != nil {
 		return nil, err
 	}
-	inv := inventory.WrapInventoryInfoObj(invObj)
+	inv := inventory.WrapInfoObjForClientFactory(ir.InvFactory, invObj)
 	return inv, nil
 }
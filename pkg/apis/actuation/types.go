@@ -77,6 +77,23 @@ type ObjectStatus struct {
 	// Generation is not available for deleted objects.
 	// +optional
 	Generation int64 `json:"generation,omitempty"`
+	// AppliedHash is a fingerprint of the configuration last applied,
+	// before apply-time mutations.
+	// This can help identify if the local configuration has changed since
+	// the last apply.
+	// AppliedHash is not available for deleted objects.
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+	// ResourceVersion is the last known ResourceVersion (after apply).
+	// This can help identify if the object has been modified out-of-band
+	// since the last apply.
+	// ResourceVersion is not available for deleted objects.
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Replaced is true if the object was deleted and re-created by the last
+	// apply, because the apply changed an immutable field.
+	// +optional
+	Replaced bool `json:"replaced,omitempty"`
 }
 
 //nolint:revive // consistent prefix improves tab-completion for enums
@@ -16,6 +16,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
@@ -23,11 +24,13 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
 	"sigs.k8s.io/cli-utils/pkg/apply/solver"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/apply/verify"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/cli-utils/pkg/redact"
 )
 
 // Applier performs the step of applying a set of resources into a cluster,
@@ -46,8 +49,10 @@ type Applier struct {
 	invClient     inventory.Client
 	client        dynamic.Interface
 	openAPIGetter discovery.OpenAPISchemaInterface
+	discoClient   discovery.ServerResourcesInterface
 	mapper        meta.RESTMapper
 	infoHelper    info.Helper
+	verifier      *verify.Verifier
 }
 
 // prepareObjects returns the set of objects to apply and to prune or
@@ -78,7 +83,7 @@ func (a *Applier) prepareObjects(localInv inventory.Info, localObjs object.Unstr
 		}
 		if len(prevInvObjs) == 1 {
 			invObj := prevInvObjs[0]
-			val := invObj.GetLabels()[common.InventoryLabel]
+			val := inventory.ClusterInventoryID(invObj)
 			if val != localInv.ID() {
 				return nil, nil, fmt.Errorf("inventory-id of inventory object in cluster doesn't match provided id %q", localInv.ID())
 			}
@@ -90,9 +95,39 @@ func (a *Applier) prepareObjects(localInv inventory.Info, localObjs object.Unstr
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := a.checkPruneLimit(localInv, pruneObjs, o); err != nil {
+		return nil, nil, err
+	}
 	return localObjs, pruneObjs, nil
 }
 
+// checkPruneLimit returns a PruneLimitError if the number of objects to
+// prune exceeds the MaxPruneCount or MaxPrunePercent of the options.
+func (a *Applier) checkPruneLimit(localInv inventory.Info, pruneObjs object.UnstructuredSet, o ApplierOptions) error {
+	if o.NoPrune || o.AllowMassPrune || len(pruneObjs) == 0 {
+		return nil
+	}
+	if o.MaxPruneCount <= 0 && o.MaxPrunePercent <= 0 {
+		return nil
+	}
+	invIDs, err := a.invClient.GetClusterObjs(localInv)
+	if err != nil {
+		return err
+	}
+	pruneCount := len(pruneObjs)
+	invCount := len(invIDs)
+	if (o.MaxPruneCount > 0 && pruneCount > o.MaxPruneCount) ||
+		(o.MaxPrunePercent > 0 && pruneCount*100 > o.MaxPrunePercent*invCount) {
+		return &applyerror.PruneLimitError{
+			PruneCount:     pruneCount,
+			InventoryCount: invCount,
+			MaxCount:       o.MaxPruneCount,
+			MaxPercent:     o.MaxPrunePercent,
+		}
+	}
+	return nil
+}
+
 // Run performs the Apply step. This happens asynchronously with updates
 // on progress and any errors reported back on the event channel.
 // Cancelling the operation or setting timeout on how long to Wait
@@ -107,12 +142,24 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 	setDefaults(&options)
 	go func() {
 		defer close(eventChannel)
+		// Read the namespaces and kinds the objects are restricted to, if
+		// declared by the inventory object.
+		scope, err := inventory.ReadScopeFromInfo(invInfo)
+		if err != nil {
+			handleError(eventChannel, err)
+			return
+		}
+
 		// Validate the resources to make sure we catch those problems early
 		// before anything has been updated in the cluster.
 		vCollector := &validation.Collector{}
+		for _, err := range options.ValidationErrors {
+			vCollector.Collect(err)
+		}
 		validator := &validation.Validator{
 			Collector: vCollector,
 			Mapper:    a.mapper,
+			Scope:     scope,
 		}
 		validator.Validate(objects)
 
@@ -147,6 +194,13 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 		// Build list of prune validation filters.
 		pruneFilters := []filter.ValidationFilter{
 			filter.PreventRemoveFilter{},
+			filter.CRDInUseFilter{
+				Client:      a.client,
+				TaskContext: taskContext,
+			},
+			filter.PrunePolicyFilter{
+				Policy: options.PrunePolicy,
+			},
 			filter.InventoryPolicyPruneFilter{
 				Inv:       invInfo,
 				InvPolicy: options.InventoryPolicy,
@@ -160,6 +214,18 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 				DryRunStrategy:    options.DryRunStrategy,
 			},
 		}
+		if !scope.IsEmpty() {
+			pruneFilters = append(pruneFilters, filter.ScopeFilter{
+				Scope: scope,
+			})
+		}
+		if !options.PruneNonEmptyNamespaces {
+			pruneFilters = append(pruneFilters, &filter.NonEmptyNamespaceFilter{
+				Client:          a.client,
+				DiscoveryClient: a.discoClient,
+				Inv:             invInfo,
+			})
+		}
 		// Build list of apply mutators.
 		applyMutators := []mutator.Interface{
 			&mutator.ApplyTimeMutator{
@@ -179,16 +245,21 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 			ApplyFilters:  applyFilters,
 			ApplyMutators: applyMutators,
 			PruneFilters:  pruneFilters,
+			Verifier:      a.verifier,
 		}
 		opts := solver.Options{
-			ServerSideOptions:      options.ServerSideOptions,
-			ReconcileTimeout:       options.ReconcileTimeout,
-			Destroy:                false,
-			Prune:                  !options.NoPrune,
-			DryRunStrategy:         options.DryRunStrategy,
-			PrunePropagationPolicy: options.PrunePropagationPolicy,
-			PruneTimeout:           options.PruneTimeout,
-			InventoryPolicy:        options.InventoryPolicy,
+			ServerSideOptions:          options.ServerSideOptions,
+			ReconcileTimeout:           options.ReconcileTimeout,
+			Destroy:                    false,
+			Prune:                      !options.NoPrune,
+			DryRunStrategy:             options.DryRunStrategy,
+			PrunePropagationPolicy:     options.PrunePropagationPolicy,
+			PruneTimeout:               options.PruneTimeout,
+			InventoryPolicy:            options.InventoryPolicy,
+			ForceRemoveFinalizersAfter: options.ForceRemoveFinalizersAfter,
+			RecreateOnImmutable:        options.RecreateOnImmutable,
+			WaitProgressInterval:       options.WaitProgressInterval,
+			VerifyTimeout:              options.VerifyTimeout,
 		}
 
 		// Build the ordered set of tasks to execute.
@@ -223,17 +294,43 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 			taskContext.AddInvalidObject(id)
 		}
 
+		// The status watcher watches all the apply objects and prune
+		// candidates.
+		allIds := object.UnstructuredSetToObjMetadataSet(append(applyObjs, pruneObjs...))
+
+		// Check the permissions needed by the tasks, before any task runs.
+		if options.Preflight {
+			var preflightPruneObjs object.UnstructuredSet
+			if !options.NoPrune {
+				preflightPruneObjs = vCollector.FilterInvalidObjects(pruneObjs)
+			}
+			err = a.checkPermissions(ctx, invInfo, vCollector.FilterInvalidObjects(applyObjs),
+				preflightPruneObjs, allIds, pruneFilters, options)
+			if err != nil {
+				handleError(eventChannel, err)
+				return
+			}
+		}
+
+		// Ask the caller to confirm the plan, if requested.
+		actionGroups := taskQueue.ToActionGroups()
+		if !confirmPlan(ctx, eventChannel, options.ConfirmFunc, Plan{
+			ActionGroups: actionGroups,
+			Skipped:      vCollector.InvalidIds,
+		}) {
+			return
+		}
+
 		// Send event to inform the caller about the resources that
 		// will be applied/pruned.
 		eventChannel <- event.Event{
 			Type: event.InitType,
 			InitEvent: event.InitEvent{
-				ActionGroups: taskQueue.ToActionGroups(),
+				ActionGroups: actionGroups,
 			},
 		}
 		// Create a new TaskStatusRunner to execute the taskQueue.
 		klog.V(4).Infoln("applier building TaskStatusRunner...")
-		allIds := object.UnstructuredSetToObjMetadataSet(append(applyObjs, pruneObjs...))
 		statusWatcher := a.statusWatcher
 		// Disable watcher for dry runs
 		if opts.DryRunStrategy.ClientOrServerDryRun() {
@@ -244,13 +341,17 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 		err = runner.Run(ctx, taskContext, taskQueue.ToChannel(), taskrunner.Options{
 			EmitStatusEvents:         options.EmitStatusEvents,
 			WatcherRESTScopeStrategy: options.WatcherRESTScopeStrategy,
+			ErrorPolicy:              options.ErrorPolicy,
 		})
 		if err != nil {
 			handleError(eventChannel, err)
 			return
 		}
 	}()
-	return eventChannel
+	if options.NoRedact {
+		return eventChannel
+	}
+	return redact.Events(eventChannel)
 }
 
 type ApplierOptions struct {
@@ -290,9 +391,81 @@ type ApplierOptions struct {
 	// ValidationPolicy defines how to handle invalid objects.
 	ValidationPolicy validation.Policy
 
+	// ValidationErrors are the errors about the objects which were found
+	// before the run, like the errors returned by the manifest readers.
+	// They are handled like the errors of the validation of the objects,
+	// according to the ValidationPolicy.
+	ValidationErrors []error
+
 	// RESTScopeStrategy specifies which strategy to use when listing and
 	// watching resources. By default, the strategy is selected automatically.
 	WatcherRESTScopeStrategy watcher.RESTScopeStrategy
+
+	// MaxPruneCount is the maximum number of inventory objects that may be
+	// pruned in one run. If exceeded, the run is aborted before any task
+	// runs. Zero means no limit.
+	MaxPruneCount int
+
+	// MaxPrunePercent is the maximum percentage of inventory objects that
+	// may be pruned in one run. If exceeded, the run is aborted before any
+	// task runs. Zero means no limit.
+	MaxPrunePercent int
+
+	// AllowMassPrune disables the MaxPruneCount and MaxPrunePercent limits.
+	AllowMassPrune bool
+
+	// ForceRemoveFinalizersAfter defines whether the finalizers of pruned
+	// objects should be removed, and if so, how long the objects may be
+	// terminating before their finalizers are removed. Removing finalizers
+	// skips the cleanup of the controllers that added them.
+	ForceRemoveFinalizersAfter time.Duration
+
+	// PrunePolicy restricts which objects may be pruned by GroupKind and
+	// namespace. Objects not allowed by the policy are skipped. By default,
+	// all objects may be pruned.
+	PrunePolicy filter.PrunePolicy
+
+	// PruneNonEmptyNamespaces allows pruning namespaces which contain
+	// objects not tracked by the inventory. By default, such namespaces
+	// are skipped, because deleting them would delete those objects too.
+	PruneNonEmptyNamespaces bool
+
+	// RecreateOnImmutable deletes and re-creates objects when the apply
+	// fails because an immutable field changed, e.g. a Job template or a
+	// Deployment selector. Objects may also opt in individually with the
+	// common.RecreateOnImmutableAnnotation.
+	RecreateOnImmutable bool
+
+	// WaitProgressInterval defines whether progress events with the latest
+	// status message of the objects which have not reconciled yet should be
+	// emitted while waiting, and if so, how often. Zero means never.
+	WaitProgressInterval time.Duration
+
+	// ErrorPolicy defines whether to keep going after the first actuation
+	// or reconcile failure. With taskrunner.AbortOnError, the remaining
+	// tasks are skipped, except for the inventory update.
+	ErrorPolicy taskrunner.ErrorPolicy
+
+	// VerifyTimeout is the timeout of each verification check declared by
+	// the applied objects with the common.VerifyAnnotation. The checks are
+	// retried until they pass or the timeout expires. Zero means
+	// verify.DefaultTimeout.
+	VerifyTimeout time.Duration
+
+	// NoRedact disables the redaction of the sensitive values of the
+	// objects included in the events, like the data of Secrets.
+	NoRedact bool
+
+	// Preflight enables a check of the permissions needed to apply and prune
+	// the objects, update the inventory and watch the status of the objects,
+	// before any task runs. If any permission is missing, the run fails with
+	// a preflight.MissingPermissionsError listing all of them. The
+	// permissions needed by a real run are checked, even for dry runs.
+	Preflight bool
+
+	// ConfirmFunc, if set, is called with the plan before any task runs.
+	// The run is aborted unless the plan is confirmed.
+	ConfirmFunc ConfirmFunc
 }
 
 // setDefaults set the options to the default values if they
@@ -4,14 +4,18 @@
 package apply
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/apply/verify"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 )
@@ -32,6 +36,10 @@ func (b *ApplierBuilder) Build() (*Applier, error) {
 	if err != nil {
 		return nil, err
 	}
+	coreClient, err := corev1client.NewForConfig(bx.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error getting core client: %v", err)
+	}
 	return &Applier{
 		pruner: &prune.Pruner{
 			InvClient: bx.invClient,
@@ -42,8 +50,14 @@ func (b *ApplierBuilder) Build() (*Applier, error) {
 		invClient:     bx.invClient,
 		client:        bx.client,
 		openAPIGetter: bx.discoClient,
+		discoClient:   bx.discoClient,
 		mapper:        bx.mapper,
 		infoHelper:    info.NewHelper(bx.mapper, bx.unstructuredClientForMapping),
+		verifier: &verify.Verifier{
+			Client:     bx.client,
+			Mapper:     bx.mapper,
+			CoreClient: coreClient,
+		},
 	}, nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -1954,6 +1955,11 @@ func TestReadAndPrepareObjects(t *testing.T) {
 	obj1 := testutil.Unstructured(t, resources["obj1"])
 	obj2 := testutil.Unstructured(t, resources["obj2"])
 	clusterScopedObj := testutil.Unstructured(t, resources["clusterScopedObj"])
+	// Objects which are never applied, so they are not annotated
+	pruneObj1 := testutil.Unstructured(t, resources["obj1"])
+	pruneObj2 := testutil.Unstructured(t, resources["obj2"])
+	appliedObj1 := testutil.Unstructured(t, resources["obj1"])
+	appliedObj2 := testutil.Unstructured(t, resources["obj2"])
 
 	testCases := map[string]struct {
 		// objects in the cluster
@@ -1966,8 +1972,12 @@ func TestReadAndPrepareObjects(t *testing.T) {
 		applyObjs object.UnstructuredSet
 		// expected objects to prune
 		pruneObjs object.UnstructuredSet
+		// applier options
+		options ApplierOptions
 		// expected error
 		isError bool
+		// expected error, if not nil
+		expectedErr error
 	}{
 		"objects include inventory": {
 			invInfo: inventoryInfo{
@@ -2038,6 +2048,77 @@ func TestReadAndPrepareObjects(t *testing.T) {
 			applyObjs: object.UnstructuredSet{obj1, obj2, clusterScopedObj},
 			pruneObjs: object.UnstructuredSet{},
 		},
+		"prune count above limit": {
+			clusterObjs: object.UnstructuredSet{pruneObj1, pruneObj2},
+			invInfo: inventoryInfo{
+				name:      inventory.Name(),
+				namespace: inventory.Namespace(),
+				id:        inventory.ID(),
+				set: object.ObjMetadataSet{
+					object.UnstructuredToObjMetadata(pruneObj1),
+					object.UnstructuredToObjMetadata(pruneObj2),
+				},
+			},
+			options: ApplierOptions{MaxPruneCount: 1},
+			isError: true,
+			expectedErr: &applyerror.PruneLimitError{
+				PruneCount:     2,
+				InventoryCount: 2,
+				MaxCount:       1,
+			},
+		},
+		"prune percentage above limit": {
+			clusterObjs: object.UnstructuredSet{pruneObj2},
+			invInfo: inventoryInfo{
+				name:      inventory.Name(),
+				namespace: inventory.Namespace(),
+				id:        inventory.ID(),
+				set: object.ObjMetadataSet{
+					object.UnstructuredToObjMetadata(pruneObj1),
+					object.UnstructuredToObjMetadata(pruneObj2),
+				},
+			},
+			resources: object.UnstructuredSet{appliedObj1},
+			options:   ApplierOptions{MaxPrunePercent: 49},
+			isError:   true,
+			expectedErr: &applyerror.PruneLimitError{
+				PruneCount:     1,
+				InventoryCount: 2,
+				MaxPercent:     49,
+			},
+		},
+		"prune percentage at limit": {
+			clusterObjs: object.UnstructuredSet{pruneObj2},
+			invInfo: inventoryInfo{
+				name:      inventory.Name(),
+				namespace: inventory.Namespace(),
+				id:        inventory.ID(),
+				set: object.ObjMetadataSet{
+					object.UnstructuredToObjMetadata(pruneObj1),
+					object.UnstructuredToObjMetadata(pruneObj2),
+				},
+			},
+			resources: object.UnstructuredSet{appliedObj1},
+			options:   ApplierOptions{MaxPrunePercent: 50},
+			applyObjs: object.UnstructuredSet{appliedObj1},
+			pruneObjs: object.UnstructuredSet{pruneObj2},
+		},
+		"prune above limit with override": {
+			clusterObjs: object.UnstructuredSet{pruneObj1},
+			invInfo: inventoryInfo{
+				name:      inventory.Name(),
+				namespace: inventory.Namespace(),
+				id:        inventory.ID(),
+				set: object.ObjMetadataSet{
+					object.UnstructuredToObjMetadata(pruneObj1),
+					object.UnstructuredToObjMetadata(pruneObj2),
+				},
+			},
+			resources: object.UnstructuredSet{appliedObj2},
+			options:   ApplierOptions{MaxPrunePercent: 10, AllowMassPrune: true},
+			applyObjs: object.UnstructuredSet{appliedObj2},
+			pruneObjs: object.UnstructuredSet{pruneObj1},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -2050,9 +2131,12 @@ func TestReadAndPrepareObjects(t *testing.T) {
 				watcher.BlindStatusWatcher{},
 			)
 
-			applyObjs, pruneObjs, err := applier.prepareObjects(tc.invInfo.toWrapped(), tc.resources, ApplierOptions{})
+			applyObjs, pruneObjs, err := applier.prepareObjects(tc.invInfo.toWrapped(), tc.resources, tc.options)
 			if tc.isError {
 				assert.Error(t, err)
+				if tc.expectedErr != nil {
+					assert.Equal(t, tc.expectedErr, err)
+				}
 				return
 			}
 			require.NoError(t, err)
@@ -0,0 +1,63 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"errors"
+
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// ErrNotConfirmed is the error sent in the error event when the ConfirmFunc
+// declines the plan.
+var ErrNotConfirmed = errors.New("run aborted: plan was not confirmed")
+
+// Plan describes the actions of an applier or destroyer run. It is passed
+// to the ConfirmFunc after the task queue is built and before any task runs.
+type Plan struct {
+	// ActionGroups are the action groups of the task queue, the same as
+	// sent in the InitEvent.
+	ActionGroups []event.ActionGroup
+	// Skipped are the invalid objects, which will not be actuated.
+	Skipped object.ObjMetadataSet
+}
+
+// Objects returns the objects of all the action groups with the passed
+// action.
+func (p Plan) Objects(action event.ResourceAction) object.ObjMetadataSet {
+	ids := object.ObjMetadataSet{}
+	for _, ag := range p.ActionGroups {
+		if ag.Action == action {
+			ids = ids.Union(ag.Identifiers)
+		}
+	}
+	return ids
+}
+
+// ConfirmFunc is called with the context and the Plan of a run before any
+// task runs. If it returns false, the run is aborted with ErrNotConfirmed. If
+// it returns an error, the run is aborted with the error. It should return
+// when the context is done.
+type ConfirmFunc func(context.Context, Plan) (bool, error)
+
+// confirmPlan calls the passed ConfirmFunc, if not nil, and sends an error
+// event if the plan was not confirmed. Returns true if the run should
+// continue.
+func confirmPlan(ctx context.Context, eventChannel chan event.Event, confirm ConfirmFunc, plan Plan) bool {
+	if confirm == nil {
+		return true
+	}
+	ok, err := confirm(ctx, plan)
+	if err != nil {
+		handleError(eventChannel, err)
+		return false
+	}
+	if !ok {
+		handleError(eventChannel, ErrNotConfirmed)
+		return false
+	}
+	return true
+}
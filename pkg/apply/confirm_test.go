@@ -0,0 +1,104 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestPlanObjects(t *testing.T) {
+	obj1 := object.ObjMetadata{Name: "obj1"}
+	obj2 := object.ObjMetadata{Name: "obj2"}
+	obj3 := object.ObjMetadata{Name: "obj3"}
+	plan := Plan{
+		ActionGroups: []event.ActionGroup{
+			{Name: "apply-0", Action: event.ApplyAction, Identifiers: object.ObjMetadataSet{obj1}},
+			{Name: "wait-0", Action: event.WaitAction, Identifiers: object.ObjMetadataSet{obj1}},
+			{Name: "apply-1", Action: event.ApplyAction, Identifiers: object.ObjMetadataSet{obj2}},
+			{Name: "prune-0", Action: event.PruneAction, Identifiers: object.ObjMetadataSet{obj3}},
+		},
+	}
+	assert.Equal(t, object.ObjMetadataSet{obj1, obj2}, plan.Objects(event.ApplyAction))
+	assert.Equal(t, object.ObjMetadataSet{obj3}, plan.Objects(event.PruneAction))
+	assert.Equal(t, object.ObjMetadataSet{}, plan.Objects(event.DeleteAction))
+}
+
+func TestApplierConfirm(t *testing.T) {
+	confirmErr := errors.New("confirm error")
+
+	tests := map[string]struct {
+		confirmed     bool
+		confirmErr    error
+		expectedInit  bool
+		expectedError error
+	}{
+		"confirmed": {
+			confirmed:    true,
+			expectedInit: true,
+		},
+		"not confirmed": {
+			confirmed:     false,
+			expectedError: ErrNotConfirmed,
+		},
+		"confirm error": {
+			confirmErr:    confirmErr,
+			expectedError: confirmErr,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			deployment := testutil.Unstructured(t, resources["deployment"])
+			invInfo := inventoryInfo{
+				name:      "abc-123",
+				namespace: "default",
+				id:        "test",
+			}
+			applier := newTestApplier(t, invInfo, object.UnstructuredSet{deployment},
+				object.UnstructuredSet{}, watcher.BlindStatusWatcher{})
+
+			var plan Plan
+			eventChannel := applier.Run(context.TODO(), invInfo.toWrapped(), object.UnstructuredSet{deployment}, ApplierOptions{
+				NoPrune:         true,
+				DryRunStrategy:  common.DryRunClient,
+				InventoryPolicy: inventory.PolicyMustMatch,
+				ConfirmFunc: func(_ context.Context, p Plan) (bool, error) {
+					plan = p
+					return tc.confirmed, tc.confirmErr
+				},
+			})
+
+			var foundInit bool
+			var errs []error
+			for e := range eventChannel {
+				switch e.Type {
+				case event.InitType:
+					foundInit = true
+				case event.ErrorType:
+					errs = append(errs, e.ErrorEvent.Err)
+				}
+			}
+
+			require.NotEmpty(t, plan.ActionGroups)
+			assert.Equal(t, object.ObjMetadataSet{object.UnstructuredToObjMetadata(deployment)},
+				plan.Objects(event.ApplyAction))
+			assert.Equal(t, tc.expectedInit, foundInit)
+			if tc.expectedError != nil {
+				assert.Equal(t, []error{tc.expectedError}, errs)
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
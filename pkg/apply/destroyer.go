@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/cli-utils/pkg/redact"
 )
 
 // Destroyer performs the step of grabbing all the previous inventory objects and
@@ -37,6 +38,7 @@ type Destroyer struct {
 	mapper        meta.RESTMapper
 	client        dynamic.Interface
 	openAPIGetter discovery.OpenAPISchemaInterface
+	discoClient   discovery.ServerResourcesInterface
 	infoHelper    info.Helper
 }
 
@@ -63,6 +65,34 @@ type DestroyerOptions struct {
 
 	// ValidationPolicy defines how to handle invalid objects.
 	ValidationPolicy validation.Policy
+
+	// ForceRemoveFinalizersAfter defines whether the finalizers of deleted
+	// objects should be removed, and if so, how long the objects may be
+	// terminating before their finalizers are removed. Removing finalizers
+	// skips the cleanup of the controllers that added them.
+	ForceRemoveFinalizersAfter time.Duration
+
+	// DeleteNonEmptyNamespaces allows deleting namespaces which contain
+	// objects not tracked by the inventory. By default, such namespaces
+	// are skipped, because deleting them would delete those objects too.
+	DeleteNonEmptyNamespaces bool
+
+	// PrunePolicy restricts which objects may be deleted by GroupKind and
+	// namespace.
+	PrunePolicy filter.PrunePolicy
+
+	// WaitProgressInterval defines whether progress events with the latest
+	// status message of the objects which have not been deleted yet should
+	// be emitted while waiting, and if so, how often. Zero means never.
+	WaitProgressInterval time.Duration
+
+	// NoRedact disables the redaction of the sensitive values of the
+	// objects included in the events, like the data of Secrets.
+	NoRedact bool
+
+	// ConfirmFunc, if set, is called with the plan before any task runs.
+	// The run is aborted unless the plan is confirmed.
+	ConfirmFunc ConfirmFunc
 }
 
 func setDestroyerDefaults(o *DestroyerOptions) {
@@ -79,6 +109,14 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 	setDestroyerDefaults(&options)
 	go func() {
 		defer close(eventChannel)
+		// Read the namespaces and kinds the objects are restricted to, if
+		// declared by the inventory object.
+		scope, err := inventory.ReadScopeFromInfo(invInfo)
+		if err != nil {
+			handleError(eventChannel, err)
+			return
+		}
+
 		// Retrieve the objects to be deleted from the cluster. Second parameter is empty
 		// because no local objects returns all inventory objects for deletion.
 		emptyLocalObjs := object.UnstructuredSet{}
@@ -106,6 +144,13 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 		klog.V(4).Infoln("destroyer building task queue...")
 		deleteFilters := []filter.ValidationFilter{
 			filter.PreventRemoveFilter{},
+			filter.CRDInUseFilter{
+				Client:      d.client,
+				TaskContext: taskContext,
+			},
+			filter.PrunePolicyFilter{
+				Policy: options.PrunePolicy,
+			},
 			filter.InventoryPolicyPruneFilter{
 				Inv:       invInfo,
 				InvPolicy: options.InventoryPolicy,
@@ -116,6 +161,18 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 				DryRunStrategy:    options.DryRunStrategy,
 			},
 		}
+		if !scope.IsEmpty() {
+			deleteFilters = append(deleteFilters, filter.ScopeFilter{
+				Scope: scope,
+			})
+		}
+		if !options.DeleteNonEmptyNamespaces {
+			deleteFilters = append(deleteFilters, &filter.NonEmptyNamespaceFilter{
+				Client:          d.client,
+				DiscoveryClient: d.discoClient,
+				Inv:             invInfo,
+			})
+		}
 		taskBuilder := &solver.TaskQueueBuilder{
 			Pruner:        d.pruner,
 			DynamicClient: d.client,
@@ -127,12 +184,14 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			PruneFilters:  deleteFilters,
 		}
 		opts := solver.Options{
-			Destroy:                true,
-			Prune:                  true,
-			DryRunStrategy:         options.DryRunStrategy,
-			PrunePropagationPolicy: options.DeletePropagationPolicy,
-			PruneTimeout:           options.DeleteTimeout,
-			InventoryPolicy:        options.InventoryPolicy,
+			Destroy:                    true,
+			Prune:                      true,
+			DryRunStrategy:             options.DryRunStrategy,
+			PrunePropagationPolicy:     options.DeletePropagationPolicy,
+			PruneTimeout:               options.DeleteTimeout,
+			InventoryPolicy:            options.InventoryPolicy,
+			ForceRemoveFinalizersAfter: options.ForceRemoveFinalizersAfter,
+			WaitProgressInterval:       options.WaitProgressInterval,
 		}
 
 		// Build the ordered set of tasks to execute.
@@ -166,12 +225,21 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			taskContext.AddInvalidObject(id)
 		}
 
+		// Ask the caller to confirm the plan, if requested.
+		actionGroups := taskQueue.ToActionGroups()
+		if !confirmPlan(ctx, eventChannel, options.ConfirmFunc, Plan{
+			ActionGroups: actionGroups,
+			Skipped:      vCollector.InvalidIds,
+		}) {
+			return
+		}
+
 		// Send event to inform the caller about the resources that
 		// will be pruned.
 		eventChannel <- event.Event{
 			Type: event.InitType,
 			InitEvent: event.InitEvent{
-				ActionGroups: taskQueue.ToActionGroups(),
+				ActionGroups: actionGroups,
 			},
 		}
 		// Create a new TaskStatusRunner to execute the taskQueue.
@@ -192,5 +260,8 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			return
 		}
 	}()
-	return eventChannel
+	if options.NoRedact {
+		return eventChannel
+	}
+	return redact.Events(eventChannel)
 }
@@ -43,6 +43,7 @@ func (b *DestroyerBuilder) Build() (*Destroyer, error) {
 		mapper:        bx.mapper,
 		client:        bx.client,
 		openAPIGetter: bx.discoClient,
+		discoClient:   bx.discoClient,
 		infoHelper:    info.NewHelper(bx.mapper, bx.unstructuredClientForMapping),
 	}, nil
 }
@@ -10,7 +10,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -393,3 +396,57 @@ func TestDestroyerCancel(t *testing.T) {
 		})
 	}
 }
+
+func TestDestroyerPrunePolicy(t *testing.T) {
+	invInfo := inventoryInfo{
+		name:      "abc-123",
+		namespace: "test",
+		id:        "test",
+		set: object.ObjMetadataSet{
+			testutil.ToIdentifier(t, resources["deployment"]),
+		},
+	}
+	clusterObjs := object.UnstructuredSet{
+		testutil.Unstructured(t, resources["deployment"], testutil.AddOwningInv(t, "test")),
+	}
+	statusWatcher := newFakeWatcher(nil)
+	statusWatcher.Start()
+	destroyer := newTestDestroyer(t,
+		invInfo,
+		append(clusterObjs, inventory.InvInfoToConfigMap(invInfo.toWrapped())),
+		statusWatcher,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var events []event.Event
+	for e := range destroyer.Run(ctx, invInfo.toWrapped(), DestroyerOptions{
+		PrunePolicy: filter.PrunePolicy{
+			DenyGroupKinds: []schema.GroupKind{{Group: "apps", Kind: "Deployment"}},
+		},
+	}) {
+		events = append(events, e)
+	}
+	require.NoError(t, ctx.Err())
+
+	var deleteEvents []testutil.ExpEvent
+	for _, e := range testutil.EventsToExpEvents(events) {
+		if e.EventType == event.DeleteType {
+			deleteEvents = append(deleteEvents, e)
+		}
+	}
+	testutil.AssertEqual(t, []testutil.ExpEvent{
+		{
+			EventType: event.DeleteType,
+			DeleteEvent: &testutil.ExpDeleteEvent{
+				GroupName:  "prune-0",
+				Status:     event.DeleteSkipped,
+				Identifier: testutil.ToIdentifier(t, resources["deployment"]),
+				Error: testutil.EqualError(&filter.PrunePolicyPreventedDeletionError{
+					Reason: `kind "Deployment.apps" in denylist`,
+				}),
+			},
+		},
+	}, deleteEvents)
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/cli-utils/pkg/common"
+)
+
+// ApplyStrategySkippedError is the error returned when the apply of an
+// object is skipped because of its apply strategy, e.g. a create-only
+// object which already exists.
+type ApplyStrategySkippedError struct {
+	Strategy common.ApplyStrategy
+	Reason   string
+}
+
+func (e *ApplyStrategySkippedError) Error() string {
+	return fmt.Sprintf("apply strategy %q prevents apply: %s", e.Strategy, e.Reason)
+}
+
+func (e *ApplyStrategySkippedError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*ApplyStrategySkippedError)
+	if !ok {
+		return false
+	}
+	return e.Strategy == tErr.Strategy &&
+		e.Reason == tErr.Reason
+}
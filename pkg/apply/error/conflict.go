@@ -0,0 +1,138 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldConflict is a field which could not be applied with server-side
+// apply, because it is owned by another field manager.
+type FieldConflict struct {
+	// Field is the path of the conflicting field, e.g. ".spec.replicas".
+	Field string
+	// Manager is the name of the field manager which owns the field.
+	Manager string
+}
+
+// ApplyConflictError is the error returned when a server-side apply fails
+// because of one or more field conflicts.
+type ApplyConflictError struct {
+	Conflicts []FieldConflict
+	err       error
+}
+
+func (e *ApplyConflictError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "apply failed with %d conflict", len(e.Conflicts))
+	if len(e.Conflicts) != 1 {
+		sb.WriteString("s")
+	}
+	sb.WriteString(": ")
+	for i, c := range e.Conflicts {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s (manager: %q)", c.Field, c.Manager)
+	}
+	return sb.String()
+}
+
+func (e *ApplyConflictError) Unwrap() error {
+	return e.err
+}
+
+// Managers returns the sorted, unique field managers of the conflicts.
+func (e *ApplyConflictError) Managers() []string {
+	managerSet := map[string]struct{}{}
+	for _, c := range e.Conflicts {
+		managerSet[c.Manager] = struct{}{}
+	}
+	managers := make([]string, 0, len(managerSet))
+	for manager := range managerSet {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+var (
+	// conflictManagerRegex matches the quoted manager name in a conflict
+	// message, e.g. `conflict with "kubectl-edit" using apps/v1`.
+	conflictManagerRegex = regexp.MustCompile(`conflicts? with "((?:[^"\\]|\\.)*)"`)
+	// singleConflictRegex matches the message of an apply with one conflict,
+	// e.g. `Apply failed with 1 conflict: conflict with "kubectl-edit" using apps/v1: .spec.replicas`.
+	singleConflictRegex = regexp.MustCompile(`Apply failed with 1 conflict: conflict with "((?:[^"\\]|\\.)*)"[^:]*: (\S+)`)
+	// conflictFieldRegex matches a field listed in the message of an apply
+	// with more than one conflict, e.g. `- .spec.replicas`.
+	conflictFieldRegex = regexp.MustCompile(`^- (\S+)`)
+)
+
+// NewApplyConflictError returns an ApplyConflictError if the passed error
+// is a server-side apply conflict, otherwise nil. The conflicts are read from
+// the status causes if the error wraps an APIStatus. Otherwise they are
+// parsed from the error message, because kubectl does not wrap the error.
+func NewApplyConflictError(err error) *ApplyConflictError {
+	if err == nil {
+		return nil
+	}
+	var conflicts []FieldConflict
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Details != nil {
+		if statusErr.Status().Reason != metav1.StatusReasonConflict {
+			return nil
+		}
+		for _, cause := range statusErr.Status().Details.Causes {
+			if cause.Type != metav1.CauseTypeFieldManagerConflict {
+				continue
+			}
+			if match := conflictManagerRegex.FindStringSubmatch(cause.Message); match != nil {
+				conflicts = append(conflicts, FieldConflict{Field: cause.Field, Manager: match[1]})
+			}
+		}
+	} else {
+		conflicts = parseConflictMessage(err.Error())
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &ApplyConflictError{Conflicts: conflicts, err: err}
+}
+
+// parseConflictMessage parses the conflicts from the message of an apply
+// conflict error returned by the apiserver.
+func parseConflictMessage(msg string) []FieldConflict {
+	if match := singleConflictRegex.FindStringSubmatch(msg); match != nil {
+		return []FieldConflict{{Field: match[2], Manager: match[1]}}
+	}
+	idx := strings.Index(msg, "Apply failed with ")
+	if idx < 0 {
+		return nil
+	}
+	var conflicts []FieldConflict
+	manager := ""
+	for _, line := range strings.Split(msg[idx:], "\n") {
+		line = strings.TrimSpace(line)
+		if match := conflictManagerRegex.FindStringSubmatch(line); match != nil {
+			manager = match[1]
+			continue
+		}
+		if match := conflictFieldRegex.FindStringSubmatch(line); match != nil && manager != "" {
+			conflicts = append(conflicts, FieldConflict{Field: match[1], Manager: manager})
+			continue
+		}
+		if manager != "" {
+			// End of the conflict list
+			break
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewApplyConflictError(t *testing.T) {
+	statusErr := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "old-ci"`,
+			Field:   ".metadata.labels.app",
+		},
+	}, "Apply failed with 2 conflicts")
+
+	tests := map[string]struct {
+		err      error
+		expected []FieldConflict
+	}{
+		"nil error": {
+			err: nil,
+		},
+		"not a conflict": {
+			err: errors.New("some other error"),
+		},
+		"status error not a conflict": {
+			err: apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "foo"),
+		},
+		"wrapped status error": {
+			err: fmt.Errorf("apply failed: %w", statusErr),
+			expected: []FieldConflict{
+				{Field: ".spec.replicas", Manager: "kubectl-edit"},
+				{Field: ".metadata.labels.app", Manager: "old-ci"},
+			},
+		},
+		"single conflict message": {
+			err: errors.New(`Apply failed with 1 conflict: conflict with "kubectl-edit" using apps/v1: .spec.replicas
+Please review the fields above--they currently have other managers.`),
+			expected: []FieldConflict{
+				{Field: ".spec.replicas", Manager: "kubectl-edit"},
+			},
+		},
+		"multiple conflicts message": {
+			err: errors.New(`Apply failed with 3 conflicts: conflicts with "kubectl-edit" using apps/v1:
+- .spec.replicas
+- .spec.paused
+conflicts with "old-ci":
+- .metadata.labels.app
+Please review the fields above--they currently have other managers.`),
+			expected: []FieldConflict{
+				{Field: ".spec.replicas", Manager: "kubectl-edit"},
+				{Field: ".spec.paused", Manager: "kubectl-edit"},
+				{Field: ".metadata.labels.app", Manager: "old-ci"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			conflictErr := NewApplyConflictError(tc.err)
+			if tc.expected == nil {
+				assert.Nil(t, conflictErr)
+				return
+			}
+			if assert.NotNil(t, conflictErr) {
+				assert.Equal(t, tc.expected, conflictErr.Conflicts)
+				assert.True(t, errors.Is(conflictErr, tc.err))
+			}
+		})
+	}
+}
+
+func TestApplyConflictError(t *testing.T) {
+	conflictErr := &ApplyConflictError{
+		Conflicts: []FieldConflict{
+			{Field: ".spec.replicas", Manager: "kubectl-edit"},
+			{Field: ".metadata.labels.app", Manager: "old-ci"},
+			{Field: ".spec.paused", Manager: "kubectl-edit"},
+		},
+	}
+	assert.Equal(t, `apply failed with 3 conflicts: .spec.replicas (manager: "kubectl-edit"), `+
+		`.metadata.labels.app (manager: "old-ci"), .spec.paused (manager: "kubectl-edit")`, conflictErr.Error())
+	assert.Equal(t, []string{"kubectl-edit", "old-ci"}, conflictErr.Managers())
+
+	// Unwrapped from ApplyRunError
+	var target *ApplyConflictError
+	assert.True(t, errors.As(NewApplyRunError(conflictErr), &target))
+}
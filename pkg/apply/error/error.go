@@ -22,6 +22,10 @@ func (e *ApplyRunError) Error() string {
 	return e.err.Error()
 }
 
+func (e *ApplyRunError) Unwrap() error {
+	return e.err
+}
+
 func NewApplyRunError(err error) *ApplyRunError {
 	return &ApplyRunError{err: err}
 }
@@ -0,0 +1,41 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// immutableFieldMessages are the messages returned by the apiserver when an
+// update changes a field which can only be set on create.
+var immutableFieldMessages = []string{
+	// Most fields, e.g. Job template, Service clusterIP, Deployment selector.
+	"field is immutable",
+	// StatefulSet fields other than the allowed ones, e.g. volumeClaimTemplates.
+	"updates to statefulset spec for fields other than",
+}
+
+// IsImmutableFieldError returns true if the passed error is returned by the
+// apiserver because an update changes an immutable field. Such objects can
+// only be changed by deleting and re-creating them.
+func IsImmutableFieldError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Reason != metav1.StatusReasonInvalid {
+		return false
+	}
+	msg := err.Error()
+	for _, immutableMsg := range immutableFieldMessages {
+		if strings.Contains(msg, immutableMsg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestIsImmutableFieldError(t *testing.T) {
+	jobGK := schema.GroupKind{Group: "batch", Kind: "Job"}
+	stsGK := schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil error": {
+			err: nil,
+		},
+		"other error": {
+			err: errors.New("some other error"),
+		},
+		"invalid error with other cause": {
+			err: apierrors.NewInvalid(jobGK, "foo", field.ErrorList{
+				field.Required(field.NewPath("spec", "template"), ""),
+			}),
+		},
+		"not found error": {
+			err: apierrors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "foo"),
+		},
+		"immutable field": {
+			err: apierrors.NewInvalid(jobGK, "foo", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+			}),
+			expected: true,
+		},
+		"wrapped immutable field": {
+			err: fmt.Errorf("apply failed: %w", apierrors.NewInvalid(jobGK, "foo", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+			})),
+			expected: true,
+		},
+		"statefulset forbidden update": {
+			err: apierrors.NewInvalid(stsGK, "foo", field.ErrorList{
+				field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than "+
+					"'replicas', 'template', 'updateStrategy' and 'minReadySeconds' are forbidden"),
+			}),
+			expected: true,
+		},
+		"immutable field message": {
+			err:      errors.New(`Job.batch "foo" is invalid: spec.template: Invalid value: "": field is immutable`),
+			expected: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsImmutableFieldError(tc.err))
+		})
+	}
+}
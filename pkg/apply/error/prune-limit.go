@@ -0,0 +1,37 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PruneLimitError is the error returned when the number of objects to prune
+// exceeds the maximum number or percentage of inventory objects which may
+// be pruned in one run.
+type PruneLimitError struct {
+	// PruneCount is the number of objects which would have been pruned.
+	PruneCount int
+	// InventoryCount is the number of objects in the inventory.
+	InventoryCount int
+	// MaxCount is the maximum number of objects which may be pruned, or
+	// zero if unlimited.
+	MaxCount int
+	// MaxPercent is the maximum percentage of inventory objects which may
+	// be pruned, or zero if unlimited.
+	MaxPercent int
+}
+
+func (e *PruneLimitError) Error() string {
+	var limits []string
+	if e.MaxCount > 0 {
+		limits = append(limits, fmt.Sprintf("%d objects", e.MaxCount))
+	}
+	if e.MaxPercent > 0 {
+		limits = append(limits, fmt.Sprintf("%d%% of inventory", e.MaxPercent))
+	}
+	return fmt.Sprintf("refusing to prune %d of %d inventory objects: exceeds prune limit (%s)",
+		e.PruneCount, e.InventoryCount, strings.Join(limits, ", "))
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneLimitError(t *testing.T) {
+	tests := map[string]struct {
+		err      *PruneLimitError
+		expected string
+	}{
+		"count limit": {
+			err:      &PruneLimitError{PruneCount: 40, InventoryCount: 42, MaxCount: 10},
+			expected: "refusing to prune 40 of 42 inventory objects: exceeds prune limit (10 objects)",
+		},
+		"percent limit": {
+			err:      &PruneLimitError{PruneCount: 40, InventoryCount: 42, MaxPercent: 50},
+			expected: "refusing to prune 40 of 42 inventory objects: exceeds prune limit (50% of inventory)",
+		},
+		"both limits": {
+			err:      &PruneLimitError{PruneCount: 40, InventoryCount: 42, MaxCount: 10, MaxPercent: 50},
+			expected: "refusing to prune 40 of 42 inventory objects: exceeds prune limit (10 objects, 50% of inventory)",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.err.Error())
+		})
+	}
+}
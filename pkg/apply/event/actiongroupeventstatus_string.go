@@ -10,11 +10,12 @@ func _() {
 	var x [1]struct{}
 	_ = x[Started-0]
 	_ = x[Finished-1]
+	_ = x[Skipped-2]
 }
 
-const _ActionGroupEventStatus_name = "StartedFinished"
+const _ActionGroupEventStatus_name = "StartedFinishedSkipped"
 
-var _ActionGroupEventStatus_index = [...]uint8{0, 7, 15}
+var _ActionGroupEventStatus_index = [...]uint8{0, 7, 15, 22}
 
 func (i ActionGroupEventStatus) String() string {
 	if i < 0 || i >= ActionGroupEventStatus(len(_ActionGroupEventStatus_index)-1) {
@@ -6,6 +6,7 @@ package event
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -27,6 +28,7 @@ const (
 	DeleteType
 	WaitType
 	ValidationType
+	VerifyType
 )
 
 // Event is the type of the objects that will be returned through
@@ -70,6 +72,10 @@ type Event struct {
 
 	// ValidationEvent contains information about validation errors.
 	ValidationEvent ValidationEvent
+
+	// VerifyEvent contains information about the result of a verification
+	// check.
+	VerifyEvent VerifyEvent
 }
 
 // String returns a string suitable for logging
@@ -94,6 +100,8 @@ func (e Event) String() string {
 		sb.WriteString(e.WaitEvent.String())
 	case ValidationType:
 		sb.WriteString(e.ValidationEvent.String())
+	case VerifyType:
+		sb.WriteString(e.VerifyEvent.String())
 	}
 	return sb.String()
 }
@@ -116,6 +124,7 @@ const (
 	DeleteAction                          // Delete
 	WaitAction                            // Wait
 	InventoryAction                       // Inventory
+	VerifyAction                          // Verify
 )
 
 type ActionGroupList []ActionGroup
@@ -172,20 +181,98 @@ type WaitEvent struct {
 	GroupName  string
 	Identifier object.ObjMetadata
 	Status     WaitEventStatus
+	// Terminating is set when waiting for the deletion of an object that
+	// has been scheduled for deletion, but is blocked by finalizers.
+	Terminating *TerminatingInfo
+	// Progress is set on the periodic pending events of objects which have
+	// not reconciled yet.
+	Progress *ProgressInfo
 }
 
 // String returns a string suitable for logging
 func (we WaitEvent) String() string {
+	if we.Terminating != nil {
+		return fmt.Sprintf("WaitEvent{ GroupName: %q, Status: %q, Identifier: %q, Terminating: %s }",
+			we.GroupName, we.Status, we.Identifier, we.Terminating)
+	}
+	if we.Progress != nil {
+		return fmt.Sprintf("WaitEvent{ GroupName: %q, Status: %q, Identifier: %q, Progress: %s }",
+			we.GroupName, we.Status, we.Identifier, we.Progress)
+	}
 	return fmt.Sprintf("WaitEvent{ GroupName: %q, Status: %q, Identifier: %q }",
 		we.GroupName, we.Status, we.Identifier)
 }
 
+// TerminatingInfo describes an object that has a deletionTimestamp and
+// remaining finalizers.
+type TerminatingInfo struct {
+	// Finalizers are the remaining finalizers of the object.
+	Finalizers []string
+	// Duration is how long the object has been terminating.
+	Duration time.Duration
+	// FinalizersRemoved is true if the finalizers were forcibly removed,
+	// because the object was terminating for too long.
+	FinalizersRemoved bool
+}
+
+// String returns a string suitable for logging
+func (ti TerminatingInfo) String() string {
+	return fmt.Sprintf("TerminatingInfo{ Finalizers: %q, Duration: %q, FinalizersRemoved: %t }",
+		ti.Finalizers, ti.Duration, ti.FinalizersRemoved)
+}
+
+// ProgressInfo describes the latest status of an object which is still
+// pending reconciliation.
+type ProgressInfo struct {
+	// Message is the latest status message of the object, e.g. "Ready: 2/5".
+	Message string
+	// Elapsed is how long the wait task has been waiting.
+	Elapsed time.Duration
+}
+
+// String returns a string suitable for logging
+func (pi ProgressInfo) String() string {
+	return fmt.Sprintf("ProgressInfo{ Message: %q, Elapsed: %q }", pi.Message, pi.Elapsed)
+}
+
+//go:generate stringer -type=VerifyEventStatus -linecomment
+type VerifyEventStatus int
+
+const (
+	VerifySuccessful VerifyEventStatus = iota // Successful
+	VerifySkipped                             // Skipped
+	VerifyFailed                              // Failed
+)
+
+type VerifyEvent struct {
+	GroupName  string
+	Identifier object.ObjMetadata
+	Status     VerifyEventStatus
+	// Check is the verification check, in the format of the verify
+	// annotation value, e.g. "job-complete".
+	Check string
+	Error error
+}
+
+// String returns a string suitable for logging
+func (ve VerifyEvent) String() string {
+	if ve.Error != nil {
+		return fmt.Sprintf("VerifyEvent{ GroupName: %q, Status: %q, Identifier: %q, Check: %q, Error: %q }",
+			ve.GroupName, ve.Status, ve.Identifier, ve.Check, ve.Error)
+	}
+	return fmt.Sprintf("VerifyEvent{ GroupName: %q, Status: %q, Identifier: %q, Check: %q }",
+		ve.GroupName, ve.Status, ve.Identifier, ve.Check)
+}
+
 //go:generate stringer -type=ActionGroupEventStatus
 type ActionGroupEventStatus int
 
 const (
 	Started ActionGroupEventStatus = iota
 	Finished
+	// Skipped means the action group was never started, because the run
+	// was aborted.
+	Skipped
 )
 
 type ActionGroupEvent struct {
@@ -216,10 +303,24 @@ type ApplyEvent struct {
 	Status     ApplyEventStatus
 	Resource   *unstructured.Unstructured
 	Error      error
+	// Replaced is true if the object was deleted and re-created, because
+	// the apply changed an immutable field.
+	Replaced bool
+	// Adopted is set if the apply took ownership of an object owned by
+	// another inventory, or by none.
+	Adopted *AdoptionInfo
 }
 
 // String returns a string suitable for logging
 func (ae ApplyEvent) String() string {
+	if ae.Replaced {
+		return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q, Replaced: true }",
+			ae.GroupName, ae.Status, ae.Identifier)
+	}
+	if ae.Adopted != nil {
+		return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q, Adopted: %s }",
+			ae.GroupName, ae.Status, ae.Identifier, ae.Adopted)
+	}
 	if ae.Error != nil {
 		return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q, Error: %q }",
 			ae.GroupName, ae.Status, ae.Identifier, ae.Error)
@@ -228,6 +329,22 @@ func (ae ApplyEvent) String() string {
 		ae.GroupName, ae.Status, ae.Identifier)
 }
 
+// AdoptionInfo describes an object whose ownership was taken over by the
+// applying inventory.
+type AdoptionInfo struct {
+	// PreviousOwner is the ID of the inventory which owned the object,
+	// or empty if the object was not owned by any inventory.
+	PreviousOwner string
+	// NewOwner is the ID of the inventory which now owns the object.
+	NewOwner string
+}
+
+// String returns a string suitable for logging
+func (ai AdoptionInfo) String() string {
+	return fmt.Sprintf("AdoptionInfo{ PreviousOwner: %q, NewOwner: %q }",
+		ai.PreviousOwner, ai.NewOwner)
+}
+
 type StatusEvent struct {
 	Identifier       object.ObjMetadata
 	PollResourceInfo *pollevent.ResourceStatus
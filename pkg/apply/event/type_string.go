@@ -17,11 +17,12 @@ func _() {
 	_ = x[DeleteType-6]
 	_ = x[WaitType-7]
 	_ = x[ValidationType-8]
+	_ = x[VerifyType-9]
 }
 
-const _Type_name = "InitTypeErrorTypeActionGroupTypeApplyTypeStatusTypePruneTypeDeleteTypeWaitTypeValidationType"
+const _Type_name = "InitTypeErrorTypeActionGroupTypeApplyTypeStatusTypePruneTypeDeleteTypeWaitTypeValidationTypeVerifyType"
 
-var _Type_index = [...]uint8{0, 8, 17, 32, 41, 51, 60, 70, 78, 92}
+var _Type_index = [...]uint8{0, 8, 17, 32, 41, 51, 60, 70, 78, 92, 102}
 
 func (i Type) String() string {
 	if i < 0 || i >= Type(len(_Type_index)-1) {
@@ -0,0 +1,135 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// CRDInUseFilter implements ValidationFilter interface to prevent pruning
+// (deleting) a CustomResourceDefinition while custom resources of that kind
+// exist which were not successfully pruned (deleted) earlier in the same
+// run. Deleting a CRD deletes all of its custom resources, including those
+// owned by other inventories or by no inventory, and those whose prune was
+// skipped or failed.
+type CRDInUseFilter struct {
+	Client      dynamic.Interface
+	TaskContext *taskrunner.TaskContext
+}
+
+// Name returns a filter identifier for logging.
+func (cuf CRDInUseFilter) Name() string {
+	return "CRDInUseFilter"
+}
+
+// Filter returns a CRDInUseError if the object is a CRD with custom
+// resources that were not successfully pruned (deleted), in which case the
+// prune/delete should be skipped. Custom resources are pruned before their
+// CRD, so the inventory manager has recorded their prune result.
+func (cuf CRDInUseFilter) Filter(obj *unstructured.Unstructured) error {
+	if !object.IsCRD(obj) {
+		return nil
+	}
+	gk, found := object.GetCRDGroupKind(obj)
+	if !found {
+		return nil
+	}
+	gvr, err := crdResource(obj)
+	if err != nil {
+		return fmt.Errorf("failed to read custom resource definition %q: %w", obj.GetName(), err)
+	}
+	klog.V(6).Infof("listing custom resources %q", gvr)
+	list, err := cuf.Client.Resource(gvr).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource is not served, so there are no instances.
+			return nil
+		}
+		return fmt.Errorf("failed to list custom resources %q: %w", gk, err)
+	}
+	remaining := 0
+	for i := range list.Items {
+		id := object.UnstructuredToObjMetadata(&list.Items[i])
+		// Deleted custom resources may still exist while they terminate.
+		if !cuf.TaskContext.InventoryManager().IsSuccessfulDelete(id) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return &CRDInUseError{
+			GroupKind: gk,
+			Remaining: remaining,
+		}
+	}
+	return nil
+}
+
+// crdResource returns the GroupVersionResource of the custom resources of
+// the passed CRD, using the storage version.
+func crdResource(crd *unstructured.Unstructured) (schema.GroupVersionResource, error) {
+	group, _, err := unstructured.NestedString(crd.Object, "spec", "group")
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	plural, found, err := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	if !found || plural == "" {
+		return schema.GroupVersionResource{}, object.NotFound([]interface{}{"spec", "names", "plural"}, plural)
+	}
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	version := ""
+	for _, v := range versions {
+		vMap, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(vMap, "name")
+		if storage, _, _ := unstructured.NestedBool(vMap, "storage"); storage || version == "" {
+			version = name
+		}
+	}
+	if version == "" {
+		return schema.GroupVersionResource{}, object.NotFound([]interface{}{"spec", "versions"}, version)
+	}
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: plural}, nil
+}
+
+// CRDInUseError is the error returned when a CustomResourceDefinition is not
+// deleted, because custom resources of that kind remain.
+type CRDInUseError struct {
+	GroupKind schema.GroupKind
+	Remaining int
+}
+
+func (e *CRDInUseError) Error() string {
+	return fmt.Sprintf("custom resource definition in use: %d %s instances remain which were not deleted",
+		e.Remaining, e.GroupKind)
+}
+
+func (e *CRDInUseError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*CRDInUseError)
+	if !ok {
+		return false
+	}
+	return e.GroupKind == tErr.GroupKind &&
+		e.Remaining == tErr.Remaining
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var anvilCRD = &unstructured.Unstructured{
+	Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": "anvils.example.com",
+		},
+		"spec": map[string]interface{}{
+			"group": "example.com",
+			"names": map[string]interface{}{
+				"kind":   "Anvil",
+				"plural": "anvils",
+			},
+			"scope": "Namespaced",
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name":    "v1alpha1",
+					"served":  true,
+					"storage": false,
+				},
+				map[string]interface{}{
+					"name":    "v1",
+					"served":  true,
+					"storage": true,
+				},
+			},
+		},
+	},
+}
+
+func anvil(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Anvil",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+			},
+		},
+	}
+}
+
+func TestCRDInUseFilter(t *testing.T) {
+	anvilGK := schema.GroupKind{Group: "example.com", Kind: "Anvil"}
+
+	tests := map[string]struct {
+		obj           *unstructured.Unstructured
+		clusterObjs   []runtime.Object
+		deletedIDs    object.ObjMetadataSet
+		skippedIDs    object.ObjMetadataSet
+		failedIDs     object.ObjMetadataSet
+		expectedError error
+	}{
+		"Non-CRD object is not filtered": {
+			obj: defaultObj,
+			clusterObjs: []runtime.Object{
+				anvil("foo", "anvil-1"),
+			},
+		},
+		"CRD without custom resources is not filtered": {
+			obj: anvilCRD,
+		},
+		"CRD with only pruned custom resources is not filtered": {
+			obj: anvilCRD,
+			clusterObjs: []runtime.Object{
+				anvil("foo", "anvil-1"),
+				anvil("bar", "anvil-2"),
+			},
+			deletedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(anvil("foo", "anvil-1")),
+				object.UnstructuredToObjMetadata(anvil("bar", "anvil-2")),
+			},
+		},
+		"CRD with remaining custom resources is filtered": {
+			obj: anvilCRD,
+			clusterObjs: []runtime.Object{
+				anvil("foo", "anvil-1"),
+				anvil("bar", "anvil-2"),
+				anvil("bar", "anvil-3"),
+			},
+			deletedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(anvil("foo", "anvil-1")),
+			},
+			expectedError: &CRDInUseError{
+				GroupKind: anvilGK,
+				Remaining: 2,
+			},
+		},
+		"CRD with custom resources whose prune was skipped is filtered": {
+			obj: anvilCRD,
+			clusterObjs: []runtime.Object{
+				anvil("foo", "anvil-1"),
+				anvil("bar", "anvil-2"),
+			},
+			deletedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(anvil("foo", "anvil-1")),
+			},
+			skippedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(anvil("bar", "anvil-2")),
+			},
+			expectedError: &CRDInUseError{
+				GroupKind: anvilGK,
+				Remaining: 1,
+			},
+		},
+		"CRD with custom resources whose prune failed is filtered": {
+			obj: anvilCRD,
+			clusterObjs: []runtime.Object{
+				anvil("foo", "anvil-1"),
+			},
+			failedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(anvil("foo", "anvil-1")),
+			},
+			expectedError: &CRDInUseError{
+				GroupKind: anvilGK,
+				Remaining: 1,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					{Group: "example.com", Version: "v1", Resource: "anvils"}: "AnvilList",
+				}, tc.clusterObjs...)
+			taskContext := taskrunner.NewTaskContext(nil, cache.NewResourceCacheMap())
+			for _, id := range tc.deletedIDs {
+				taskContext.InventoryManager().AddSuccessfulDelete(id, "")
+			}
+			for _, id := range tc.skippedIDs {
+				taskContext.InventoryManager().AddSkippedDelete(id)
+			}
+			for _, id := range tc.failedIDs {
+				taskContext.InventoryManager().AddFailedDelete(id)
+			}
+			filter := CRDInUseFilter{
+				Client:      client,
+				TaskContext: taskContext,
+			}
+			err := filter.Filter(tc.obj.DeepCopy())
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestCRDInUseFilterInvalidCRD(t *testing.T) {
+	crd := anvilCRD.DeepCopy()
+	unstructured.RemoveNestedField(crd.Object, "spec", "versions")
+	filter := CRDInUseFilter{
+		Client:      fake.NewSimpleDynamicClient(runtime.NewScheme()),
+		TaskContext: taskrunner.NewTaskContext(nil, cache.NewResourceCacheMap()),
+	}
+	assert.Error(t, filter.Filter(crd))
+}
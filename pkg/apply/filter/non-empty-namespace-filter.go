@@ -0,0 +1,189 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// maxListedObjects is the maximum number of untracked objects listed in
+// the NamespaceNotEmptyError message.
+const maxListedObjects = 3
+
+var (
+	// ignoredNamespacedGroupResources are resources which are ignored when
+	// checking if a namespace is empty, because they are created by the
+	// cluster or do not outlive the namespace contents.
+	ignoredNamespacedGroupResources = sets.NewString( // nolint:staticcheck
+		"events",
+		"events.events.k8s.io",
+	)
+	// ignoredNamespacedObjects are objects which are created by the cluster
+	// in every namespace.
+	ignoredNamespacedObjects = map[schema.GroupKind]sets.String{ // nolint:staticcheck
+		{Kind: "ServiceAccount"}: sets.NewString("default"),
+		{Kind: "ConfigMap"}:      sets.NewString("kube-root-ca.crt"),
+	}
+)
+
+// NonEmptyNamespaceFilter implements ValidationFilter interface to prevent
+// pruning (deleting) a Namespace which contains objects that are not tracked
+// by the inventory. Deleting a Namespace deletes all the objects in it,
+// including objects owned by other tools or teams.
+// The server resources are discovered once, so a new filter should be used
+// for every run.
+type NonEmptyNamespaceFilter struct {
+	Client          dynamic.Interface
+	DiscoveryClient discovery.ServerResourcesInterface
+	Inv             inventory.Info
+
+	// mu protects the cached server resources
+	mu            sync.Mutex
+	resourceLists []*metav1.APIResourceList
+	discovered    bool
+}
+
+// Name returns a filter identifier for logging.
+func (nef *NonEmptyNamespaceFilter) Name() string {
+	return "NonEmptyNamespaceFilter"
+}
+
+// Filter returns a NamespaceNotEmptyError if the object is a Namespace that
+// contains objects not tracked by the inventory, in which case the
+// prune/delete should be skipped.
+func (nef *NonEmptyNamespaceFilter) Filter(obj *unstructured.Unstructured) error {
+	id := object.UnstructuredToObjMetadata(obj)
+	if id.GroupKind != namespaceGK {
+		return nil
+	}
+	untracked, err := nef.untrackedObjects(id.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list objects in namespace %q: %w", id.Name, err)
+	}
+	if len(untracked) > 0 {
+		return &NamespaceNotEmptyError{
+			Namespace: id.Name,
+			Objects:   untracked,
+		}
+	}
+	return nil
+}
+
+// untrackedObjects lists the objects in the passed namespace which are not
+// tracked by the inventory. Objects with owner references are ignored,
+// because they are garbage collected with their owner.
+func (nef *NonEmptyNamespaceFilter) untrackedObjects(namespace string) (object.ObjMetadataSet, error) {
+	resourceLists, err := nef.serverResources()
+	if err != nil {
+		return nil, err
+	}
+	var untracked object.ObjMetadataSet
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resourceList.APIResources {
+			gvr := gv.WithResource(resource.Name)
+			if !resource.Namespaced ||
+				!sets.NewString(resource.Verbs...).Has("list") || // nolint:staticcheck
+				ignoredNamespacedGroupResources.Has(gvr.GroupResource().String()) {
+				continue
+			}
+			klog.V(6).Infof("listing %q in namespace %q", gvr.GroupResource(), namespace)
+			list, err := nef.Client.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				if nef.isIgnored(item) {
+					continue
+				}
+				untracked = append(untracked, object.UnstructuredToObjMetadata(item))
+			}
+		}
+	}
+	return untracked, nil
+}
+
+// serverResources returns the preferred resources of the server. The
+// resources are discovered on the first call and cached. Groups which fail
+// discovery, like unavailable aggregated APIs, are skipped with a warning,
+// so their objects are not checked.
+func (nef *NonEmptyNamespaceFilter) serverResources() ([]*metav1.APIResourceList, error) {
+	nef.mu.Lock()
+	defer nef.mu.Unlock()
+
+	if nef.discovered {
+		return nef.resourceLists, nil
+	}
+	resourceLists, err := nef.DiscoveryClient.ServerPreferredResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		klog.Warningf("objects of unavailable resources are not checked for namespace deletion: %v", err)
+	}
+	nef.resourceLists = resourceLists
+	nef.discovered = true
+	return resourceLists, nil
+}
+
+// isIgnored returns true if the passed object does not prevent deleting
+// its namespace.
+func (nef *NonEmptyNamespaceFilter) isIgnored(obj *unstructured.Unstructured) bool {
+	if len(obj.GetOwnerReferences()) > 0 {
+		return true
+	}
+	if names, found := ignoredNamespacedObjects[obj.GroupVersionKind().GroupKind()]; found && names.Has(obj.GetName()) {
+		return true
+	}
+	return inventory.IDMatch(nef.Inv, obj) == inventory.Match
+}
+
+// NamespaceNotEmptyError is the error returned when a Namespace is not
+// deleted, because it contains objects not tracked by the inventory.
+type NamespaceNotEmptyError struct {
+	Namespace string
+	Objects   object.ObjMetadataSet
+}
+
+func (e *NamespaceNotEmptyError) Error() string {
+	listed := make([]string, 0, maxListedObjects)
+	for i, id := range e.Objects {
+		if i == maxListedObjects {
+			listed = append(listed, fmt.Sprintf("and %d more", len(e.Objects)-maxListedObjects))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s/%s", strings.ToLower(id.GroupKind.String()), id.Name))
+	}
+	return fmt.Sprintf("namespace not empty: %s contains %d objects not in the inventory (%s)",
+		e.Namespace, len(e.Objects), strings.Join(listed, ", "))
+}
+
+func (e *NamespaceNotEmptyError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*NamespaceNotEmptyError)
+	if !ok {
+		return false
+	}
+	return e.Namespace == tErr.Namespace &&
+		e.Objects.Equal(tErr.Objects)
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+// fakeResourcesDiscovery returns the fake resources from
+// ServerPreferredResources, which the client-go fake does not implement.
+type fakeResourcesDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	err   error
+	calls int
+}
+
+func (f *fakeResourcesDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	f.calls++
+	return f.Resources, f.err
+}
+
+var testNamespacedResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: []string{"get", "list"}},
+			{Name: "events", Kind: "Event", Namespaced: true, Verbs: []string{"get", "list"}},
+			{Name: "namespaces", Kind: "Namespace", Verbs: []string{"get", "list"}},
+			{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+			{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true, Verbs: []string{"get", "list"}},
+		},
+	},
+}
+
+func namespacedObj(kind, name, inventoryID string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "test-namespace",
+			},
+		},
+	}
+	if inventoryID != "" {
+		obj.SetAnnotations(map[string]string{inventory.OwningInventoryKey: inventoryID})
+	}
+	return obj
+}
+
+func TestNonEmptyNamespaceFilter(t *testing.T) {
+	ownedPod := namespacedObj("Pod", "owned", "")
+	ownedPod.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "foo", UID: "uid"},
+	})
+
+	tests := map[string]struct {
+		obj            *unstructured.Unstructured
+		clusterObjs    []runtime.Object
+		discoveryErr   error
+		expectedError  error
+		expectedErrMsg string
+	}{
+		"Non-namespace object is not filtered": {
+			obj: namespacedObj("Pod", "untracked", ""),
+			clusterObjs: []runtime.Object{
+				namespacedObj("Pod", "untracked", ""),
+			},
+		},
+		"Empty namespace is not filtered": {
+			obj: testNamespace,
+		},
+		"Namespace with only tracked and ignored objects is not filtered": {
+			obj: testNamespace,
+			clusterObjs: []runtime.Object{
+				namespacedObj("Pod", "tracked", "test-inv"),
+				namespacedObj("ServiceAccount", "default", ""),
+				namespacedObj("ConfigMap", "kube-root-ca.crt", ""),
+				namespacedObj("Event", "some-event", ""),
+				ownedPod,
+			},
+		},
+		"Namespace with untracked objects is filtered": {
+			obj: testNamespace,
+			clusterObjs: []runtime.Object{
+				namespacedObj("Pod", "tracked", "test-inv"),
+				namespacedObj("Pod", "untracked", ""),
+				namespacedObj("ConfigMap", "other-inv", "other-inv"),
+			},
+			expectedError: &NamespaceNotEmptyError{
+				Namespace: "test-namespace",
+				Objects: object.ObjMetadataSet{
+					{
+						GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+						Namespace: "test-namespace",
+						Name:      "other-inv",
+					},
+					{
+						GroupKind: schema.GroupKind{Kind: "Pod"},
+						Namespace: "test-namespace",
+						Name:      "untracked",
+					},
+				},
+			},
+		},
+		"Discovery error filters the namespace": {
+			obj:            testNamespace,
+			discoveryErr:   errors.New("discovery failed"),
+			expectedErrMsg: `failed to list objects in namespace "test-namespace": discovery failed`,
+		},
+		"Group discovery failure checks the discovered resources": {
+			obj: testNamespace,
+			clusterObjs: []runtime.Object{
+				namespacedObj("Pod", "untracked", ""),
+			},
+			discoveryErr: &discovery.ErrGroupDiscoveryFailed{
+				Groups: map[schema.GroupVersion]error{
+					{Group: "metrics.k8s.io", Version: "v1beta1"}: errors.New("service unavailable"),
+				},
+			},
+			expectedError: &NamespaceNotEmptyError{
+				Namespace: "test-namespace",
+				Objects: object.ObjMetadataSet{
+					{
+						GroupKind: schema.GroupKind{Kind: "Pod"},
+						Namespace: "test-namespace",
+						Name:      "untracked",
+					},
+				},
+			},
+		},
+	}
+
+	invObj := inventoryObj.DeepCopy()
+	invObj.SetLabels(map[string]string{common.InventoryLabel: "test-inv"})
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					{Version: "v1", Resource: "configmaps"}:      "ConfigMapList",
+					{Version: "v1", Resource: "events"}:          "EventList",
+					{Version: "v1", Resource: "pods"}:            "PodList",
+					{Version: "v1", Resource: "serviceaccounts"}: "ServiceAccountList",
+				}, tc.clusterObjs...)
+			filter := &NonEmptyNamespaceFilter{
+				Client: client,
+				DiscoveryClient: &fakeResourcesDiscovery{
+					FakeDiscovery: &fakediscovery.FakeDiscovery{
+						Fake: &clienttesting.Fake{Resources: testNamespacedResources},
+					},
+					err: tc.discoveryErr,
+				},
+				Inv: inventory.WrapInventoryInfoObj(invObj),
+			}
+			err := filter.Filter(tc.obj.DeepCopy())
+			if tc.expectedErrMsg != "" {
+				assert.EqualError(t, err, tc.expectedErrMsg)
+				return
+			}
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestNonEmptyNamespaceFilter_DiscoversOnce(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "configmaps"}:      "ConfigMapList",
+			{Version: "v1", Resource: "pods"}:            "PodList",
+			{Version: "v1", Resource: "serviceaccounts"}: "ServiceAccountList",
+		})
+	discoveryClient := &fakeResourcesDiscovery{
+		FakeDiscovery: &fakediscovery.FakeDiscovery{
+			Fake: &clienttesting.Fake{Resources: testNamespacedResources},
+		},
+	}
+	filter := &NonEmptyNamespaceFilter{
+		Client:          client,
+		DiscoveryClient: discoveryClient,
+		Inv:             inventory.WrapInventoryInfoObj(inventoryObj),
+	}
+	otherNamespace := testNamespace.DeepCopy()
+	otherNamespace.SetName("other-namespace")
+
+	assert.NoError(t, filter.Filter(testNamespace.DeepCopy()))
+	assert.NoError(t, filter.Filter(otherNamespace))
+	assert.Equal(t, 1, discoveryClient.calls)
+}
+
+func TestNamespaceNotEmptyError(t *testing.T) {
+	err := &NamespaceNotEmptyError{
+		Namespace: "test-namespace",
+		Objects: object.ObjMetadataSet{
+			{GroupKind: schema.GroupKind{Kind: "Pod"}, Namespace: "test-namespace", Name: "a"},
+			{GroupKind: schema.GroupKind{Kind: "Pod"}, Namespace: "test-namespace", Name: "b"},
+			{GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"}, Namespace: "test-namespace", Name: "c"},
+			{GroupKind: schema.GroupKind{Kind: "Secret"}, Namespace: "test-namespace", Name: "d"},
+		},
+	}
+	expected := "namespace not empty: test-namespace contains 4 objects not in the inventory " +
+		"(pod/a, pod/b, deployment.apps/c, and 1 more)"
+	testutil.AssertEqual(t, expected, err.Error())
+}
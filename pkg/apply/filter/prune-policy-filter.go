@@ -0,0 +1,107 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// PrunePolicy restricts which objects may be pruned by GroupKind and
+// namespace. An object may be pruned if it matches the allowlists, when not
+// empty, and does not match the denylists. The namespace of a Namespace
+// object is its name, so denying a namespace also denies pruning the
+// Namespace itself. Cluster-scoped objects are not affected by the
+// namespace lists.
+type PrunePolicy struct {
+	// AllowGroupKinds, if not empty, are the only GroupKinds that may be
+	// pruned.
+	AllowGroupKinds []schema.GroupKind
+	// DenyGroupKinds are GroupKinds that must not be pruned.
+	DenyGroupKinds []schema.GroupKind
+	// AllowNamespaces, if not empty, are the only namespaces in which
+	// objects may be pruned.
+	AllowNamespaces []string
+	// DenyNamespaces are namespaces in which objects must not be pruned.
+	DenyNamespaces []string
+}
+
+// PrunePolicyFilter implements ValidationFilter interface to determine if
+// an object should not be pruned (deleted) because of the PrunePolicy.
+type PrunePolicyFilter struct {
+	Policy PrunePolicy
+}
+
+// Name returns a filter identifier for logging.
+func (ppf PrunePolicyFilter) Name() string {
+	return "PrunePolicyFilter"
+}
+
+// Filter returns a PrunePolicyPreventedDeletionError if the object
+// prune/delete should be skipped.
+func (ppf PrunePolicyFilter) Filter(obj *unstructured.Unstructured) error {
+	id := object.UnstructuredToObjMetadata(obj)
+	if len(ppf.Policy.AllowGroupKinds) > 0 && !containsGroupKind(ppf.Policy.AllowGroupKinds, id.GroupKind) {
+		return &PrunePolicyPreventedDeletionError{
+			Reason: fmt.Sprintf("kind %q not in allowlist", id.GroupKind),
+		}
+	}
+	if containsGroupKind(ppf.Policy.DenyGroupKinds, id.GroupKind) {
+		return &PrunePolicyPreventedDeletionError{
+			Reason: fmt.Sprintf("kind %q in denylist", id.GroupKind),
+		}
+	}
+	namespace := id.Namespace
+	if id.GroupKind == namespaceGK {
+		namespace = id.Name
+	}
+	if namespace == "" {
+		return nil
+	}
+	if len(ppf.Policy.AllowNamespaces) > 0 && !slices.Contains(ppf.Policy.AllowNamespaces, namespace) {
+		return &PrunePolicyPreventedDeletionError{
+			Reason: fmt.Sprintf("namespace %q not in allowlist", namespace),
+		}
+	}
+	if slices.Contains(ppf.Policy.DenyNamespaces, namespace) {
+		return &PrunePolicyPreventedDeletionError{
+			Reason: fmt.Sprintf("namespace %q in denylist", namespace),
+		}
+	}
+	return nil
+}
+
+func containsGroupKind(gks []schema.GroupKind, gk schema.GroupKind) bool {
+	for _, g := range gks {
+		if g == gk {
+			return true
+		}
+	}
+	return false
+}
+
+// PrunePolicyPreventedDeletionError is the error returned when an object is
+// not pruned because of the PrunePolicy.
+type PrunePolicyPreventedDeletionError struct {
+	Reason string
+}
+
+func (e *PrunePolicyPreventedDeletionError) Error() string {
+	return fmt.Sprintf("prune policy prevents deletion: %s", e.Reason)
+}
+
+func (e *PrunePolicyPreventedDeletionError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*PrunePolicyPreventedDeletionError)
+	if !ok {
+		return false
+	}
+	return e.Reason == tErr.Reason
+}
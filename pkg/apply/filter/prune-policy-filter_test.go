@@ -0,0 +1,103 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestPrunePolicyFilter(t *testing.T) {
+	secret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "test-secret",
+				"namespace": "test-namespace",
+			},
+		},
+	}
+	clusterRole := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata": map[string]interface{}{
+				"name": "test-cluster-role",
+			},
+		},
+	}
+	secretGK := schema.GroupKind{Kind: "Secret"}
+	namespaceGK := schema.GroupKind{Kind: "Namespace"}
+
+	tests := map[string]struct {
+		policy        PrunePolicy
+		obj           *unstructured.Unstructured
+		expectedError error
+	}{
+		"Empty policy, object is not filtered": {
+			obj: secret,
+		},
+		"Kind in allowlist, object is not filtered": {
+			policy: PrunePolicy{AllowGroupKinds: []schema.GroupKind{secretGK}},
+			obj:    secret,
+		},
+		"Kind not in allowlist, object is filtered": {
+			policy: PrunePolicy{AllowGroupKinds: []schema.GroupKind{namespaceGK}},
+			obj:    secret,
+			expectedError: &PrunePolicyPreventedDeletionError{
+				Reason: `kind "Secret" not in allowlist`,
+			},
+		},
+		"Kind in denylist, object is filtered": {
+			policy: PrunePolicy{DenyGroupKinds: []schema.GroupKind{secretGK, namespaceGK}},
+			obj:    secret,
+			expectedError: &PrunePolicyPreventedDeletionError{
+				Reason: `kind "Secret" in denylist`,
+			},
+		},
+		"Namespace in allowlist, object is not filtered": {
+			policy: PrunePolicy{AllowNamespaces: []string{"test-namespace"}},
+			obj:    secret,
+		},
+		"Namespace not in allowlist, object is filtered": {
+			policy: PrunePolicy{AllowNamespaces: []string{"foo"}},
+			obj:    secret,
+			expectedError: &PrunePolicyPreventedDeletionError{
+				Reason: `namespace "test-namespace" not in allowlist`,
+			},
+		},
+		"Namespace in denylist, object is filtered": {
+			policy: PrunePolicy{DenyNamespaces: []string{"test-namespace"}},
+			obj:    secret,
+			expectedError: &PrunePolicyPreventedDeletionError{
+				Reason: `namespace "test-namespace" in denylist`,
+			},
+		},
+		"Denied namespace, Namespace object is filtered": {
+			policy: PrunePolicy{DenyNamespaces: []string{"test-namespace"}},
+			obj:    testNamespace,
+			expectedError: &PrunePolicyPreventedDeletionError{
+				Reason: `namespace "test-namespace" in denylist`,
+			},
+		},
+		"Namespace allowlist, cluster-scoped object is not filtered": {
+			policy: PrunePolicy{AllowNamespaces: []string{"foo"}},
+			obj:    clusterRole,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter := PrunePolicyFilter{
+				Policy: tc.policy,
+			}
+			err := filter.Filter(tc.obj.DeepCopy())
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// ScopeFilter implements ValidationFilter interface to determine if an
+// object should not be pruned (deleted) because it is outside the scope
+// declared by the inventory object.
+type ScopeFilter struct {
+	Scope inventory.Scope
+}
+
+// Name returns a filter identifier for logging.
+func (sf ScopeFilter) Name() string {
+	return "ScopeFilter"
+}
+
+// Filter returns an inventory.OutOfScopeError if the object prune/delete
+// should be skipped.
+func (sf ScopeFilter) Filter(obj *unstructured.Unstructured) error {
+	return sf.Scope.Check(object.UnstructuredToObjMetadata(obj))
+}
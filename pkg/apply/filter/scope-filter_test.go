@@ -0,0 +1,48 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestScopeFilter(t *testing.T) {
+	secret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "test-secret",
+				"namespace": "kube-system",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		scope         inventory.Scope
+		expectedError error
+	}{
+		"Empty scope, object is not filtered": {},
+		"Namespace in allowed namespaces, object is not filtered": {
+			scope: inventory.Scope{Namespaces: []string{"kube-system"}},
+		},
+		"Namespace not in allowed namespaces, object is filtered": {
+			scope: inventory.Scope{Namespaces: []string{"test-namespace"}},
+			expectedError: &inventory.OutOfScopeError{
+				Reason: `namespace "kube-system" not in allowed namespaces`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter := ScopeFilter{Scope: tc.scope}
+			err := filter.Filter(secret)
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
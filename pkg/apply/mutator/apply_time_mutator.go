@@ -19,8 +19,8 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
 	"sigs.k8s.io/cli-utils/pkg/jsonpath"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
-	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+	"sigs.k8s.io/cli-utils/pkg/redact"
 )
 
 // ApplyTimeMutator mutates an object by injecting values specified by the
@@ -58,7 +58,7 @@ func (atm *ApplyTimeMutator) Mutate(ctx context.Context, obj *unstructured.Unstr
 	}
 
 	klog.V(4).Infof("target object: %s", targetRef)
-	klog.V(7).Infof("target object YAML:\n%s", object.YamlStringer{O: obj})
+	klog.V(7).Infof("target object YAML:\n%s", redact.YamlStringer{O: obj})
 
 	// validate no self-references
 	// Early validation to avoid GETs, but won't catch sources with implicit namespace.
@@ -95,7 +95,7 @@ func (atm *ApplyTimeMutator) Mutate(ctx context.Context, obj *unstructured.Unstr
 		}
 
 		klog.V(4).Infof("source object: %s", sourceRef)
-		klog.V(7).Infof("source object YAML:\n%s", object.YamlStringer{O: sourceObj})
+		klog.V(7).Infof("source object YAML:\n%s", redact.YamlStringer{O: sourceObj})
 
 		// lookup target field in target object
 		targetValue, _, err := readFieldValue(obj, sub.TargetPath)
@@ -148,7 +148,7 @@ func (atm *ApplyTimeMutator) Mutate(ctx context.Context, obj *unstructured.Unstr
 
 	if mutated {
 		klog.V(4).Infof("mutated target object: %s", targetRef)
-		klog.V(7).Infof("mutated target object YAML:\n%s", object.YamlStringer{O: obj})
+		klog.V(7).Infof("mutated target object YAML:\n%s", redact.YamlStringer{O: obj})
 	}
 
 	return mutated, reason, nil
@@ -0,0 +1,67 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/preflight"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// checkPermissions checks that the user is allowed to apply the apply
+// objects, prune the prune objects, update the inventory and watch the
+// watched objects. The watched objects must be the ones passed to the status
+// watcher, so the watch scope is the same. Returns a
+// preflight.MissingPermissionsError with all the missing permissions, if any.
+func (a *Applier) checkPermissions(ctx context.Context, invInfo inventory.Info, applyObjs, pruneObjs object.UnstructuredSet,
+	watchedIDs object.ObjMetadataSet, pruneFilters []filter.ValidationFilter, options ApplierOptions) error {
+	// Objects skipped by the filters which do not depend on the cluster or
+	// on the progress of the run are not pruned, so they need no permission.
+	var staticFilters []filter.ValidationFilter
+	for _, f := range pruneFilters {
+		switch f.(type) {
+		case filter.PreventRemoveFilter, filter.PrunePolicyFilter, filter.InventoryPolicyPruneFilter,
+			filter.LocalNamespacesFilter, filter.ScopeFilter:
+			staticFilters = append(staticFilters, f)
+		}
+	}
+	var prunedObjs object.UnstructuredSet
+	for _, obj := range pruneObjs {
+		if !filteredOut(obj, staticFilters) {
+			prunedObjs = append(prunedObjs, obj)
+		}
+	}
+
+	inv := inventory.InvInfoToConfigMap(invInfo)
+	if inv == nil {
+		inv = inventory.InvInfoToApplySet(invInfo)
+	}
+	perms, err := preflight.RequiredPermissions(a.mapper, inv, applyObjs, prunedObjs, preflight.Options{
+		ServerSideApply:          options.ServerSideOptions.ServerSideApply,
+		WatcherRESTScopeStrategy: options.WatcherRESTScopeStrategy,
+		WatchedIDs:               watchedIDs,
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("preflight: checking %d permissions", len(perms))
+	checker := &preflight.Checker{Client: a.client}
+	return checker.Check(ctx, perms)
+}
+
+// filteredOut returns true if any of the filters returns an error for the
+// object.
+func filteredOut(obj *unstructured.Unstructured, filters []filter.ValidationFilter) bool {
+	for _, f := range filters {
+		if err := f.Filter(obj); err != nil {
+			return true
+		}
+	}
+	return false
+}
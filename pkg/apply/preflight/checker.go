@@ -0,0 +1,94 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+var selfSubjectAccessReviewGVR = authorizationv1.SchemeGroupVersion.WithResource("selfsubjectaccessreviews")
+
+// MissingPermissionsError is returned if the user is not allowed to perform
+// some of the actions of the apply.
+type MissingPermissionsError struct {
+	Permissions []Permission
+}
+
+func (e *MissingPermissionsError) Error() string {
+	perms := make([]string, len(e.Permissions))
+	for i, perm := range e.Permissions {
+		perms[i] = perm.String()
+	}
+	return fmt.Sprintf("preflight check failed: missing %d permission(s): %s",
+		len(perms), strings.Join(perms, ", "))
+}
+
+// Checker checks permissions with SelfSubjectAccessReviews, which do not
+// require any permission.
+type Checker struct {
+	Client dynamic.Interface
+}
+
+// Check returns a MissingPermissionsError with all the permissions which are
+// not allowed, or nil if all are allowed.
+func (c *Checker) Check(ctx context.Context, perms []Permission) error {
+	var missing []Permission
+	for _, perm := range perms {
+		allowed, err := c.allowed(ctx, perm)
+		if err != nil {
+			return fmt.Errorf("failed to check permission to %s: %w", perm, err)
+		}
+		if !allowed {
+			klog.V(4).Infof("preflight: permission missing: %s", perm)
+			missing = append(missing, perm)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingPermissionsError{Permissions: missing}
+	}
+	return nil
+}
+
+// allowed returns true if the SelfSubjectAccessReview of the permission is
+// allowed.
+func (c *Checker) allowed(ctx context.Context, perm Permission) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: authorizationv1.SchemeGroupVersion.String(),
+			Kind:       "SelfSubjectAccessReview",
+		},
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:      perm.Verb,
+				Group:     perm.Group,
+				Resource:  perm.Resource,
+				Namespace: perm.Namespace,
+				Name:      perm.Name,
+			},
+		},
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+	if err != nil {
+		return false, err
+	}
+	result, err := c.Client.Resource(selfSubjectAccessReviewGVR).
+		Create(ctx, &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	allowed, _, err := unstructured.NestedBool(result.Object, "status", "allowed")
+	if err != nil {
+		return false, err
+	}
+	return allowed, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestChecker_Check(t *testing.T) {
+	testCases := map[string]struct {
+		perms         []Permission
+		denied        map[string]bool
+		reviewError   error
+		expectedError error
+		expectedNames []string
+	}{
+		"all allowed": {
+			perms: concat(
+				perms("test", "", "secrets", "list"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+			expectedNames: []string{"", "creds", "creds"},
+		},
+		"missing permissions": {
+			perms: concat(
+				perms("", "", "namespaces", "create"),
+				perms("test", "", "secrets", "delete", "get", "patch"),
+			),
+			denied: map[string]bool{"create": true, "delete": true},
+			expectedError: &MissingPermissionsError{
+				Permissions: concat(
+					perms("", "", "namespaces", "create"),
+					perms("test", "", "secrets", "delete"),
+				),
+			},
+		},
+		"review error": {
+			perms:         perms("test", "", "secrets", "get"),
+			reviewError:   errors.New("connection refused"),
+			expectedError: errors.New(`failed to check permission to get secrets in namespace "test": connection refused`),
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var names []string
+			client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			client.PrependReactor("create", "selfsubjectaccessreviews",
+				func(action clienttesting.Action) (bool, runtime.Object, error) {
+					if tc.reviewError != nil {
+						return true, nil, tc.reviewError
+					}
+					review := action.(clienttesting.CreateAction).GetObject().(*unstructured.Unstructured)
+					verb, _, _ := unstructured.NestedString(review.Object, "spec", "resourceAttributes", "verb")
+					name, _, _ := unstructured.NestedString(review.Object, "spec", "resourceAttributes", "name")
+					names = append(names, name)
+					result := review.DeepCopy()
+					if err := unstructured.SetNestedField(result.Object, !tc.denied[verb], "status", "allowed"); err != nil {
+						return true, nil, err
+					}
+					return true, result, nil
+				})
+
+			err := (&Checker{Client: client}).Check(context.TODO(), tc.perms)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				if missingErr, ok := tc.expectedError.(*MissingPermissionsError); ok {
+					var resultErr *MissingPermissionsError
+					if assert.ErrorAs(t, err, &resultErr) {
+						assert.Equal(t, missingErr.Permissions, resultErr.Permissions)
+					}
+				}
+				return
+			}
+			assert.NoError(t, err)
+			if tc.expectedNames != nil {
+				assert.Equal(t, tc.expectedNames, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package preflight computes the permissions needed to apply and prune a set
+// of objects, and checks them before the apply starts, so that an apply does
+// not fail halfway through because of missing permissions.
+package preflight
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+)
+
+// Permission is a verb on a resource, which must be allowed for the apply.
+type Permission struct {
+	Verb     string
+	Group    string
+	Resource string
+	// Namespace is empty for cluster-scoped resources, and for namespaced
+	// resources in all namespaces.
+	Namespace string
+	// Name is the name of the object for the verbs on a single object, so
+	// that permissions restricted to resourceNames are checked.
+	Name string
+}
+
+// String returns the format
+// "VERB RESOURCE[.GROUP] ["NAME"] [in namespace NAMESPACE]".
+func (p Permission) String() string {
+	resource := schema.GroupResource{Group: p.Group, Resource: p.Resource}.String()
+	if p.Name != "" {
+		resource = fmt.Sprintf("%s %q", resource, p.Name)
+	}
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %q", p.Verb, resource, p.Namespace)
+}
+
+// Options defines how the objects are applied, which affects the required
+// permissions.
+type Options struct {
+	// ServerSideApply is true if the objects are applied with server-side
+	// apply, which creates objects with a patch.
+	ServerSideApply bool
+	// WatcherRESTScopeStrategy is the strategy of the status watcher, which
+	// decides whether the objects are watched in their namespaces or in all
+	// namespaces.
+	WatcherRESTScopeStrategy watcher.RESTScopeStrategy
+	// WatchedIDs are the objects watched by the status watcher, which may
+	// include prune candidates which are not pruned. Defaults to the apply
+	// and prune objects.
+	WatchedIDs object.ObjMetadataSet
+}
+
+var (
+	deploymentGK  = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	replicaSetGK  = schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}
+	statefulSetGK = schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	replicaSetGR  = schema.GroupResource{Group: "apps", Resource: "replicasets"}
+	podGR         = schema.GroupResource{Group: "", Resource: "pods"}
+)
+
+// namedVerbs are the verbs on a single object, whose permissions can be
+// restricted to resourceNames.
+var namedVerbs = map[string]bool{
+	"get":    true,
+	"patch":  true,
+	"update": true,
+	"delete": true,
+}
+
+// generatedResources are the resources listed by the status readers to
+// compute the status of the objects of a kind.
+var generatedResources = map[schema.GroupKind][]schema.GroupResource{
+	deploymentGK:  {replicaSetGR, podGR},
+	replicaSetGK:  {podGR},
+	statefulSetGK: {podGR},
+}
+
+// RequiredPermissions returns the sorted permissions needed to apply the
+// apply objects, prune the prune objects, update the inventory object and
+// watch the status of the objects. The resources of the kinds defined by
+// CRDs in the apply objects are looked up in the CRDs.
+func RequiredPermissions(mapper meta.RESTMapper, inv *unstructured.Unstructured,
+	applyObjs, pruneObjs object.UnstructuredSet, opts Options) ([]Permission, error) {
+	resolver := &resourceResolver{mapper: mapper, crds: findCRDs(applyObjs)}
+	perms := make(map[Permission]struct{})
+	add := func(gk schema.GroupKind, namespace, name string, verbs ...string) error {
+		gr, namespaced, err := resolver.resolve(gk)
+		if err != nil {
+			return err
+		}
+		if !namespaced {
+			namespace = ""
+		}
+		for _, verb := range verbs {
+			perm := Permission{Verb: verb, Group: gr.Group, Resource: gr.Resource, Namespace: namespace}
+			if namedVerbs[verb] {
+				perm.Name = name
+			}
+			perms[perm] = struct{}{}
+		}
+		return nil
+	}
+
+	if inv != nil {
+		// The inventory object is read, then created or updated.
+		if err := add(inv.GroupVersionKind().GroupKind(), inv.GetNamespace(), inv.GetName(),
+			"get", "create", "update"); err != nil {
+			return nil, err
+		}
+	}
+
+	applyVerbs := []string{"get", "create", "patch"}
+	if opts.ServerSideApply {
+		applyVerbs = []string{"get", "patch"}
+	}
+	for _, obj := range applyObjs {
+		id := object.UnstructuredToObjMetadata(obj)
+		if err := add(id.GroupKind, id.Namespace, id.Name, applyVerbs...); err != nil {
+			return nil, err
+		}
+		// The sources of apply-time mutations are read before the apply.
+		subs, err := mutation.ReadAnnotation(obj)
+		if err != nil {
+			// invalid annotations are rejected by validation
+			continue
+		}
+		for _, sub := range subs {
+			ref := sub.SourceRef.ToObjMetadata()
+			if ref.Namespace == "" {
+				ref.Namespace = id.Namespace
+			}
+			if err := add(ref.GroupKind, ref.Namespace, ref.Name, "get"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, obj := range pruneObjs {
+		id := object.UnstructuredToObjMetadata(obj)
+		if err := add(id.GroupKind, id.Namespace, id.Name, "get", "delete"); err != nil {
+			return nil, err
+		}
+	}
+
+	// The status watcher lists and watches the watched objects, and lists
+	// the objects they generate to compute their status.
+	ids := opts.WatchedIDs
+	if ids == nil {
+		ids = object.UnstructuredSetToObjMetadataSet(applyObjs).
+			Union(object.UnstructuredSetToObjMetadataSet(pruneObjs))
+	}
+	allNamespaces := opts.WatcherRESTScopeStrategy == watcher.RESTScopeRoot ||
+		(opts.WatcherRESTScopeStrategy == watcher.RESTScopeAutomatic && len(uniqueNamespaces(ids)) > 1)
+	for _, id := range ids {
+		namespace := id.Namespace
+		if allNamespaces {
+			namespace = ""
+		}
+		if err := add(id.GroupKind, namespace, "", "list", "watch"); err != nil {
+			return nil, err
+		}
+		for _, gr := range generatedResources[id.GroupKind] {
+			perms[Permission{Verb: "list", Group: gr.Group, Resource: gr.Resource, Namespace: namespace}] = struct{}{}
+		}
+	}
+
+	permList := make([]Permission, 0, len(perms))
+	for perm := range perms {
+		permList = append(permList, perm)
+	}
+	sort.Slice(permList, func(i, j int) bool {
+		a, b := permList[i], permList[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Verb < b.Verb
+	})
+	return permList, nil
+}
+
+// resourceResolver looks up the resources of kinds with the RESTMapper, or
+// in the CRDs which will be applied.
+type resourceResolver struct {
+	mapper meta.RESTMapper
+	crds   []*unstructured.Unstructured
+}
+
+// resolve returns the resource of the kind, and whether it is namespaced.
+func (r *resourceResolver) resolve(gk schema.GroupKind) (schema.GroupResource, bool, error) {
+	mapping, err := r.mapper.RESTMapping(gk)
+	if err == nil {
+		return mapping.Resource.GroupResource(), mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+	}
+	if !meta.IsNoMatchError(err) {
+		return schema.GroupResource{}, false, err
+	}
+	for _, crd := range r.crds {
+		if crdGK, _ := object.GetCRDGroupKind(crd); crdGK != gk {
+			continue
+		}
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+		if plural != "" {
+			return schema.GroupResource{Group: gk.Group, Resource: plural}, scope == "Namespaced", nil
+		}
+	}
+	return schema.GroupResource{}, false, &object.UnknownTypeError{
+		GroupVersionKind: gk.WithVersion(""),
+	}
+}
+
+func findCRDs(objs object.UnstructuredSet) []*unstructured.Unstructured {
+	var crds []*unstructured.Unstructured
+	for _, obj := range objs {
+		if object.IsCRD(obj) {
+			crds = append(crds, obj)
+		}
+	}
+	return crds
+}
+
+// uniqueNamespaces returns the namespaces of the objects, including the
+// empty namespace of cluster-scoped objects, like the status watcher does to
+// select the RESTScopeStrategy automatically.
+func uniqueNamespaces(ids object.ObjMetadataSet) []string {
+	nsMap := make(map[string]struct{})
+	for _, id := range ids {
+		nsMap[id.Namespace] = struct{}{}
+	}
+	nsList := make([]string, 0, len(nsMap))
+	for ns := range nsMap {
+		nsList = append(nsList, ns)
+	}
+	return nsList
+}
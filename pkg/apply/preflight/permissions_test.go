@@ -0,0 +1,258 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var inventoryObj = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: test
+`
+
+var deploymentObj = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: test
+`
+
+var secretObj = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: test
+`
+
+var clusterRoleObj = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+`
+
+var crdObj = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+spec:
+  group: example.com
+  names:
+    kind: CronTab
+    plural: crontabs
+  scope: Namespaced
+`
+
+var crObj = `
+apiVersion: example.com/v1
+kind: CronTab
+metadata:
+  name: cron
+  namespace: test
+`
+
+var mutatedObj = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mutated
+  namespace: test
+  annotations:
+    config.kubernetes.io/apply-time-mutation: |
+      - sourceRef:
+          kind: Secret
+          name: creds
+        sourcePath: $.data.a
+        targetPath: $.data.a
+`
+
+func newRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{
+		{Version: "v1"},
+		{Group: "apps", Version: "v1"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1"},
+		{Group: "apiextensions.k8s.io", Version: "v1"},
+	})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+func perms(namespace, group, resource string, verbs ...string) []Permission {
+	var result []Permission
+	for _, verb := range verbs {
+		result = append(result, Permission{Verb: verb, Group: group, Resource: resource, Namespace: namespace})
+	}
+	return result
+}
+
+func named(namespace, group, resource, name string, verbs ...string) []Permission {
+	result := perms(namespace, group, resource, verbs...)
+	for i := range result {
+		result[i].Name = name
+	}
+	return result
+}
+
+func concat(permLists ...[]Permission) []Permission {
+	var result []Permission
+	for _, permList := range permLists {
+		result = append(result, permList...)
+	}
+	return result
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	testCases := map[string]struct {
+		inv           string
+		applyObjs     []string
+		pruneObjs     []string
+		opts          Options
+		expected      []Permission
+		expectedError error
+	}{
+		"client-side apply": {
+			inv:       inventoryObj,
+			applyObjs: []string{secretObj},
+			expected: concat(
+				perms("test", "", "configmaps", "create"),
+				named("test", "", "configmaps", "inventory", "get", "update"),
+				perms("test", "", "secrets", "create", "list", "watch"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+		},
+		"server-side apply": {
+			applyObjs: []string{secretObj},
+			opts:      Options{ServerSideApply: true},
+			expected: concat(
+				perms("test", "", "secrets", "list", "watch"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+		},
+		"prune": {
+			applyObjs: []string{secretObj},
+			pruneObjs: []string{deploymentObj},
+			expected: concat(
+				perms("test", "", "pods", "list"),
+				perms("test", "", "secrets", "create", "list", "watch"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+				perms("test", "apps", "deployments", "list", "watch"),
+				named("test", "apps", "deployments", "app", "delete", "get"),
+				perms("test", "apps", "replicasets", "list"),
+			),
+		},
+		"apply-time mutation": {
+			applyObjs: []string{mutatedObj},
+			expected: concat(
+				perms("test", "", "configmaps", "create", "list", "watch"),
+				named("test", "", "configmaps", "mutated", "get", "patch"),
+				named("test", "", "secrets", "creds", "get"),
+			),
+		},
+		"cluster-scoped objects are watched in all namespaces": {
+			applyObjs: []string{secretObj, clusterRoleObj},
+			opts:      Options{WatcherRESTScopeStrategy: watcher.RESTScopeAutomatic},
+			expected: concat(
+				perms("", "", "secrets", "list", "watch"),
+				perms("", "rbac.authorization.k8s.io", "clusterroles", "create", "list", "watch"),
+				named("", "rbac.authorization.k8s.io", "clusterroles", "app", "get", "patch"),
+				perms("test", "", "secrets", "create"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+		},
+		"watched prune candidates decide the watch scope": {
+			applyObjs: []string{secretObj},
+			opts: Options{
+				WatcherRESTScopeStrategy: watcher.RESTScopeAutomatic,
+				WatchedIDs: object.ObjMetadataSet{
+					{GroupKind: schema.GroupKind{Kind: "Secret"}, Namespace: "test", Name: "creds"},
+					{GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, Name: "app"},
+				},
+			},
+			expected: concat(
+				perms("", "", "secrets", "list", "watch"),
+				perms("", "rbac.authorization.k8s.io", "clusterroles", "list", "watch"),
+				perms("test", "", "secrets", "create"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+		},
+		"namespace scope": {
+			applyObjs: []string{secretObj, clusterRoleObj},
+			opts:      Options{WatcherRESTScopeStrategy: watcher.RESTScopeNamespace},
+			expected: concat(
+				perms("", "rbac.authorization.k8s.io", "clusterroles", "create", "list", "watch"),
+				named("", "rbac.authorization.k8s.io", "clusterroles", "app", "get", "patch"),
+				perms("test", "", "secrets", "create", "list", "watch"),
+				named("test", "", "secrets", "creds", "get", "patch"),
+			),
+		},
+		"custom resource with CRD": {
+			applyObjs: []string{crdObj, crObj},
+			opts:      Options{WatcherRESTScopeStrategy: watcher.RESTScopeNamespace},
+			expected: concat(
+				perms("", "apiextensions.k8s.io", "customresourcedefinitions", "create", "list", "watch"),
+				named("", "apiextensions.k8s.io", "customresourcedefinitions", "crontabs.example.com", "get", "patch"),
+				perms("test", "example.com", "crontabs", "create", "list", "watch"),
+				named("test", "example.com", "crontabs", "cron", "get", "patch"),
+			),
+		},
+		"custom resource without CRD": {
+			applyObjs: []string{crObj},
+			expectedError: &object.UnknownTypeError{
+				GroupVersionKind: schema.GroupVersionKind{Group: "example.com", Kind: "CronTab"},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var inv *unstructured.Unstructured
+			if tc.inv != "" {
+				inv = testutil.Unstructured(t, tc.inv)
+			}
+			var applyObjs, pruneObjs object.UnstructuredSet
+			for _, manifest := range tc.applyObjs {
+				applyObjs = append(applyObjs, testutil.Unstructured(t, manifest))
+			}
+			for _, manifest := range tc.pruneObjs {
+				pruneObjs = append(pruneObjs, testutil.Unstructured(t, manifest))
+			}
+
+			result, err := RequiredPermissions(newRESTMapper(), inv, applyObjs, pruneObjs, tc.opts)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			testutil.AssertEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPermission_String(t *testing.T) {
+	assert.Equal(t, "list pods", Permission{Verb: "list", Resource: "pods"}.String())
+	assert.Equal(t, `patch deployments.apps in namespace "test"`,
+		Permission{Verb: "patch", Group: "apps", Resource: "deployments", Namespace: "test"}.String())
+	assert.Equal(t, `delete deployments.apps "app" in namespace "test"`,
+		Permission{Verb: "delete", Group: "apps", Resource: "deployments", Namespace: "test", Name: "app"}.String())
+}
@@ -206,27 +206,36 @@ func (p *Pruner) Prune(
 	return nil
 }
 
-// removeInventoryAnnotation removes the `config.k8s.io/owning-inventory` annotation from pruneObj.
+// removeInventoryAnnotation removes the `config.k8s.io/owning-inventory` annotation
+// and the `applyset.kubernetes.io/part-of` label from pruneObj.
 func (p *Pruner) removeInventoryAnnotation(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	// Make a copy of the input object to avoid modifying the input.
 	// This prevents race conditions when writing to the underlying map.
 	obj = obj.DeepCopy()
 	id := object.UnstructuredToObjMetadata(obj)
 	annotations := obj.GetAnnotations()
-	if annotations != nil {
-		if _, ok := annotations[inventory.OwningInventoryKey]; ok {
-			klog.V(4).Infof("removing annotation (object: %q, annotation: %q)", id, inventory.OwningInventoryKey)
-			delete(annotations, inventory.OwningInventoryKey)
-			obj.SetAnnotations(annotations)
-			namespacedClient, err := p.namespacedClient(id)
-			if err != nil {
-				return obj, err
-			}
-			_, err = namespacedClient.Update(context.TODO(), obj, metav1.UpdateOptions{})
-			return obj, err
-		}
+	labels := obj.GetLabels()
+	_, hasAnnotation := annotations[inventory.OwningInventoryKey]
+	_, hasLabel := labels[inventory.ApplySetPartOfLabel]
+	if !hasAnnotation && !hasLabel {
+		return obj, nil
+	}
+	if hasAnnotation {
+		klog.V(4).Infof("removing annotation (object: %q, annotation: %q)", id, inventory.OwningInventoryKey)
+		delete(annotations, inventory.OwningInventoryKey)
+		obj.SetAnnotations(annotations)
+	}
+	if hasLabel {
+		klog.V(4).Infof("removing label (object: %q, label: %q)", id, inventory.ApplySetPartOfLabel)
+		delete(labels, inventory.ApplySetPartOfLabel)
+		obj.SetLabels(labels)
+	}
+	namespacedClient, err := p.namespacedClient(id)
+	if err != nil {
+		return obj, err
 	}
-	return obj, nil
+	_, err = namespacedClient.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	return obj, err
 }
 
 // GetPruneObjs calculates the set of prune objects, and retrieves them
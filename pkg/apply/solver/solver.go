@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
 	"sigs.k8s.io/cli-utils/pkg/apply/task"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/apply/verify"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -50,6 +51,9 @@ type TaskQueueBuilder struct {
 	ApplyFilters  []filter.ValidationFilter
 	ApplyMutators []mutator.Interface
 	PruneFilters  []filter.ValidationFilter
+	// Verifier is used to run the verification checks declared by the
+	// applied objects. If nil, no verify task is added.
+	Verifier *verify.Verifier
 
 	// The accumulated tasks and counter variables to name tasks.
 	applyCounter int
@@ -98,6 +102,20 @@ type Options struct {
 	PrunePropagationPolicy metav1.DeletionPropagation
 	PruneTimeout           time.Duration
 	InventoryPolicy        inventory.Policy
+	// ForceRemoveFinalizersAfter defines whether the finalizers of pruned
+	// objects should be removed, and if so, how long the objects may be
+	// terminating before their finalizers are removed.
+	ForceRemoveFinalizersAfter time.Duration
+	// RecreateOnImmutable deletes and re-creates applied objects when the
+	// apply fails because an immutable field changed.
+	RecreateOnImmutable bool
+	// WaitProgressInterval defines whether the wait tasks should send
+	// progress events for the objects which have not reconciled yet, and
+	// if so, how often.
+	WaitProgressInterval time.Duration
+	// VerifyTimeout is the timeout of each verification check. Zero means
+	// verify.DefaultTimeout.
+	VerifyTimeout time.Duration
 }
 
 // WithInventory sets the inventory info and returns the builder for chaining.
@@ -182,7 +200,7 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 			if !o.DryRunStrategy.ClientOrServerDryRun() {
 				applyIds := object.UnstructuredSetToObjMetadataSet(applySet)
 				tasks = append(tasks,
-					t.newWaitTask(applyIds, taskrunner.AllCurrent, o.ReconcileTimeout))
+					t.newWaitTask(applyIds, taskrunner.AllCurrent, o.ReconcileTimeout, o))
 			}
 		}
 	}
@@ -206,11 +224,25 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 			if !o.DryRunStrategy.ClientOrServerDryRun() {
 				pruneIds := object.UnstructuredSetToObjMetadataSet(pruneSet)
 				tasks = append(tasks,
-					t.newWaitTask(pruneIds, taskrunner.AllNotFound, o.PruneTimeout))
+					t.newPruneWaitTask(pruneIds, o))
 			}
 		}
 	}
 
+	// Verification checks run after the final wait.
+	// dry-run skips verify tasks
+	if !o.Destroy && !o.DryRunStrategy.ClientOrServerDryRun() && t.Verifier != nil {
+		if verifyObjs := verifiableObjects(applyObjs); len(verifyObjs) > 0 {
+			klog.V(2).Infof("adding verify task (%d objects)", len(verifyObjs))
+			tasks = append(tasks, &task.VerifyTask{
+				TaskName: "verify-0",
+				Objects:  verifyObjs,
+				Verifier: t.Verifier,
+				Timeout:  o.VerifyTimeout,
+			})
+		}
+	}
+
 	// TODO: add InvSetTask when Destroy=true to retain undeleted objects
 	if !o.Destroy {
 		klog.V(2).Infoln("adding inventory set task")
@@ -242,16 +274,18 @@ func (t *TaskQueueBuilder) newApplyTask(applyObjs object.UnstructuredSet,
 	applyObjs = t.Collector.FilterInvalidObjects(applyObjs)
 	klog.V(2).Infof("adding apply task (%d objects)", len(applyObjs))
 	task := &task.ApplyTask{
-		TaskName:          fmt.Sprintf("apply-%d", t.applyCounter),
-		Objects:           applyObjs,
-		Filters:           applyFilters,
-		Mutators:          applyMutators,
-		ServerSideOptions: o.ServerSideOptions,
-		DryRunStrategy:    o.DryRunStrategy,
-		DynamicClient:     t.DynamicClient,
-		OpenAPIGetter:     t.OpenAPIGetter,
-		InfoHelper:        t.InfoHelper,
-		Mapper:            t.Mapper,
+		TaskName:            fmt.Sprintf("apply-%d", t.applyCounter),
+		Objects:             applyObjs,
+		Filters:             applyFilters,
+		Mutators:            applyMutators,
+		ServerSideOptions:   o.ServerSideOptions,
+		DryRunStrategy:      o.DryRunStrategy,
+		DynamicClient:       t.DynamicClient,
+		OpenAPIGetter:       t.OpenAPIGetter,
+		InfoHelper:          t.InfoHelper,
+		Mapper:              t.Mapper,
+		RecreateOnImmutable: o.RecreateOnImmutable,
+		InventoryPolicy:     o.InventoryPolicy,
 	}
 	t.applyCounter++
 	return task
@@ -260,7 +294,7 @@ func (t *TaskQueueBuilder) newApplyTask(applyObjs object.UnstructuredSet,
 // AppendWaitTask appends a task to wait on the passed objects to the task queue.
 // Returns a pointer to the Builder to chain function calls.
 func (t *TaskQueueBuilder) newWaitTask(waitIds object.ObjMetadataSet, condition taskrunner.Condition,
-	waitTimeout time.Duration) taskrunner.Task {
+	waitTimeout time.Duration, o Options) taskrunner.Task {
 	waitIds = t.Collector.FilterInvalidIds(waitIds)
 	klog.V(2).Infoln("adding wait task")
 	task := taskrunner.NewWaitTask(
@@ -270,10 +304,21 @@ func (t *TaskQueueBuilder) newWaitTask(waitIds object.ObjMetadataSet, condition
 		waitTimeout,
 		t.Mapper,
 	)
+	task.ProgressInterval = o.WaitProgressInterval
 	t.waitCounter++
 	return task
 }
 
+// newPruneWaitTask returns a task to wait for the deletion of the passed
+// objects, which removes their finalizers if they are terminating for
+// longer than ForceRemoveFinalizersAfter.
+func (t *TaskQueueBuilder) newPruneWaitTask(pruneIds object.ObjMetadataSet, o Options) taskrunner.Task {
+	task := t.newWaitTask(pruneIds, taskrunner.AllNotFound, o.PruneTimeout, o).(*taskrunner.WaitTask)
+	task.Client = t.DynamicClient
+	task.ForceRemoveFinalizersAfter = o.ForceRemoveFinalizersAfter
+	return task
+}
+
 // AppendPruneTask appends a task to delete objects from the cluster to the task queue.
 // Returns a pointer to the Builder to chain function calls.
 func (t *TaskQueueBuilder) newPruneTask(pruneObjs object.UnstructuredSet,
@@ -292,3 +337,14 @@ func (t *TaskQueueBuilder) newPruneTask(pruneObjs object.UnstructuredSet,
 	t.pruneCounter++
 	return task
 }
+
+// verifiableObjects returns the objects which declare a verification check.
+func verifiableObjects(objs object.UnstructuredSet) object.UnstructuredSet {
+	var verifyObjs object.UnstructuredSet
+	for _, obj := range objs {
+		if _, found, err := common.ReadVerifyCheck(obj); err == nil && found {
+			verifyObjs = append(verifyObjs, obj)
+		}
+	}
+	return verifyObjs
+}
@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
 	"sigs.k8s.io/cli-utils/pkg/apply/task"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/apply/verify"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -833,6 +834,90 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 	}
 }
 
+func TestTaskQueueBuilder_VerifyBuild(t *testing.T) {
+	invInfo := inventory.WrapInventoryInfoObj(newInvObject(
+		"abc-123", "default", "test"))
+	job := testutil.Unstructured(t, `
+kind: Job
+apiVersion: batch/v1
+metadata:
+  name: migrate
+  namespace: test-namespace
+  annotations:
+    config.kubernetes.io/verify: job-complete
+`)
+
+	testCases := map[string]struct {
+		applyObjs          []*unstructured.Unstructured
+		verifier           *verify.Verifier
+		options            Options
+		expectedTaskNames  []string
+		expectedVerifyObjs object.UnstructuredSet
+	}{
+		"verify task after the final wait": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+				job,
+			},
+			verifier: &verify.Verifier{},
+			options:  Options{VerifyTimeout: time.Minute},
+			expectedTaskNames: []string{
+				"inventory-add-0", "apply-0", "wait-0", "verify-0", "inventory-set-0",
+			},
+			expectedVerifyObjs: object.UnstructuredSet{job},
+		},
+		"no verify task without checks": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+			},
+			verifier: &verify.Verifier{},
+			expectedTaskNames: []string{
+				"inventory-add-0", "apply-0", "wait-0", "inventory-set-0",
+			},
+		},
+		"no verify task without verifier": {
+			applyObjs: []*unstructured.Unstructured{job},
+			expectedTaskNames: []string{
+				"inventory-add-0", "apply-0", "wait-0", "inventory-set-0",
+			},
+		},
+		"dry-run skips verify task": {
+			applyObjs: []*unstructured.Unstructured{job},
+			verifier:  &verify.Verifier{},
+			options:   Options{DryRunStrategy: common.DryRunClient},
+			expectedTaskNames: []string{
+				"inventory-add-0", "apply-0", "inventory-set-0",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tqb := TaskQueueBuilder{
+				Pruner:    pruner,
+				Mapper:    testutil.NewFakeRESTMapper(),
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Collector: &validation.Collector{},
+				Verifier:  tc.verifier,
+			}
+			tq := tqb.WithInventory(invInfo).
+				WithApplyObjects(tc.applyObjs).
+				Build(taskrunner.NewTaskContext(nil, nil), tc.options)
+
+			var taskNames []string
+			for _, tsk := range tq.tasks {
+				taskNames = append(taskNames, tsk.Name())
+				if verifyTask, ok := tsk.(*task.VerifyTask); ok {
+					assert.Equal(t, tc.expectedVerifyObjs, verifyTask.Objects)
+					assert.Equal(t, tc.verifier, verifyTask.Verifier)
+					assert.Equal(t, tc.options.VerifyTimeout, verifyTask.Timeout)
+				}
+			}
+			assert.Equal(t, tc.expectedTaskNames, taskNames)
+		})
+	}
+}
+
 func TestTaskQueueBuilder_PruneBuild(t *testing.T) {
 	// Use a custom Asserter to customize the comparison options
 	asserter := testutil.NewAsserter(
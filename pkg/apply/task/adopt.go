@@ -0,0 +1,60 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+)
+
+// canAdopt returns true if the inventory policy allows taking ownership of
+// objects which are not owned by the applying inventory.
+func (a *ApplyTask) canAdopt() bool {
+	return a.InventoryPolicy == inventory.PolicyAdoptIfNoInventory ||
+		a.InventoryPolicy == inventory.PolicyAdoptAll
+}
+
+// adoption returns the AdoptionInfo if applying the object takes ownership of
+// the live object from another inventory, or from none. Returns nil if the
+// object does not exist yet, or is already owned by the applying inventory.
+func adoption(obj, live *unstructured.Unstructured) *event.AdoptionInfo {
+	if live == nil {
+		return nil
+	}
+	newOwner, found := inventory.OwningInventory(obj)
+	if !found {
+		return nil
+	}
+	previousOwner, _ := inventory.OwningInventory(live)
+	if previousOwner == newOwner {
+		return nil
+	}
+	return &event.AdoptionInfo{
+		PreviousOwner: previousOwner,
+		NewOwner:      newOwner,
+	}
+}
+
+// forwardApplyEvents returns a channel which forwards the events sent to it
+// to the eventChannel, after passing the apply events to the update
+// function. The returned function closes the channel and waits for the
+// forwarded events to be sent.
+func forwardApplyEvents(eventChannel chan<- event.Event, update func(*event.ApplyEvent)) (chan event.Event, func()) {
+	forwardChannel := make(chan event.Event)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range forwardChannel {
+			if e.Type == event.ApplyType {
+				update(&e.ApplyEvent)
+			}
+			eventChannel <- e
+		}
+	}()
+	return forwardChannel, func() {
+		close(forwardChannel)
+		<-done
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
+	"sigs.k8s.io/cli-utils/pkg/common"
+)
+
+// applyStrategySkipError returns an ApplyStrategySkippedError if the apply
+// strategy prevents applying the object, given the live object, or nil if
+// the object does not exist in the cluster.
+func applyStrategySkipError(strategy common.ApplyStrategy, live *unstructured.Unstructured) error {
+	switch strategy {
+	case common.ApplyStrategyCreateOnly:
+		if live != nil {
+			return &applyerror.ApplyStrategySkippedError{
+				Strategy: strategy,
+				Reason:   "object already exists",
+			}
+		}
+	case common.ApplyStrategyUpdateOnly:
+		if live == nil {
+			return &applyerror.ApplyStrategySkippedError{
+				Strategy: strategy,
+				Reason:   "object does not exist",
+			}
+		}
+	}
+	return nil
+}
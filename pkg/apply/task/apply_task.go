@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
@@ -58,6 +59,13 @@ type ApplyTask struct {
 	Mutators          []mutator.Interface
 	DryRunStrategy    common.DryRunStrategy
 	ServerSideOptions common.ServerSideOptions
+	// RecreateOnImmutable deletes and re-creates objects when the apply
+	// fails because an immutable field changed. Objects may also opt in
+	// with the RecreateOnImmutableAnnotation.
+	RecreateOnImmutable bool
+	// InventoryPolicy is the inventory policy of the apply. Objects adopted
+	// by the applying inventory are reported in the apply events.
+	InventoryPolicy inventory.Policy
 }
 
 // applyOptionsFactoryFunc is a factory function for creating a new
@@ -136,6 +144,15 @@ func (a *ApplyTask) Start(taskContext *taskrunner.TaskContext) {
 				continue
 			}
 
+			// Fingerprint the configuration being applied, so the inventory
+			// can record what was last applied. The hash is computed before
+			// the apply-time mutations, so it matches the hash of the local
+			// configuration.
+			appliedHash, err := inventory.AppliedHash(obj)
+			if err != nil {
+				klog.Warningf("apply hash errored (object: %s): %v", id, err)
+			}
+
 			// Execute mutators, if any apply
 			err = a.mutate(ctx, obj)
 			if err != nil {
@@ -148,19 +165,95 @@ func (a *ApplyTask) Start(taskContext *taskrunner.TaskContext) {
 				continue
 			}
 
-			// Create a new instance of the applyOptions interface and use it
-			// to apply the objects.
-			ao := applyOptionsFactoryFunc(a.Name(), taskContext.EventChannel(),
-				a.ServerSideOptions, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
-			ao.SetObjects([]*resource.Info{info})
-			klog.V(5).Infof("applying object: %v", id)
-			err = ao.Run()
-			if err != nil && a.ServerSideOptions.ServerSideApply && isAPIService(obj) && isStreamError(err) {
-				// Server-side Apply doesn't work with APIService before k8s 1.21
-				// https://github.com/kubernetes/kubernetes/issues/89264
-				// Thus APIService is handled specially using client-side apply.
-				err = a.clientSideApply(info, taskContext.EventChannel())
+			// Check the apply strategy, which may skip or replace the object.
+			strategy, err := common.ReadApplyStrategy(obj)
+			if err != nil {
+				taskContext.SendEvent(a.createApplyFailedEvent(id, err))
+				taskContext.InventoryManager().AddFailedApply(id)
+				continue
+			}
+			var live *unstructured.Unstructured
+			if strategy != common.ApplyStrategyApply {
+				live, err = a.getObject(ctx, id)
+				if err != nil {
+					if klog.V(4).Enabled() {
+						// only log event emitted errors if the verbosity > 4
+						klog.Errorf("apply strategy errored (object: %s): %v", id, err)
+					}
+					taskContext.SendEvent(a.createApplyFailedEvent(id, err))
+					taskContext.InventoryManager().AddFailedApply(id)
+					continue
+				}
+			} else if a.canAdopt() {
+				// The live object is only used to report adoption, so the
+				// apply is not prevented by errors.
+				live, err = a.getObject(ctx, id)
+				if err != nil {
+					klog.V(4).Infof("apply adoption check errored (object: %s): %v", id, err)
+				}
+			}
+			if skipErr := applyStrategySkipError(strategy, live); skipErr != nil {
+				klog.V(4).Infof("apply skipped (object: %s): %v", id, skipErr)
+				taskContext.SendEvent(a.createApplySkippedEvent(id, obj, skipErr))
+				if strategy == common.ApplyStrategyCreateOnly {
+					// The object already exists, as desired, so the apply is
+					// a successful no-op for the dependents, the wait and the
+					// inventory.
+					taskContext.InventoryManager().AddSuccessfulApply(id, live.GetUID(), live.GetGeneration())
+				} else {
+					taskContext.InventoryManager().AddSkippedApply(id)
+				}
+				continue
+			}
+
+			if a.canMigrateClientSideApply() {
+				if live == nil {
+					live, err = a.getObject(ctx, id)
+				}
+				if err == nil && live != nil {
+					err = a.migrateClientSideApply(ctx, id, live)
+				}
+				if err != nil {
+					// Applying without the migration would leave the fields
+					// removed from the configuration owned by the client-side
+					// apply managers, so they would not be removed.
+					err = fmt.Errorf("failed to migrate client-side apply field managers: %w", err)
+					if klog.V(4).Enabled() {
+						klog.Errorf("apply errored (object: %s): %v", id, err)
+					}
+					taskContext.SendEvent(a.createApplyFailedEvent(id, err))
+					taskContext.InventoryManager().AddFailedApply(id)
+					continue
+				}
+			}
+
+			var eventChannel chan<- event.Event = taskContext.EventChannel()
+			stopForwarding := func() {}
+			if adopted := adoption(obj, live); adopted != nil {
+				// Forward the apply events, marked as adopted.
+				klog.V(4).Infof("apply adopting (object: %s, previous owner: %q)", id, adopted.PreviousOwner)
+				eventChannel, stopForwarding = forwardApplyEvents(eventChannel, func(e *event.ApplyEvent) {
+					if e.Status == event.ApplySuccessful {
+						e.Adopted = adopted
+					}
+				})
 			}
+			replaced := false
+			if strategy == common.ApplyStrategyReplace && live != nil {
+				klog.V(4).Infof("apply replacing (object: %s)", id)
+				err = a.recreate(ctx, info, obj, live, eventChannel)
+				replaced = err == nil
+			} else {
+				err = a.apply(info, obj, eventChannel)
+				if err != nil && applyerror.IsImmutableFieldError(err) && a.canRecreate(obj) {
+					klog.V(4).Infof("apply recreating (object: %s): %v", id, err)
+					if live, err = a.getObject(ctx, id); err == nil {
+						err = a.recreate(ctx, info, obj, live, eventChannel)
+						replaced = err == nil
+					}
+				}
+			}
+			stopForwarding()
 			if err != nil {
 				err = applyerror.NewApplyRunError(err)
 				if klog.V(4).Enabled() {
@@ -174,7 +267,15 @@ func (a *ApplyTask) Start(taskContext *taskrunner.TaskContext) {
 				if err == nil {
 					uid := acc.GetUID()
 					gen := acc.GetGeneration()
-					taskContext.InventoryManager().AddSuccessfulApply(id, uid, gen)
+					if replaced {
+						taskContext.InventoryManager().AddSuccessfulReplace(id, uid, gen)
+					} else {
+						taskContext.InventoryManager().AddSuccessfulApply(id, uid, gen)
+					}
+					err = taskContext.InventoryManager().SetAppliedFingerprint(id, appliedHash, acc.GetResourceVersion())
+					if err != nil {
+						klog.Errorf("Failed to record applied fingerprint: %v", err)
+					}
 				}
 			}
 		}
@@ -218,6 +319,52 @@ func newApplyOptions(taskName string, eventChannel chan<- event.Event, serverSid
 	}
 }
 
+// apply applies the object, forcing conflicts with the allowed managers.
+func (a *ApplyTask) apply(info *resource.Info, obj *unstructured.Unstructured, eventChannel chan<- event.Event) error {
+	id := object.UnstructuredToObjMetadata(obj)
+	// Create a new instance of the applyOptions interface and use it
+	// to apply the objects.
+	ao := applyOptionsFactoryFunc(a.Name(), eventChannel,
+		a.ServerSideOptions, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
+	ao.SetObjects([]*resource.Info{info})
+	klog.V(5).Infof("applying object: %v", id)
+	err := ao.Run()
+	if err != nil && a.ServerSideOptions.ServerSideApply && isAPIService(obj) && isStreamError(err) {
+		// Server-side Apply doesn't work with APIService before k8s 1.21
+		// https://github.com/kubernetes/kubernetes/issues/89264
+		// Thus APIService is handled specially using client-side apply.
+		err = a.clientSideApply(info, eventChannel)
+	}
+	if conflictErr := applyerror.NewApplyConflictError(err); conflictErr != nil {
+		if a.canForceConflicts(conflictErr) {
+			// Retry, taking ownership of fields from the allowed managers.
+			klog.V(4).Infof("apply forcing conflicts (object: %s, managers: %v)", id, conflictErr.Managers())
+			forceOptions := a.ServerSideOptions
+			forceOptions.ForceConflicts = true
+			ao = applyOptionsFactoryFunc(a.Name(), eventChannel,
+				forceOptions, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
+			ao.SetObjects([]*resource.Info{info})
+			err = ao.Run()
+			if retryConflictErr := applyerror.NewApplyConflictError(err); retryConflictErr != nil {
+				err = retryConflictErr
+			}
+		} else {
+			err = conflictErr
+		}
+	}
+	return err
+}
+
+// canForceConflicts returns true if all the conflicting fields are owned by
+// managers in ServerSideOptions.ForceConflictsManagers.
+func (a *ApplyTask) canForceConflicts(conflictErr *applyerror.ApplyConflictError) bool {
+	if len(a.ServerSideOptions.ForceConflictsManagers) == 0 {
+		return false
+	}
+	allowed := sets.NewString(a.ServerSideOptions.ForceConflictsManagers...)
+	return allowed.HasAll(conflictErr.Managers()...)
+}
+
 func (a *ApplyTask) sendTaskResult(taskContext *taskrunner.TaskContext) {
 	klog.V(2).Infof("apply task completing (name: %q)", a.Name())
 	taskContext.TaskChannel() <- taskrunner.TaskResult{}
@@ -4,23 +4,39 @@
 package task
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/graph"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
 )
 
@@ -205,11 +221,72 @@ func TestApplyTask_FetchGeneration(t *testing.T) {
 
 				gen, _ := taskContext.InventoryManager().AppliedGeneration(id)
 				assert.Equal(t, info.generation, gen)
+
+				status, found := taskContext.InventoryManager().ObjectStatus(id)
+				assert.True(t, found)
+				assert.NotEmpty(t, status.AppliedHash)
 			}
 		})
 	}
 }
 
+// replicasMutator sets the replicas of the mutated objects.
+type replicasMutator struct {
+	mutated int
+}
+
+func (m *replicasMutator) Name() string {
+	return "replicasMutator"
+}
+
+func (m *replicasMutator) Mutate(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+	m.mutated++
+	return true, "test", unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas")
+}
+
+func TestApplyTask_AppliedHashBeforeMutation(t *testing.T) {
+	eventChannel := make(chan event.Event)
+	defer close(eventChannel)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+	objs := toUnstructureds([]resourceInfo{
+		{
+			group:      "apps",
+			apiVersion: "apps/v1",
+			kind:       "Deployment",
+			name:       "foo",
+			namespace:  "default",
+			uid:        types.UID("my-uid"),
+			generation: int64(1),
+		},
+	})
+	id := object.UnstructuredToObjMetadata(objs[0])
+	localHash, err := inventory.AppliedHash(objs[0])
+	require.NoError(t, err)
+
+	oldAO := applyOptionsFactoryFunc
+	applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+		dynamic.Interface, discovery.OpenAPISchemaInterface) applyOptions {
+		return &fakeApplyOptions{}
+	}
+	defer func() { applyOptionsFactoryFunc = oldAO }()
+	replicas := &replicasMutator{}
+	applyTask := &ApplyTask{
+		Objects:    objs,
+		InfoHelper: &fakeInfoHelper{},
+		Mutators:   []mutator.Interface{replicas},
+	}
+	applyTask.Start(taskContext)
+
+	<-taskContext.TaskChannel()
+
+	assert.Equal(t, 1, replicas.mutated)
+	status, found := taskContext.InventoryManager().ObjectStatus(id)
+	require.True(t, found)
+	assert.Equal(t, localHash, status.AppliedHash)
+}
+
 func TestApplyTask_DryRun(t *testing.T) {
 	testCases := map[string]struct {
 		objs            []*unstructured.Unstructured
@@ -514,6 +591,735 @@ func TestApplyTaskWithError(t *testing.T) {
 	}
 }
 
+func TestApplyTaskForceConflictsManagers(t *testing.T) {
+	conflictMsg := "Apply failed with 2 conflicts: conflicts with \"old-ci\" using apps/v1:\n" +
+		"- .spec.replicas\n" +
+		"- .spec.template.spec.containers[name=\"app\"].image\n" +
+		"Please review the fields above--they currently have other managers."
+	expectedConflicts := []applyerror.FieldConflict{
+		{Field: ".spec.replicas", Manager: "old-ci"},
+		{Field: `.spec.template.spec.containers[name="app"].image`, Manager: "old-ci"},
+	}
+
+	testCases := map[string]struct {
+		forceConflictsManagers []string
+		expectedRuns           int
+		expectedFailed         bool
+	}{
+		"no allowed managers": {
+			expectedRuns:   1,
+			expectedFailed: true,
+		},
+		"conflicting manager not allowed": {
+			forceConflictsManagers: []string{"other-ci"},
+			expectedRuns:           1,
+			expectedFailed:         true,
+		},
+		"conflicting manager allowed": {
+			forceConflictsManagers: []string{"other-ci", "old-ci"},
+			expectedRuns:           2,
+			expectedFailed:         false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+			objs := toUnstructureds([]resourceInfo{
+				{
+					group:      "apps",
+					apiVersion: "apps/v1",
+					kind:       "Deployment",
+					name:       "foo",
+					namespace:  "default",
+				},
+			})
+			id := object.UnstructuredToObjMetadata(objs[0])
+
+			runs := 0
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(_ string, _ chan<- event.Event, serverSideOptions common.ServerSideOptions,
+				_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+				return &conflictApplyOptions{
+					force: serverSideOptions.ForceConflicts,
+					err:   fmt.Errorf("%s", conflictMsg),
+					runs:  &runs,
+				}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			applyTask := &ApplyTask{
+				Objects:    objs,
+				InfoHelper: &fakeInfoHelper{},
+				ServerSideOptions: common.ServerSideOptions{
+					ServerSideApply:        true,
+					ForceConflictsManagers: tc.forceConflictsManagers,
+				},
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedRuns, runs)
+			im := taskContext.InventoryManager()
+			assert.Equal(t, tc.expectedFailed, im.IsFailedApply(id))
+			if !tc.expectedFailed {
+				assert.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			var conflictErr *applyerror.ApplyConflictError
+			require.True(t, errors.As(events[0].ApplyEvent.Error, &conflictErr))
+			assert.Equal(t, expectedConflicts, conflictErr.Conflicts)
+		})
+	}
+}
+
+func TestApplyTaskRecreateOnImmutable(t *testing.T) {
+	immutableErr := apierrors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "foo", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+	})
+	jobGVK := schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}
+	jobGVR := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+	testCases := map[string]struct {
+		recreateOnImmutable bool
+		annotation          string
+		dryRunStrategy      common.DryRunStrategy
+		err                 error
+		expectedRuns        int
+		expectedReplaced    bool
+		expectedDeleted     bool
+	}{
+		"not opted in": {
+			err:          immutableErr,
+			expectedRuns: 1,
+		},
+		"opted in by option": {
+			recreateOnImmutable: true,
+			err:                 immutableErr,
+			expectedRuns:        2,
+			expectedReplaced:    true,
+			expectedDeleted:     true,
+		},
+		"opted in by annotation": {
+			annotation:       "true",
+			err:              immutableErr,
+			expectedRuns:     2,
+			expectedReplaced: true,
+			expectedDeleted:  true,
+		},
+		"other error": {
+			recreateOnImmutable: true,
+			err:                 errors.New("some other error"),
+			expectedRuns:        1,
+		},
+		"dry-run": {
+			recreateOnImmutable: true,
+			dryRunStrategy:      common.DryRunServer,
+			err:                 immutableErr,
+			expectedRuns:        1,
+			expectedReplaced:    true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			oldInterval := recreatePollInterval
+			recreatePollInterval = 10 * time.Millisecond
+			defer func() { recreatePollInterval = oldInterval }()
+
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+			objs := toUnstructureds([]resourceInfo{
+				{
+					group:      "batch",
+					apiVersion: "batch/v1",
+					kind:       "Job",
+					name:       "foo",
+					namespace:  "default",
+				},
+			})
+			if tc.annotation != "" {
+				objs[0].SetAnnotations(map[string]string{
+					common.RecreateOnImmutableAnnotation: tc.annotation,
+				})
+			}
+			id := object.UnstructuredToObjMetadata(objs[0])
+			live := objs[0].DeepCopy()
+			live.SetUID("old")
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+
+			runs := 0
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(_ string, eventChannel chan<- event.Event, _ common.ServerSideOptions,
+				_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+				return &immutableApplyOptions{
+					eventChannel: eventChannel,
+					err:          tc.err,
+					runs:         &runs,
+				}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			applyTask := &ApplyTask{
+				Objects:             objs,
+				InfoHelper:          &fakeInfoHelper{},
+				DynamicClient:       client,
+				Mapper:              testutil.NewFakeRESTMapper(jobGVK),
+				DryRunStrategy:      tc.dryRunStrategy,
+				RecreateOnImmutable: tc.recreateOnImmutable,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedRuns, runs)
+			im := taskContext.InventoryManager()
+			require.Len(t, events, 1)
+			if !tc.expectedReplaced {
+				assert.True(t, im.IsFailedApply(id))
+				assert.Equal(t, event.ApplyFailed, events[0].ApplyEvent.Status)
+				return
+			}
+			assert.Equal(t, event.ApplySuccessful, events[0].ApplyEvent.Status)
+			assert.True(t, events[0].ApplyEvent.Replaced)
+			objStatus, found := im.ObjectStatus(id)
+			require.True(t, found)
+			assert.Equal(t, actuation.ActuationSucceeded, objStatus.Actuation)
+			assert.True(t, objStatus.Replaced)
+
+			_, err := client.Resource(jobGVR).Namespace(id.Namespace).
+				Get(context.TODO(), id.Name, metav1.GetOptions{})
+			if tc.expectedDeleted {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.Equal(t, types.UID("new"), objStatus.UID)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyTaskApplyStrategy(t *testing.T) {
+	secretGVK := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}
+	secretGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+	testCases := map[string]struct {
+		strategy          common.ApplyStrategy
+		exists            bool
+		expectedRuns      int
+		expectedStatus    event.ApplyEventStatus
+		expectedError     error
+		expectedActuation actuation.ActuationStatus
+		expectedUID       types.UID
+		expectedReplaced  bool
+	}{
+		"create-only creates missing object": {
+			strategy:          common.ApplyStrategyCreateOnly,
+			expectedRuns:      1,
+			expectedStatus:    event.ApplySuccessful,
+			expectedActuation: actuation.ActuationSucceeded,
+		},
+		"create-only skips existing object": {
+			strategy:       common.ApplyStrategyCreateOnly,
+			exists:         true,
+			expectedStatus: event.ApplySkipped,
+			expectedError: &applyerror.ApplyStrategySkippedError{
+				Strategy: common.ApplyStrategyCreateOnly,
+				Reason:   "object already exists",
+			},
+			expectedActuation: actuation.ActuationSucceeded,
+			expectedUID:       "old",
+		},
+		"update-only skips missing object": {
+			strategy:       common.ApplyStrategyUpdateOnly,
+			expectedStatus: event.ApplySkipped,
+			expectedError: &applyerror.ApplyStrategySkippedError{
+				Strategy: common.ApplyStrategyUpdateOnly,
+				Reason:   "object does not exist",
+			},
+			expectedActuation: actuation.ActuationSkipped,
+		},
+		"update-only updates existing object": {
+			strategy:          common.ApplyStrategyUpdateOnly,
+			exists:            true,
+			expectedRuns:      1,
+			expectedStatus:    event.ApplySuccessful,
+			expectedActuation: actuation.ActuationSucceeded,
+		},
+		"replace creates missing object": {
+			strategy:          common.ApplyStrategyReplace,
+			expectedRuns:      1,
+			expectedStatus:    event.ApplySuccessful,
+			expectedActuation: actuation.ActuationSucceeded,
+		},
+		"replace recreates existing object": {
+			strategy:          common.ApplyStrategyReplace,
+			exists:            true,
+			expectedRuns:      1,
+			expectedStatus:    event.ApplySuccessful,
+			expectedActuation: actuation.ActuationSucceeded,
+			expectedReplaced:  true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			oldInterval := recreatePollInterval
+			recreatePollInterval = 10 * time.Millisecond
+			defer func() { recreatePollInterval = oldInterval }()
+
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+			objs := toUnstructureds([]resourceInfo{
+				{
+					apiVersion: "v1",
+					kind:       "Secret",
+					name:       "foo",
+					namespace:  "default",
+				},
+			})
+			objs[0].SetAnnotations(map[string]string{
+				common.ApplyStrategyAnnotation: string(tc.strategy),
+			})
+			id := object.UnstructuredToObjMetadata(objs[0])
+			var clusterObjs []runtime.Object
+			if tc.exists {
+				live := objs[0].DeepCopy()
+				live.SetUID("old")
+				clusterObjs = append(clusterObjs, live)
+			}
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), clusterObjs...)
+
+			runs := 0
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(_ string, eventChannel chan<- event.Event, _ common.ServerSideOptions,
+				_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+				return &immutableApplyOptions{
+					eventChannel: eventChannel,
+					runs:         &runs,
+				}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			applyTask := &ApplyTask{
+				Objects:       objs,
+				InfoHelper:    &fakeInfoHelper{},
+				DynamicClient: client,
+				Mapper:        testutil.NewFakeRESTMapper(secretGVK),
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedRuns, runs)
+			require.Len(t, events, 1)
+			assert.Equal(t, tc.expectedStatus, events[0].ApplyEvent.Status)
+			testutil.AssertEqual(t, tc.expectedError, events[0].ApplyEvent.Error)
+			assert.Equal(t, tc.expectedReplaced, events[0].ApplyEvent.Replaced)
+
+			im := taskContext.InventoryManager()
+			objStatus, found := im.ObjectStatus(id)
+			require.True(t, found)
+			assert.Equal(t, tc.expectedActuation, objStatus.Actuation)
+			assert.Equal(t, tc.expectedReplaced, objStatus.Replaced)
+			if tc.expectedUID != "" {
+				assert.Equal(t, tc.expectedUID, objStatus.UID)
+			}
+			if tc.expectedReplaced {
+				_, err := client.Resource(secretGVR).Namespace(id.Namespace).
+					Get(context.TODO(), id.Name, metav1.GetOptions{})
+				assert.True(t, apierrors.IsNotFound(err))
+			}
+		})
+	}
+}
+
+// TestApplyTaskCreateOnlyDependent verifies that the dependents of an
+// existing create-only object are applied.
+func TestApplyTaskCreateOnlyDependent(t *testing.T) {
+	secretGVK := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}
+
+	objs := toUnstructureds([]resourceInfo{
+		{
+			apiVersion: "v1",
+			kind:       "Secret",
+			name:       "dependency",
+			namespace:  "default",
+		},
+		{
+			apiVersion: "v1",
+			kind:       "Secret",
+			name:       "dependent",
+			namespace:  "default",
+		},
+	})
+	dependency, dependent := objs[0], objs[1]
+	dependency.SetAnnotations(map[string]string{
+		common.ApplyStrategyAnnotation: string(common.ApplyStrategyCreateOnly),
+	})
+	dependencyID := object.UnstructuredToObjMetadata(dependency)
+	dependentID := object.UnstructuredToObjMetadata(dependent)
+	live := dependency.DeepCopy()
+	live.SetUID("old")
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+
+	runs := 0
+	oldAO := applyOptionsFactoryFunc
+	applyOptionsFactoryFunc = func(_ string, eventChannel chan<- event.Event, _ common.ServerSideOptions,
+		_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+		return &immutableApplyOptions{
+			eventChannel: eventChannel,
+			runs:         &runs,
+		}
+	}
+	defer func() { applyOptionsFactoryFunc = oldAO }()
+
+	eventChannel := make(chan event.Event)
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	g := graph.New()
+	g.AddEdge(dependentID, dependencyID)
+	taskContext.SetGraph(g)
+
+	var events []event.Event
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for msg := range eventChannel {
+			events = append(events, msg)
+		}
+	}()
+
+	for _, obj := range objs {
+		applyTask := &ApplyTask{
+			Objects:       object.UnstructuredSet{obj},
+			InfoHelper:    &fakeInfoHelper{},
+			DynamicClient: client,
+			Mapper:        testutil.NewFakeRESTMapper(secretGVK),
+			Filters: []filter.ValidationFilter{
+				filter.DependencyFilter{
+					TaskContext:       taskContext,
+					ActuationStrategy: actuation.ActuationStrategyApply,
+					// Ignore the reconcile status, which requires a WaitTask.
+					DryRunStrategy: common.DryRunClient,
+				},
+			},
+		}
+		applyTask.Start(taskContext)
+		<-taskContext.TaskChannel()
+	}
+	close(eventChannel)
+	wg.Wait()
+
+	require.Len(t, events, 2)
+	assert.Equal(t, event.ApplySkipped, events[0].ApplyEvent.Status)
+	assert.Equal(t, event.ApplySuccessful, events[1].ApplyEvent.Status)
+	assert.Equal(t, 1, runs)
+
+	im := taskContext.InventoryManager()
+	assert.True(t, im.IsSuccessfulApply(dependencyID))
+	assert.True(t, im.IsSuccessfulApply(dependentID))
+	uid, found := im.AppliedResourceUID(dependencyID)
+	assert.True(t, found)
+	assert.Equal(t, types.UID("old"), uid)
+}
+
+func TestApplyTaskAdoption(t *testing.T) {
+	secretGVK := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}
+
+	testCases := map[string]struct {
+		policy          inventory.Policy
+		exists          bool
+		liveOwner       string
+		expectedAdopted *event.AdoptionInfo
+	}{
+		"new object is not adopted": {
+			policy: inventory.PolicyAdoptIfNoInventory,
+		},
+		"object owned by the inventory is not adopted": {
+			policy:    inventory.PolicyAdoptAll,
+			exists:    true,
+			liveOwner: "inv-id",
+		},
+		"unowned object is adopted": {
+			policy: inventory.PolicyAdoptIfNoInventory,
+			exists: true,
+			expectedAdopted: &event.AdoptionInfo{
+				NewOwner: "inv-id",
+			},
+		},
+		"object owned by another inventory is adopted": {
+			policy:    inventory.PolicyAdoptAll,
+			exists:    true,
+			liveOwner: "other-id",
+			expectedAdopted: &event.AdoptionInfo{
+				PreviousOwner: "other-id",
+				NewOwner:      "inv-id",
+			},
+		},
+		"adoption is not checked with must match policy": {
+			policy: inventory.PolicyMustMatch,
+			exists: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+			objs := toUnstructureds([]resourceInfo{
+				{
+					apiVersion: "v1",
+					kind:       "Secret",
+					name:       "foo",
+					namespace:  "default",
+				},
+			})
+			objs[0].SetAnnotations(map[string]string{
+				inventory.OwningInventoryKey: "inv-id",
+			})
+			var clusterObjs []runtime.Object
+			if tc.exists {
+				live := objs[0].DeepCopy()
+				live.SetAnnotations(nil)
+				if tc.liveOwner != "" {
+					live.SetAnnotations(map[string]string{
+						inventory.OwningInventoryKey: tc.liveOwner,
+					})
+				}
+				clusterObjs = append(clusterObjs, live)
+			}
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), clusterObjs...)
+
+			runs := 0
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(_ string, eventChannel chan<- event.Event, _ common.ServerSideOptions,
+				_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+				return &immutableApplyOptions{
+					eventChannel: eventChannel,
+					runs:         &runs,
+				}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			applyTask := &ApplyTask{
+				Objects:         objs,
+				InfoHelper:      &fakeInfoHelper{},
+				DynamicClient:   client,
+				Mapper:          testutil.NewFakeRESTMapper(secretGVK),
+				InventoryPolicy: tc.policy,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, 1, runs)
+			require.Len(t, events, 1)
+			assert.Equal(t, event.ApplySuccessful, events[0].ApplyEvent.Status)
+			assert.Equal(t, tc.expectedAdopted, events[0].ApplyEvent.Adopted)
+		})
+	}
+}
+
+func TestApplyTaskMigrateClientSideApply(t *testing.T) {
+	secretGVK := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}
+	secretGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+	testCases := map[string]struct {
+		serverSideOptions common.ServerSideOptions
+		dryRunStrategy    common.DryRunStrategy
+		patchErr          error
+		expectedManager   string
+		expectedRuns      int
+		expectedStatus    event.ApplyEventStatus
+		expectedErr       string
+	}{
+		"client-side apply managers are migrated": {
+			serverSideOptions: common.ServerSideOptions{
+				ServerSideApply:        true,
+				FieldManager:           "kubectl",
+				MigrateClientSideApply: true,
+			},
+			expectedManager: "kubectl",
+			expectedRuns:    1,
+			expectedStatus:  event.ApplySuccessful,
+		},
+		"migration failure fails the apply": {
+			serverSideOptions: common.ServerSideOptions{
+				ServerSideApply:        true,
+				FieldManager:           "kubectl",
+				MigrateClientSideApply: true,
+			},
+			patchErr:        errors.New("forbidden"),
+			expectedManager: "kubectl-client-side-apply",
+			expectedStatus:  event.ApplyFailed,
+			expectedErr:     "failed to migrate client-side apply field managers: failed to upgrade field managers: forbidden",
+		},
+		"migration is disabled by default": {
+			serverSideOptions: common.ServerSideOptions{
+				ServerSideApply: true,
+				FieldManager:    "kubectl",
+			},
+			expectedManager: "kubectl-client-side-apply",
+			expectedRuns:    1,
+			expectedStatus:  event.ApplySuccessful,
+		},
+		"migration is skipped in dry-run": {
+			serverSideOptions: common.ServerSideOptions{
+				ServerSideApply:        true,
+				FieldManager:           "kubectl",
+				MigrateClientSideApply: true,
+			},
+			dryRunStrategy:  common.DryRunServer,
+			expectedManager: "kubectl-client-side-apply",
+			expectedRuns:    1,
+			expectedStatus:  event.ApplySuccessful,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+
+			objs := toUnstructureds([]resourceInfo{
+				{
+					apiVersion: "v1",
+					kind:       "Secret",
+					name:       "foo",
+					namespace:  "default",
+				},
+			})
+			id := object.UnstructuredToObjMetadata(objs[0])
+			live := objs[0].DeepCopy()
+			live.SetManagedFields([]metav1.ManagedFieldsEntry{
+				{
+					Manager:    "kubectl-client-side-apply",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:key":{}}}`)},
+				},
+			})
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+			if tc.patchErr != nil {
+				client.PrependReactor("patch", "secrets", func(clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.patchErr
+				})
+			}
+
+			runs := 0
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(_ string, eventChannel chan<- event.Event, _ common.ServerSideOptions,
+				_ common.DryRunStrategy, _ dynamic.Interface, _ discovery.OpenAPISchemaInterface) applyOptions {
+				return &immutableApplyOptions{
+					eventChannel: eventChannel,
+					runs:         &runs,
+				}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			applyTask := &ApplyTask{
+				Objects:           objs,
+				InfoHelper:        &fakeInfoHelper{},
+				DynamicClient:     client,
+				Mapper:            testutil.NewFakeRESTMapper(secretGVK),
+				ServerSideOptions: tc.serverSideOptions,
+				DryRunStrategy:    tc.dryRunStrategy,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedRuns, runs)
+			require.Len(t, events, 1)
+			assert.Equal(t, tc.expectedStatus, events[0].ApplyEvent.Status)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, events[0].ApplyEvent.Error, tc.expectedErr)
+				assert.True(t, taskContext.InventoryManager().IsFailedApply(id))
+			}
+
+			migrated, err := client.Resource(secretGVR).Namespace(id.Namespace).
+				Get(context.TODO(), id.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Len(t, migrated.GetManagedFields(), 1)
+			assert.Equal(t, tc.expectedManager, migrated.GetManagedFields()[0].Manager)
+		})
+	}
+}
+
 func toUnstructured(obj map[string]interface{}) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: obj,
@@ -564,6 +1370,56 @@ func (f *fakeApplyOptions) SetObjects(objects []*resource.Info) {
 	f.objects = objects
 }
 
+// conflictApplyOptions fails with the passed error, unless forced.
+type conflictApplyOptions struct {
+	force bool
+	err   error
+	runs  *int
+}
+
+func (c *conflictApplyOptions) Run() error {
+	*c.runs++
+	if c.force {
+		return nil
+	}
+	return c.err
+}
+
+func (c *conflictApplyOptions) SetObjects([]*resource.Info) {}
+
+// immutableApplyOptions fails with the passed error, if any, on the first
+// run, and applies the objects on later runs.
+type immutableApplyOptions struct {
+	eventChannel chan<- event.Event
+	objects      []*resource.Info
+	err          error
+	runs         *int
+}
+
+func (i *immutableApplyOptions) Run() error {
+	*i.runs++
+	if *i.runs == 1 && i.err != nil {
+		return i.err
+	}
+	for _, info := range i.objects {
+		obj := info.Object.(*unstructured.Unstructured)
+		obj.SetUID("new")
+		i.eventChannel <- event.Event{
+			Type: event.ApplyType,
+			ApplyEvent: event.ApplyEvent{
+				Identifier: object.UnstructuredToObjMetadata(obj),
+				Status:     event.ApplySuccessful,
+				Resource:   obj,
+			},
+		}
+	}
+	return nil
+}
+
+func (i *immutableApplyOptions) SetObjects(objects []*resource.Info) {
+	i.objects = objects
+}
+
 type fakeInfoHelper struct{}
 
 func (f *fakeInfoHelper) UpdateInfo(*resource.Info) error {
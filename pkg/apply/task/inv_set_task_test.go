@@ -4,13 +4,17 @@
 package task
 
 import (
+	"context"
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
 )
@@ -212,3 +216,127 @@ func TestInvSetTask(t *testing.T) {
 		})
 	}
 }
+
+// TestInvSetTask_AbortOnError verifies that the objects of the tasks skipped
+// by the AbortOnError policy are kept in the inventory.
+func TestInvSetTask_AbortOnError(t *testing.T) {
+	id1 := object.UnstructuredToObjMetadata(obj1)
+	id2 := object.UnstructuredToObjMetadata(obj2)
+	id3 := object.UnstructuredToObjMetadata(obj3)
+	prevInventory := object.ObjMetadataSet{id1, id2, id3}
+
+	client := inventory.NewFakeClient(prevInventory)
+	tasks := []taskrunner.Task{
+		&fakeActuationTask{name: "apply-0", action: event.ApplyAction, ids: object.ObjMetadataSet{id1}, fail: true},
+		&fakeActuationTask{name: "apply-1", action: event.ApplyAction, ids: object.ObjMetadataSet{id2}},
+		&fakeActuationTask{name: "prune-0", action: event.PruneAction, ids: object.ObjMetadataSet{id3}},
+		&InvSetTask{
+			TaskName:      taskName,
+			InvClient:     client,
+			PrevInventory: prevInventory,
+		},
+	}
+	taskQueue := make(chan taskrunner.Task, len(tasks))
+	for _, tsk := range tasks {
+		taskQueue <- tsk
+	}
+
+	eventChannel := make(chan event.Event)
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	runner := taskrunner.NewTaskStatusRunner(object.ObjMetadataSet{}, watcher.BlindStatusWatcher{})
+
+	var events []event.Event
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range eventChannel {
+			events = append(events, e)
+		}
+	}()
+
+	err := runner.Run(context.Background(), taskContext, taskQueue, taskrunner.Options{
+		ErrorPolicy: taskrunner.AbortOnError,
+	})
+	close(eventChannel)
+	wg.Wait()
+	assert.NoError(t, err)
+
+	actual, _ := client.GetClusterObjs(nil)
+	testutil.AssertEqual(t, prevInventory, actual)
+
+	im := taskContext.InventoryManager()
+	testutil.AssertEqual(t, object.ObjMetadataSet{id2}, im.SkippedApplies())
+	testutil.AssertEqual(t, object.ObjMetadataSet{id3}, im.SkippedDeletes())
+
+	var skipped []event.Event
+	for _, e := range events {
+		if e.Type == event.ApplyType || e.Type == event.PruneType {
+			skipped = append(skipped, e)
+		}
+	}
+	testutil.AssertEqual(t, []event.Event{
+		{
+			Type: event.ApplyType,
+			ApplyEvent: event.ApplyEvent{
+				GroupName:  "apply-1",
+				Identifier: id2,
+				Status:     event.ApplySkipped,
+				Error:      &taskrunner.AbortedError{},
+			},
+		},
+		{
+			Type: event.PruneType,
+			PruneEvent: event.PruneEvent{
+				GroupName:  "prune-0",
+				Identifier: id3,
+				Status:     event.PruneSkipped,
+				Error:      &taskrunner.AbortedError{},
+			},
+		},
+	}, skipped)
+}
+
+// fakeActuationTask records its objects as successfully actuated, or as
+// failed if fail is true.
+type fakeActuationTask struct {
+	name   string
+	action event.ResourceAction
+	ids    object.ObjMetadataSet
+	fail   bool
+}
+
+func (f *fakeActuationTask) Name() string {
+	return f.name
+}
+
+func (f *fakeActuationTask) Action() event.ResourceAction {
+	return f.action
+}
+
+func (f *fakeActuationTask) Identifiers() object.ObjMetadataSet {
+	return f.ids
+}
+
+func (f *fakeActuationTask) Start(taskContext *taskrunner.TaskContext) {
+	go func() {
+		im := taskContext.InventoryManager()
+		for _, id := range f.ids {
+			switch {
+			case f.action == event.ApplyAction && f.fail:
+				im.AddFailedApply(id)
+			case f.action == event.ApplyAction:
+				im.AddSuccessfulApply(id, "unused-uid", 0)
+			case f.fail:
+				im.AddFailedDelete(id)
+			default:
+				im.AddSuccessfulDelete(id, "unused-uid")
+			}
+		}
+		taskContext.TaskChannel() <- taskrunner.TaskResult{}
+	}()
+}
+
+func (f *fakeActuationTask) Cancel(_ *taskrunner.TaskContext) {}
+
+func (f *fakeActuationTask) StatusUpdate(_ *taskrunner.TaskContext, _ object.ObjMetadata) {}
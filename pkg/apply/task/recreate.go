@@ -0,0 +1,134 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+var (
+	// recreatePollInterval is the interval between checks whether the
+	// deleted object is gone, before it is re-created.
+	recreatePollInterval = 1 * time.Second
+	// recreateTimeout is how long to wait for the deleted object to be gone,
+	// before giving up on re-creating it.
+	recreateTimeout = 2 * time.Minute
+)
+
+// canRecreate returns true if the object may be deleted and re-created when
+// the apply fails because an immutable field changed.
+func (a *ApplyTask) canRecreate(obj *unstructured.Unstructured) bool {
+	return a.RecreateOnImmutable || obj.GetAnnotations()[common.RecreateOnImmutableAnnotation] == "true"
+}
+
+// getObject returns the object from the cluster, or nil if it does not
+// exist.
+func (a *ApplyTask) getObject(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	if a.Mapper == nil || a.DynamicClient == nil {
+		return nil, fmt.Errorf("no client to get %q", id)
+	}
+	mapping, err := a.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return nil, err
+	}
+	live, err := a.DynamicClient.Resource(mapping.Resource).Namespace(id.Namespace).
+		Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get current object from cluster: %w", err)
+	}
+	return live, nil
+}
+
+// recreate deletes the live object from the cluster, if not nil, waits for it
+// to be gone, and applies the object again. The apply events are sent as
+// replaced.
+// The deletion is conditional on the UID of the live object, so an object
+// re-created by someone else in the meantime is not deleted. Foreground
+// propagation makes sure the dependents are gone before the object is
+// re-created.
+func (a *ApplyTask) recreate(ctx context.Context, info *resource.Info, obj, live *unstructured.Unstructured, eventChannel chan<- event.Event) error {
+	id := object.UnstructuredToObjMetadata(obj)
+	if a.DryRunStrategy.ClientOrServerDryRun() {
+		// The object can not be deleted in dry-run, so the create would
+		// fail with already exists. Report the replace without applying.
+		eventChannel <- a.createApplyReplacedEvent(id, obj)
+		return nil
+	}
+	if live != nil {
+		mapping, err := a.Mapper.RESTMapping(id.GroupKind)
+		if err != nil {
+			return err
+		}
+		client := a.DynamicClient.Resource(mapping.Resource).Namespace(id.Namespace)
+		uid := live.GetUID()
+		propagation := metav1.DeletePropagationForeground
+		klog.V(4).Infof("recreate deleting (object: %s, uid: %s)", id, uid)
+		err = client.Delete(ctx, id.Name, metav1.DeleteOptions{
+			Preconditions:     &metav1.Preconditions{UID: &uid},
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete object to recreate: %w", err)
+		}
+		err = wait.PollImmediate(recreatePollInterval, recreateTimeout, func() (bool, error) {
+			current, err := client.Get(ctx, id.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			// Re-created by someone else, which is updated by the apply.
+			return current.GetUID() != uid, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to wait for deletion of object to recreate: %w", err)
+		}
+	}
+
+	// A failed apply may have replaced the info object with the live
+	// object, so apply the local object again.
+	info.Object = obj
+	info.ResourceVersion = ""
+
+	// Forward the apply events, marked as replaced.
+	replacedChannel, stop := forwardApplyEvents(eventChannel, func(e *event.ApplyEvent) {
+		e.Replaced = true
+	})
+	klog.V(4).Infof("recreate applying (object: %s)", id)
+	ao := applyOptionsFactoryFunc(a.Name(), replacedChannel,
+		a.ServerSideOptions, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
+	ao.SetObjects([]*resource.Info{info})
+	err := ao.Run()
+	stop()
+	return err
+}
+
+func (a *ApplyTask) createApplyReplacedEvent(id object.ObjMetadata, resource *unstructured.Unstructured) event.Event {
+	return event.Event{
+		Type: event.ApplyType,
+		ApplyEvent: event.ApplyEvent{
+			GroupName:  a.Name(),
+			Identifier: id,
+			Status:     event.ApplySuccessful,
+			Resource:   resource,
+			Replaced:   true,
+		},
+	}
+}
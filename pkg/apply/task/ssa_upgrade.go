@@ -0,0 +1,40 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/ssaupgrade"
+)
+
+// canMigrateClientSideApply returns true if the field ownership of objects
+// previously applied with client-side apply should be migrated before the
+// server-side apply. The migration is skipped in dry-run.
+func (a *ApplyTask) canMigrateClientSideApply() bool {
+	return a.ServerSideOptions.ServerSideApply &&
+		a.ServerSideOptions.MigrateClientSideApply &&
+		!a.DryRunStrategy.ClientOrServerDryRun()
+}
+
+// migrateClientSideApply migrates the field ownership of the live object
+// from the client-side apply managers to the server-side apply field manager.
+func (a *ApplyTask) migrateClientSideApply(ctx context.Context, id object.ObjMetadata, live *unstructured.Unstructured) error {
+	mapping, err := a.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return err
+	}
+	client := a.DynamicClient.Resource(mapping.Resource).Namespace(id.Namespace)
+	migrated, err := ssaupgrade.UpgradeObject(ctx, client, live, a.ServerSideOptions.FieldManager)
+	if err != nil {
+		return err
+	}
+	if migrated {
+		klog.V(4).Infof("apply migrated client-side apply field managers (object: %s)", id)
+	}
+	return nil
+}
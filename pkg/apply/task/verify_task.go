@@ -0,0 +1,129 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/apply/verify"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// VerifyTask runs the verification checks declared by the applied objects
+// with the common.VerifyAnnotation, after they have been reconciled.
+// Objects which failed to apply or reconcile are not verified.
+type VerifyTask struct {
+	TaskName string
+
+	Objects  object.UnstructuredSet
+	Verifier *verify.Verifier
+	// Timeout of each check. Zero means verify.DefaultTimeout.
+	Timeout time.Duration
+
+	mu         sync.Mutex
+	cancelFunc context.CancelFunc
+}
+
+func (v *VerifyTask) Name() string {
+	return v.TaskName
+}
+
+func (v *VerifyTask) Action() event.ResourceAction {
+	return event.VerifyAction
+}
+
+func (v *VerifyTask) Identifiers() object.ObjMetadataSet {
+	return object.UnstructuredSetToObjMetadataSet(v.Objects)
+}
+
+// Start creates a new goroutine that runs the verification checks, one
+// object at a time, and sends a VerifyEvent with the result of each.
+func (v *VerifyTask) Start(taskContext *taskrunner.TaskContext) {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.mu.Lock()
+	v.cancelFunc = cancel
+	v.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		klog.V(2).Infof("verify task starting (name: %q, objects: %d)",
+			v.Name(), len(v.Objects))
+		for _, obj := range v.Objects {
+			if ctx.Err() != nil {
+				break
+			}
+			id := object.UnstructuredToObjMetadata(obj)
+			check, found, err := common.ReadVerifyCheck(obj)
+			if err != nil || !found {
+				// invalid checks are rejected by validation
+				continue
+			}
+			if !v.verifiable(taskContext, id) {
+				klog.V(4).Infof("verify skipped (object: %s)", id)
+				taskContext.SendEvent(v.createEvent(id, check, event.VerifySkipped, nil))
+				continue
+			}
+			err = v.verify(ctx, id, check)
+			if err != nil {
+				klog.V(4).Infof("verify failed (object: %s): %v", id, err)
+				taskContext.SendEvent(v.createEvent(id, check, event.VerifyFailed, err))
+				continue
+			}
+			taskContext.SendEvent(v.createEvent(id, check, event.VerifySuccessful, nil))
+		}
+		klog.V(2).Infof("verify task completing (name: %q)", v.Name())
+		taskContext.TaskChannel() <- taskrunner.TaskResult{}
+	}()
+}
+
+// verify runs a single check until it passes, fails, or the task timeout
+// expires.
+func (v *VerifyTask) verify(ctx context.Context, id object.ObjMetadata, check common.VerifyCheck) error {
+	timeout := v.Timeout
+	if timeout <= 0 {
+		timeout = verify.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return v.Verifier.Verify(ctx, id, check)
+}
+
+// verifiable returns true if the object was applied and reconciled
+// successfully.
+func (v *VerifyTask) verifiable(taskContext *taskrunner.TaskContext, id object.ObjMetadata) bool {
+	im := taskContext.InventoryManager()
+	return im.IsSuccessfulApply(id) && !im.IsFailedReconcile(id) && !im.IsTimeoutReconcile(id)
+}
+
+func (v *VerifyTask) createEvent(id object.ObjMetadata, check common.VerifyCheck,
+	status event.VerifyEventStatus, err error) event.Event {
+	return event.Event{
+		Type: event.VerifyType,
+		VerifyEvent: event.VerifyEvent{
+			GroupName:  v.Name(),
+			Identifier: id,
+			Status:     status,
+			Check:      check.String(),
+			Error:      err,
+		},
+	}
+}
+
+// Cancel stops the remaining checks. The running check is interrupted.
+func (v *VerifyTask) Cancel(_ *taskrunner.TaskContext) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.cancelFunc != nil {
+		v.cancelFunc()
+	}
+}
+
+// StatusUpdate is not supported by the VerifyTask.
+func (v *VerifyTask) StatusUpdate(_ *taskrunner.TaskContext, _ object.ObjMetadata) {}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+//go:generate stringer -type=ErrorPolicy
+type ErrorPolicy int
+
+const (
+	// ContinueOnError policy runs every task, even after actuation or
+	// reconcile failures. Objects which depend on failed objects are still
+	// skipped.
+	ContinueOnError ErrorPolicy = iota
+
+	// AbortOnError policy stops starting new tasks after the first
+	// actuation or reconcile failure. The objects of the tasks which are not
+	// started are recorded as skipped, and the inventory tasks still run, so
+	// the inventory keeps the objects it stored before.
+	AbortOnError
+)
+
+// AbortedError is the error of the skipped events of the objects which were
+// not actuated, because the AbortOnError policy stopped the run after a
+// failure.
+type AbortedError struct{}
+
+func (e *AbortedError) Error() string {
+	return "skipped after a previous failure (error policy: AbortOnError)"
+}
+
+func (e *AbortedError) Is(err error) bool {
+	_, ok := err.(*AbortedError)
+	return ok
+}
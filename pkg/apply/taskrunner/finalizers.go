@@ -0,0 +1,207 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+var (
+	namespaceGK = schema.GroupKind{Group: "", Kind: "Namespace"}
+	// removeFinalizersPatch is the merge patch that removes all finalizers
+	// from the object metadata.
+	removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+)
+
+// terminatingInfo returns the TerminatingInfo of the cached object, or nil
+// if the object is not terminating or has no remaining finalizers.
+// Namespaces also report the finalizers in the spec, which are removed by
+// the namespace controller after the namespace contents are deleted.
+func terminatingInfo(taskContext *TaskContext, id object.ObjMetadata) *event.TerminatingInfo {
+	obj := taskContext.ResourceCache().Get(id).Resource
+	if obj == nil || obj.GetDeletionTimestamp() == nil {
+		return nil
+	}
+	finalizers := obj.GetFinalizers()
+	if id.GroupKind == namespaceGK {
+		specFinalizers, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "finalizers")
+		finalizers = append(finalizers, specFinalizers...)
+	}
+	if len(finalizers) == 0 {
+		return nil
+	}
+	return &event.TerminatingInfo{
+		Finalizers: finalizers,
+		Duration:   time.Since(obj.GetDeletionTimestamp().Time).Round(time.Second),
+	}
+}
+
+// checkTerminating sends a pending event with the remaining finalizers if
+// the object is terminating and its finalizers changed since the last
+// event. If ForceRemoveFinalizersAfter is set, a timer is started to remove
+// the finalizers. Returns true if an event was sent.
+// The pending set must be write locked by the caller.
+func (w *WaitTask) checkTerminating(taskContext *TaskContext, id object.ObjMetadata) bool {
+	if w.Condition != AllNotFound {
+		return false
+	}
+	info := terminatingInfo(taskContext, id)
+	if info == nil {
+		return false
+	}
+	sent := false
+	if w.terminating == nil {
+		w.terminating = make(map[object.ObjMetadata][]string)
+	}
+	if previous, found := w.terminating[id]; !found || !stringsEqual(previous, info.Finalizers) {
+		klog.V(3).Infof("object terminating (object: %q, finalizers: %q, duration: %s)",
+			id, info.Finalizers, info.Duration)
+		w.terminating[id] = info.Finalizers
+		w.sendTerminatingEvent(taskContext, id, event.ReconcilePending, info)
+		sent = true
+	}
+	if w.ForceRemoveFinalizersAfter <= 0 || w.completed {
+		return sent
+	}
+	if w.finalizerTimers == nil {
+		w.finalizerTimers = make(map[object.ObjMetadata]*time.Timer)
+	}
+	if _, found := w.finalizerTimers[id]; found {
+		return sent
+	}
+	delay := w.ForceRemoveFinalizersAfter - info.Duration
+	if delay < 0 {
+		delay = 0
+	}
+	w.finalizerTimers[id] = time.AfterFunc(delay, func() {
+		w.forceRemoveFinalizers(taskContext, id)
+	})
+	return sent
+}
+
+// forceRemoveFinalizers removes the finalizers of the object, if it is
+// still pending and terminating, and sends a pending event.
+// The pending set is not locked while the finalizers are removed, to not
+// block status updates on the API calls.
+func (w *WaitTask) forceRemoveFinalizers(taskContext *TaskContext, id object.ObjMetadata) {
+	info := w.pendingTerminatingInfo(taskContext, id)
+	if info == nil {
+		return
+	}
+	klog.Infof("removing finalizers (object: %q, finalizers: %q, terminating: %s)",
+		id, info.Finalizers, info.Duration)
+	if err := w.removeFinalizers(id); err != nil {
+		klog.Errorf("failed to remove finalizers (object: %q): %v", id, err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.completed || !w.pending.Contains(id) {
+		return
+	}
+	info.FinalizersRemoved = true
+	w.sendTerminatingEvent(taskContext, id, event.ReconcilePending, info)
+}
+
+// pendingTerminatingInfo returns the TerminatingInfo of the object, or nil
+// if the task is completed or the object is not pending anymore.
+// The pending set is read locked during execution of pendingTerminatingInfo.
+func (w *WaitTask) pendingTerminatingInfo(taskContext *TaskContext, id object.ObjMetadata) *event.TerminatingInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.completed || !w.pending.Contains(id) {
+		return nil
+	}
+	return terminatingInfo(taskContext, id)
+}
+
+// removeFinalizers patches the finalizers away from the object metadata.
+// The spec finalizers of Namespaces can only be updated through the
+// finalize subresource, so they are removed with an update of it.
+func (w *WaitTask) removeFinalizers(id object.ObjMetadata) error {
+	if w.Client == nil || w.Mapper == nil {
+		return fmt.Errorf("no client to remove finalizers")
+	}
+	mapping, err := w.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return err
+	}
+	client := w.Client.Resource(mapping.Resource).Namespace(id.Namespace)
+	obj, err := client.Patch(context.TODO(), id.Name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	if id.GroupKind != namespaceGK {
+		return nil
+	}
+	specFinalizers, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "finalizers")
+	if len(specFinalizers) == 0 {
+		return nil
+	}
+	unstructured.RemoveNestedField(obj.Object, "spec", "finalizers")
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{}, "finalize")
+	return err
+}
+
+// stopFinalizerTimers marks the task as completed and stops the timers
+// that remove finalizers.
+// The pending set is write locked during execution of stopFinalizerTimers.
+func (w *WaitTask) stopFinalizerTimers() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.completed = true
+	for _, timer := range w.finalizerTimers {
+		timer.Stop()
+	}
+}
+
+// sendTimeoutEvent sends a timeout event, with the remaining finalizers if
+// the object is terminating.
+func (w *WaitTask) sendTimeoutEvent(taskContext *TaskContext, id object.ObjMetadata) {
+	if w.Condition == AllNotFound {
+		if info := terminatingInfo(taskContext, id); info != nil {
+			w.sendTerminatingEvent(taskContext, id, event.ReconcileTimeout, info)
+			return
+		}
+	}
+	w.sendEvent(taskContext, id, event.ReconcileTimeout)
+}
+
+func (w *WaitTask) sendTerminatingEvent(taskContext *TaskContext, id object.ObjMetadata, status event.WaitEventStatus, info *event.TerminatingInfo) {
+	taskContext.SendEvent(event.Event{
+		Type: event.WaitType,
+		WaitEvent: event.WaitEvent{
+			GroupName:   w.Name(),
+			Identifier:  id,
+			Status:      status,
+			Terminating: info,
+		},
+	})
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestWaitTask_Terminating(t *testing.T) {
+	taskName := "wait-1"
+	testDeployment1ID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment1 := testutil.Unstructured(t, testDeployment1YAML)
+	testDeployment1.SetUID("a")
+	testDeployment1.SetFinalizers([]string{"example.com/cleanup"})
+	deletionTimestamp := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	testDeployment1.SetDeletionTimestamp(&deletionTimestamp)
+
+	task := NewWaitTask(taskName, object.ObjMetadataSet{testDeployment1ID}, AllNotFound,
+		1*time.Second, testutil.NewFakeRESTMapper())
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulDelete(testDeployment1ID, testDeployment1.GetUID())
+	resourceCache.Put(testDeployment1ID, cache.ResourceStatus{
+		Resource: testDeployment1,
+		Status:   status.TerminatingStatus,
+	})
+
+	// run task async, to let the test collect events
+	go func() {
+		task.Start(taskContext)
+		// status updates with the same finalizers do not send events
+		task.StatusUpdate(taskContext, testDeployment1ID)
+	}()
+
+	receivedEvents := collectWaitEvents(t, taskContext)
+	expectedEvents := []event.WaitEvent{
+		{
+			GroupName:  taskName,
+			Identifier: testDeployment1ID,
+			Status:     event.ReconcilePending,
+			Terminating: &event.TerminatingInfo{
+				Finalizers: []string{"example.com/cleanup"},
+			},
+		},
+		{
+			GroupName:  taskName,
+			Identifier: testDeployment1ID,
+			Status:     event.ReconcileTimeout,
+			Terminating: &event.TerminatingInfo{
+				Finalizers: []string{"example.com/cleanup"},
+			},
+		},
+	}
+	testutil.AssertEqual(t, expectedEvents, receivedEvents)
+}
+
+var testNamespaceYAML = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test-namespace
+spec:
+  finalizers:
+  - kubernetes
+`
+
+func TestWaitTask_ForceRemoveFinalizers(t *testing.T) {
+	testCases := map[string]struct {
+		manifest           string
+		gvk                schema.GroupVersionKind
+		gvr                schema.GroupVersionResource
+		expectedFinalizers []string
+	}{
+		"deployment": {
+			manifest:           testDeployment1YAML,
+			gvk:                schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			gvr:                schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			expectedFinalizers: []string{"example.com/cleanup"},
+		},
+		"namespace with spec finalizers": {
+			manifest:           testNamespaceYAML,
+			gvk:                schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+			gvr:                schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+			expectedFinalizers: []string{"example.com/cleanup", "kubernetes"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			taskName := "wait-1"
+			id := testutil.ToIdentifier(t, tc.manifest)
+			obj := testutil.Unstructured(t, tc.manifest)
+			obj.SetUID("a")
+			obj.SetFinalizers([]string{"example.com/cleanup"})
+			deletionTimestamp := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+			obj.SetDeletionTimestamp(&deletionTimestamp)
+
+			client := fake.NewSimpleDynamicClient(runtime.NewScheme(), obj.DeepCopy())
+
+			task := NewWaitTask(taskName, object.ObjMetadataSet{id}, AllNotFound,
+				5*time.Second, testutil.NewFakeRESTMapper(tc.gvk))
+			task.Client = client
+			task.ForceRemoveFinalizersAfter = 5 * time.Minute
+
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := NewTaskContext(eventChannel, resourceCache)
+			defer close(eventChannel)
+
+			taskContext.InventoryManager().AddSuccessfulDelete(id, obj.GetUID())
+			resourceCache.Put(id, cache.ResourceStatus{
+				Resource: obj,
+				Status:   status.TerminatingStatus,
+			})
+
+			go task.Start(taskContext)
+
+			timer := time.NewTimer(10 * time.Second)
+			defer timer.Stop()
+			var receivedEvents []event.WaitEvent
+		loop:
+			for {
+				select {
+				case e := <-taskContext.EventChannel():
+					receivedEvents = append(receivedEvents, normalizeWaitEvent(t, e))
+					if e.WaitEvent.Terminating != nil && e.WaitEvent.Terminating.FinalizersRemoved {
+						// the object is deleted after its finalizers are removed
+						go func() {
+							resourceCache.Put(id, cache.ResourceStatus{
+								Status: status.NotFoundStatus,
+							})
+							task.StatusUpdate(taskContext, id)
+						}()
+					}
+				case res := <-taskContext.TaskChannel():
+					assert.NoError(t, res.Err)
+					break loop
+				case <-timer.C:
+					t.Fatalf("timed out waiting for TaskResult")
+				}
+			}
+
+			expectedEvents := []event.WaitEvent{
+				{
+					GroupName:  taskName,
+					Identifier: id,
+					Status:     event.ReconcilePending,
+					Terminating: &event.TerminatingInfo{
+						Finalizers: tc.expectedFinalizers,
+					},
+				},
+				{
+					GroupName:  taskName,
+					Identifier: id,
+					Status:     event.ReconcilePending,
+					Terminating: &event.TerminatingInfo{
+						Finalizers:        tc.expectedFinalizers,
+						FinalizersRemoved: true,
+					},
+				},
+				{
+					GroupName:  taskName,
+					Identifier: id,
+					Status:     event.ReconcileSuccessful,
+				},
+			}
+			testutil.AssertEqual(t, expectedEvents, receivedEvents)
+
+			result, err := client.Resource(tc.gvr).Namespace(id.Namespace).
+				Get(context.TODO(), id.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, result.GetFinalizers())
+			specFinalizers, _, err := unstructured.NestedStringSlice(result.Object, "spec", "finalizers")
+			require.NoError(t, err)
+			assert.Empty(t, specFinalizers)
+		})
+	}
+}
+
+// collectWaitEvents returns the normalized wait events sent until the task
+// completes.
+func collectWaitEvents(t *testing.T, taskContext *TaskContext) []event.WaitEvent {
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	var receivedEvents []event.WaitEvent
+	for {
+		select {
+		case e := <-taskContext.EventChannel():
+			receivedEvents = append(receivedEvents, normalizeWaitEvent(t, e))
+		case res := <-taskContext.TaskChannel():
+			assert.NoError(t, res.Err)
+			return receivedEvents
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+}
+
+// normalizeWaitEvent asserts the terminating duration and clears it, so
+// events can be compared.
+func normalizeWaitEvent(t *testing.T, e event.Event) event.WaitEvent {
+	require.Equal(t, event.WaitType, e.Type)
+	we := e.WaitEvent
+	if we.Terminating != nil {
+		assert.GreaterOrEqual(t, we.Terminating.Duration, 10*time.Minute)
+		info := *we.Terminating
+		info.Duration = 0
+		we.Terminating = &info
+	}
+	return we
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+// sendProgressEvents sends a pending event with the latest status message of
+// every pending object, every ProgressInterval, until the context is done.
+// The done channel is closed when no more events will be sent.
+func (w *WaitTask) sendProgressEvents(ctx context.Context, taskContext *TaskContext, start time.Time, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sendProgress(taskContext, time.Since(start).Round(time.Second))
+		}
+	}
+}
+
+// sendProgress sends a pending event with the latest status message of every
+// pending object.
+// The pending set is read locked during execution of sendProgress.
+func (w *WaitTask) sendProgress(taskContext *TaskContext, elapsed time.Duration) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	klog.V(3).Infof("wait task progress: %d/%d (elapsed: %s)",
+		len(w.Ids)-len(w.pending), len(w.Ids), elapsed)
+	for _, id := range w.pending {
+		taskContext.SendEvent(event.Event{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  w.Name(),
+				Identifier: id,
+				Status:     event.ReconcilePending,
+				Progress: &event.ProgressInfo{
+					Message: taskContext.ResourceCache().Get(id).StatusMessage,
+					Elapsed: elapsed,
+				},
+			},
+		})
+	}
+}
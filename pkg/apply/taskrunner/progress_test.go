@@ -0,0 +1,121 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestWaitTask_Progress(t *testing.T) {
+	taskName := "wait-1"
+	testDeployment1ID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment1 := testutil.Unstructured(t, testDeployment1YAML)
+
+	task := NewWaitTask(taskName, object.ObjMetadataSet{testDeployment1ID}, AllCurrent,
+		5*time.Second, testutil.NewFakeRESTMapper())
+	task.ProgressInterval = 100 * time.Millisecond
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment1ID,
+		testDeployment1.GetUID(), testDeployment1.GetGeneration())
+	resourceCache.Put(testDeployment1ID, cache.ResourceStatus{
+		Resource:      testDeployment1,
+		Status:        status.InProgressStatus,
+		StatusMessage: "Ready: 2/5",
+	})
+
+	go task.Start(taskContext)
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	var progressEvents []event.WaitEvent
+	var statusEvents []event.WaitEvent
+loop:
+	for {
+		select {
+		case e := <-taskContext.EventChannel():
+			require.Equal(t, event.WaitType, e.Type)
+			if e.WaitEvent.Progress == nil {
+				statusEvents = append(statusEvents, e.WaitEvent)
+				continue
+			}
+			progressEvents = append(progressEvents, e.WaitEvent)
+			if len(progressEvents) == 1 {
+				// the object reconciles after the first progress event
+				go func() {
+					resourceCache.Put(testDeployment1ID, cache.ResourceStatus{
+						Resource: testDeployment1,
+						Status:   status.CurrentStatus,
+					})
+					task.StatusUpdate(taskContext, testDeployment1ID)
+				}()
+			}
+		case res := <-taskContext.TaskChannel():
+			assert.NoError(t, res.Err)
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	require.NotEmpty(t, progressEvents)
+	for _, e := range progressEvents {
+		assert.Equal(t, event.ReconcilePending, e.Status)
+		assert.Equal(t, testDeployment1ID, e.Identifier)
+		assert.Equal(t, "Ready: 2/5", e.Progress.Message)
+	}
+	expectedEvents := []event.WaitEvent{
+		{
+			GroupName:  taskName,
+			Identifier: testDeployment1ID,
+			Status:     event.ReconcilePending,
+		},
+		{
+			GroupName:  taskName,
+			Identifier: testDeployment1ID,
+			Status:     event.ReconcileSuccessful,
+		},
+	}
+	testutil.AssertEqual(t, expectedEvents, statusEvents)
+}
+
+func TestWaitTask_NoProgress(t *testing.T) {
+	testDeployment1ID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment1 := testutil.Unstructured(t, testDeployment1YAML)
+
+	task := NewWaitTask("wait-1", object.ObjMetadataSet{testDeployment1ID}, AllCurrent,
+		1*time.Second, testutil.NewFakeRESTMapper())
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment1ID,
+		testDeployment1.GetUID(), testDeployment1.GetGeneration())
+	resourceCache.Put(testDeployment1ID, cache.ResourceStatus{
+		Resource:      testDeployment1,
+		Status:        status.InProgressStatus,
+		StatusMessage: "Ready: 2/5",
+	})
+
+	go task.Start(taskContext)
+
+	for _, e := range collectWaitEvents(t, taskContext) {
+		assert.Nil(t, e.Progress)
+	}
+}
@@ -38,6 +38,9 @@ type Options struct {
 	// RESTScopeStrategy specifies which strategy to use when listing and
 	// watching resources. By default, the strategy is selected automatically.
 	WatcherRESTScopeStrategy watcher.RESTScopeStrategy
+	// ErrorPolicy specifies whether to keep starting new tasks after an
+	// actuation or reconcile failure. By default, all tasks are run.
+	ErrorPolicy ErrorPolicy
 }
 
 // Run executes the tasks in the taskqueue, with the statusPoller running in the
@@ -90,6 +93,11 @@ func (tsr *TaskStatusRunner) Run(
 	abort := false
 	var abortReason error
 
+	// skip is used to signal that an object has failed and the ErrorPolicy
+	// is AbortOnError. The remaining tasks are skipped, except for the
+	// inventory tasks.
+	skip := false
+
 	// We do this so we can set the doneCh to a nil channel after
 	// it has been closed. This is needed to avoid a busy loop.
 	doneCh := ctx.Done()
@@ -137,7 +145,7 @@ func (tsr *TaskStatusRunner) Run(
 			// Tasks may commence!
 			if statusEvent.Type == pollevent.SyncEvent {
 				// Find and start the first task in the queue.
-				currentTask, done = nextTask(taskQueue, taskContext)
+				currentTask, done = nextTask(taskQueue, taskContext, skip)
 				if done {
 					return complete(nil)
 				}
@@ -199,7 +207,11 @@ func (tsr *TaskStatusRunner) Run(
 			if abort {
 				return complete(abortReason)
 			}
-			currentTask, done = nextTask(taskQueue, taskContext)
+			if !skip && opts.ErrorPolicy == AbortOnError && hasFailures(taskContext) {
+				klog.V(3).Infof("Runner skipping remaining tasks after failure in task %q", currentTask.Name())
+				skip = true
+			}
+			currentTask, done = nextTask(taskQueue, taskContext, skip)
 			// If there are no more tasks, we are done. So just
 			// return.
 			if done {
@@ -226,16 +238,24 @@ func (tsr *TaskStatusRunner) Run(
 // nextTask fetches the latest task from the taskQueue and
 // starts it. If the taskQueue is empty, it the second
 // return value will be true.
-func nextTask(taskQueue chan Task, taskContext *TaskContext) (Task, bool) {
+// If skip is true, every task except for the inventory tasks is skipped, and
+// a skipped event is sent for it instead.
+func nextTask(taskQueue chan Task, taskContext *TaskContext, skip bool) (Task, bool) {
 	var tsk Task
-	select {
-	// If there is any tasks left in the queue, this
-	// case statement will be executed.
-	case t := <-taskQueue:
-		tsk = t
-	default:
-		// Only happens when the channel is empty.
-		return nil, true
+	for tsk == nil {
+		select {
+		// If there is any tasks left in the queue, this
+		// case statement will be executed.
+		case t := <-taskQueue:
+			if skip && t.Action() != event.InventoryAction {
+				skipTask(taskContext, t)
+				continue
+			}
+			tsk = t
+		default:
+			// Only happens when the channel is empty.
+			return nil, true
+		}
 	}
 
 	taskContext.SendEvent(event.Event{
@@ -252,6 +272,81 @@ func nextTask(taskQueue chan Task, taskContext *TaskContext) (Task, bool) {
 	return tsk, false
 }
 
+// skipTask records the objects of a task which will not be started as
+// skipped, so that the inventory keeps the objects it already stored, and
+// sends a skipped event for each object and for the task.
+func skipTask(taskContext *TaskContext, t Task) {
+	im := taskContext.InventoryManager()
+	abortErr := &AbortedError{}
+	for _, id := range t.Identifiers() {
+		switch t.Action() {
+		case event.ApplyAction:
+			im.AddSkippedApply(id)
+			taskContext.SendEvent(event.Event{
+				Type: event.ApplyType,
+				ApplyEvent: event.ApplyEvent{
+					GroupName:  t.Name(),
+					Identifier: id,
+					Status:     event.ApplySkipped,
+					Error:      abortErr,
+				},
+			})
+		case event.PruneAction:
+			im.AddSkippedDelete(id)
+			taskContext.SendEvent(event.Event{
+				Type: event.PruneType,
+				PruneEvent: event.PruneEvent{
+					GroupName:  t.Name(),
+					Identifier: id,
+					Status:     event.PruneSkipped,
+					Error:      abortErr,
+				},
+			})
+		case event.DeleteAction:
+			im.AddSkippedDelete(id)
+			taskContext.SendEvent(event.Event{
+				Type: event.DeleteType,
+				DeleteEvent: event.DeleteEvent{
+					GroupName:  t.Name(),
+					Identifier: id,
+					Status:     event.DeleteSkipped,
+					Error:      abortErr,
+				},
+			})
+		case event.WaitAction:
+			if err := im.SetSkippedReconcile(id); err != nil {
+				klog.Errorf("Failed to mark object as skipped reconcile: %v", err)
+			}
+			taskContext.SendEvent(event.Event{
+				Type: event.WaitType,
+				WaitEvent: event.WaitEvent{
+					GroupName:  t.Name(),
+					Identifier: id,
+					Status:     event.ReconcileSkipped,
+				},
+			})
+		}
+	}
+	taskContext.SendEvent(event.Event{
+		Type: event.ActionGroupType,
+		ActionGroupEvent: event.ActionGroupEvent{
+			GroupName: t.Name(),
+			Action:    t.Action(),
+			Status:    event.Skipped,
+		},
+	})
+}
+
+// hasFailures returns true if any object failed to be applied, deleted, or
+// reconciled.
+func hasFailures(taskContext *TaskContext) bool {
+	im := taskContext.InventoryManager()
+	return len(im.FailedApplies()) > 0 ||
+		len(im.FailedDeletes()) > 0 ||
+		len(im.FailedReconciles()) > 0 ||
+		len(im.TimeoutReconciles()) > 0
+}
+
 // TaskResult is the type returned from tasks once they have completed
 // or failed. If it has failed or timed out, the Err property will be
 // set.
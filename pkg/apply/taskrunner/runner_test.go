@@ -507,11 +507,107 @@ func TestBaseRunnerCancellation(t *testing.T) {
 	}
 }
 
+func TestBaseRunnerErrorPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		errorPolicy    ErrorPolicy
+		failedIds      object.ObjMetadataSet
+		expectedGroups []event.ActionGroupEvent
+	}{
+		"continue after failure": {
+			errorPolicy: ContinueOnError,
+			failedIds:   object.ObjMetadataSet{depID},
+			expectedGroups: []event.ActionGroupEvent{
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Started},
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Finished},
+				{GroupName: "prune-0", Action: event.PruneAction, Status: event.Started},
+				{GroupName: "prune-0", Action: event.PruneAction, Status: event.Finished},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Started},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Finished},
+			},
+		},
+		"abort without failure": {
+			errorPolicy: AbortOnError,
+			expectedGroups: []event.ActionGroupEvent{
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Started},
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Finished},
+				{GroupName: "prune-0", Action: event.PruneAction, Status: event.Started},
+				{GroupName: "prune-0", Action: event.PruneAction, Status: event.Finished},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Started},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Finished},
+			},
+		},
+		"abort after failure": {
+			errorPolicy: AbortOnError,
+			failedIds:   object.ObjMetadataSet{depID},
+			expectedGroups: []event.ActionGroupEvent{
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Started},
+				{GroupName: "apply-0", Action: event.ApplyAction, Status: event.Finished},
+				{GroupName: "prune-0", Action: event.PruneAction, Status: event.Skipped},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Started},
+				{GroupName: "inventory-0", Action: event.InventoryAction, Status: event.Finished},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tasks := []Task{
+				&fakeApplyTask{
+					name:      "apply-0",
+					action:    event.ApplyAction,
+					failedIds: tc.failedIds,
+				},
+				&fakeApplyTask{
+					name:   "prune-0",
+					action: event.PruneAction,
+				},
+				&fakeApplyTask{
+					name:   "inventory-0",
+					action: event.InventoryAction,
+				},
+			}
+			taskQueue := make(chan Task, len(tasks))
+			for _, tsk := range tasks {
+				taskQueue <- tsk
+			}
+
+			statusWatcher := newFakeWatcher(nil)
+			statusWatcher.Start()
+			eventChannel := make(chan event.Event)
+			taskContext := NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			runner := NewTaskStatusRunner(object.ObjMetadataSet{}, statusWatcher)
+
+			var groups []event.ActionGroupEvent
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					if msg.Type == event.ActionGroupType {
+						groups = append(groups, msg.ActionGroupEvent)
+					}
+				}
+			}()
+
+			err := runner.Run(context.Background(), taskContext, taskQueue, Options{
+				ErrorPolicy: tc.errorPolicy,
+			})
+			close(eventChannel)
+			wg.Wait()
+
+			assert.NoError(t, err)
+			testutil.AssertEqual(t, tc.expectedGroups, groups)
+		})
+	}
+}
+
 type fakeApplyTask struct {
 	name        string
+	action      event.ResourceAction
 	resultEvent event.Event
 	duration    time.Duration
 	err         error
+	failedIds   object.ObjMetadataSet
 }
 
 func (f *fakeApplyTask) Name() string {
@@ -519,7 +615,7 @@ func (f *fakeApplyTask) Name() string {
 }
 
 func (f *fakeApplyTask) Action() event.ResourceAction {
-	return event.ApplyAction
+	return f.action
 }
 
 func (f *fakeApplyTask) Identifiers() object.ObjMetadataSet {
@@ -529,6 +625,9 @@ func (f *fakeApplyTask) Identifiers() object.ObjMetadataSet {
 func (f *fakeApplyTask) Start(taskContext *TaskContext) {
 	go func() {
 		<-time.NewTimer(f.duration).C
+		for _, id := range f.failedIds {
+			taskContext.InventoryManager().AddFailedApply(id)
+		}
 		taskContext.SendEvent(f.resultEvent)
 		taskContext.TaskChannel() <- TaskResult{
 			Err: f.err,
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -64,6 +65,17 @@ type WaitTask struct {
 	Timeout time.Duration
 	// Mapper is the RESTMapper to update after CRDs have been reconciled
 	Mapper meta.RESTMapper
+	// Client is used to remove the finalizers of terminating objects, if
+	// ForceRemoveFinalizersAfter is set.
+	Client dynamic.Interface
+	// ForceRemoveFinalizersAfter defines whether the finalizers of objects
+	// being waited on for deletion should be removed, and if so, how long
+	// the objects may be terminating before their finalizers are removed.
+	ForceRemoveFinalizersAfter time.Duration
+	// ProgressInterval defines whether pending events with the latest status
+	// message of the objects which have not reconciled yet should be sent
+	// periodically, and if so, how often.
+	ProgressInterval time.Duration
 	// cancelFunc is a function that will cancel the timeout timer
 	// on the task.
 	cancelFunc context.CancelFunc
@@ -72,6 +84,14 @@ type WaitTask struct {
 	// failed is the set of resources that we are waiting for, but is considered
 	// failed, i.e. unlikely to successfully reconcile.
 	failed object.ObjMetadataSet
+	// terminating is the last reported finalizers of pending objects that
+	// are terminating.
+	terminating map[object.ObjMetadata][]string
+	// finalizerTimers are the timers that remove the finalizers of
+	// terminating objects.
+	finalizerTimers map[object.ObjMetadata]*time.Timer
+	// completed is true after the task completed, to ignore late timers.
+	completed bool
 	// mu protects the pending ObjMetadataSet
 	mu sync.RWMutex
 }
@@ -104,8 +124,15 @@ func (w *WaitTask) Start(taskContext *TaskContext) {
 		ctx, w.cancelFunc = context.WithCancel(ctx)
 	}
 
+	start := time.Now()
 	w.startInner(taskContext)
 
+	var progressDone chan struct{}
+	if w.ProgressInterval > 0 {
+		progressDone = make(chan struct{})
+		go w.sendProgressEvents(ctx, taskContext, start, progressDone)
+	}
+
 	// A goroutine to handle ending the WaitTask.
 	go func() {
 		// Block until complete/cancel/timeout
@@ -113,8 +140,15 @@ func (w *WaitTask) Start(taskContext *TaskContext) {
 		// Err is always non-nil when Done channel is closed.
 		err := ctx.Err()
 
+		if progressDone != nil {
+			// Wait for the last progress events to be sent.
+			<-progressDone
+		}
+
 		klog.V(2).Infof("wait task completing (name: %q,): %v", w.TaskName, err)
 
+		w.stopFinalizerTimers()
+
 		switch err {
 		case context.Canceled:
 			// happy path - cancelled or completed (not considered an error)
@@ -178,7 +212,9 @@ func (w *WaitTask) startInner(taskContext *TaskContext) {
 				klog.Errorf("Failed to mark object as pending reconcile: %v", err)
 			}
 			pending = append(pending, id)
-			w.sendEvent(taskContext, id, event.ReconcilePending)
+			if !w.checkTerminating(taskContext, id) {
+				w.sendEvent(taskContext, id, event.ReconcilePending)
+			}
 		}
 	}
 	w.pending = pending
@@ -204,7 +240,7 @@ func (w *WaitTask) sendTimeoutEvents(taskContext *TaskContext) {
 			// Object never applied or deleted!
 			klog.Errorf("Failed to mark object as pending reconcile: %v", err)
 		}
-		w.sendEvent(taskContext, id, event.ReconcileTimeout)
+		w.sendTimeoutEvent(taskContext, id)
 	}
 }
 
@@ -349,7 +385,9 @@ func (w *WaitTask) StatusUpdate(taskContext *TaskContext, id object.ObjMetadata)
 			w.pending = w.pending.Remove(id)
 			w.failed = append(w.failed, id)
 			w.sendEvent(taskContext, id, event.ReconcileFailed)
-			// default - still pending
+		default:
+			// still pending
+			w.checkTerminating(taskContext, id)
 		}
 	case !w.Ids.Contains(id):
 		// not in wait group - ignore
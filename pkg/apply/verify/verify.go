@@ -0,0 +1,147 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package verify runs the verification checks declared by applied objects
+// with the common.VerifyAnnotation.
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const (
+	// DefaultTimeout is the timeout of a check, if none is set.
+	DefaultTimeout = 5 * time.Minute
+	// DefaultPollInterval is the interval between the attempts of a check,
+	// if none is set.
+	DefaultPollInterval = 2 * time.Second
+)
+
+// Verifier runs verification checks against the cluster.
+type Verifier struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// CoreClient is used to send requests to Services through the API
+	// server proxy.
+	CoreClient corev1client.CoreV1Interface
+	// PollInterval is the interval between the attempts of a check. Zero
+	// means DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Verify runs the check for the object with the passed identifier, until it
+// passes, fails for good, like a failed Job, or the context is done.
+// Returns nil if the check passed, or an error describing why it failed.
+func (v *Verifier) Verify(ctx context.Context, id object.ObjMetadata, check common.VerifyCheck) error {
+	interval := v.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := v.verifyOnce(ctx, id, check)
+		if err == nil {
+			return nil
+		}
+		var tErr *terminalError
+		if errors.As(err, &tErr) {
+			return tErr.err
+		}
+		klog.V(5).Infof("verify check %q not passed yet (object: %s): %v", check, id, err)
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out waiting for check %q: %w", check, err)
+			}
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// terminalError is returned by a check attempt which will never pass.
+type terminalError struct {
+	err error
+}
+
+func (e *terminalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *terminalError) Unwrap() error {
+	return e.err
+}
+
+// verifyOnce runs a single attempt of the check.
+func (v *Verifier) verifyOnce(ctx context.Context, id object.ObjMetadata, check common.VerifyCheck) error {
+	switch check.Type {
+	case common.VerifyJobComplete:
+		return v.verifyJobComplete(ctx, id)
+	case common.VerifyHTTPGet:
+		return v.verifyHTTPGet(ctx, id, check)
+	default:
+		return &terminalError{err: fmt.Errorf("unknown verification check: %q", check.Type)}
+	}
+}
+
+// verifyJobComplete returns nil if the Job has the Complete condition, or a
+// terminal error if it has the Failed condition.
+func (v *Verifier) verifyJobComplete(ctx context.Context, id object.ObjMetadata) error {
+	mapping, err := v.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return &terminalError{err: err}
+	}
+	job, err := v.Client.Resource(mapping.Resource).Namespace(id.Namespace).
+		Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if condition, found := jobCondition(job, "Failed"); found {
+		return &terminalError{err: fmt.Errorf("job failed: %s", condition["message"])}
+	}
+	if _, found := jobCondition(job, "Complete"); found {
+		return nil
+	}
+	return fmt.Errorf("job not complete")
+}
+
+// jobCondition returns the condition of the Job with the passed type, if its
+// status is True.
+func jobCondition(job *unstructured.Unstructured, conditionType string) (map[string]interface{}, bool) {
+	conditions, _, _ := unstructured.NestedSlice(job.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+// verifyHTTPGet returns nil if a GET request to the Service, through the API
+// server proxy, returns 2xx.
+func (v *Verifier) verifyHTTPGet(ctx context.Context, id object.ObjMetadata, check common.VerifyCheck) error {
+	_, err := v.CoreClient.Services(id.Namespace).
+		ProxyGet("", id.Name, check.Port, check.Path, nil).
+		DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("GET %s on port %s failed: %w", check.Path, check.Port, err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var (
+	jobGVK = schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}
+	jobID  = object.ObjMetadata{
+		GroupKind: jobGVK.GroupKind(),
+		Namespace: "default",
+		Name:      "migrate",
+	}
+	serviceID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Service"},
+		Namespace: "default",
+		Name:      "web",
+	}
+)
+
+func newJob(conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata": map[string]interface{}{
+				"name":      jobID.Name,
+				"namespace": jobID.Namespace,
+			},
+			"status": map[string]interface{}{
+				"conditions": conditions,
+			},
+		},
+	}
+}
+
+func condition(conditionType, status, message string) interface{} {
+	return map[string]interface{}{
+		"type":    conditionType,
+		"status":  status,
+		"message": message,
+	}
+}
+
+// fakeResponse is a rest.ResponseWrapper which returns the error, if any.
+type fakeResponse struct {
+	err error
+}
+
+func (f fakeResponse) DoRaw(context.Context) ([]byte, error) {
+	return nil, f.err
+}
+
+func (f fakeResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return nil, f.err
+}
+
+func TestVerify(t *testing.T) {
+	testCases := map[string]struct {
+		id          object.ObjMetadata
+		check       common.VerifyCheck
+		clusterObjs []runtime.Object
+		proxyErr    error
+		expectedErr string
+	}{
+		"job complete": {
+			id:          jobID,
+			check:       common.VerifyCheck{Type: common.VerifyJobComplete},
+			clusterObjs: []runtime.Object{newJob(condition("Complete", "True", ""))},
+		},
+		"job failed": {
+			id:          jobID,
+			check:       common.VerifyCheck{Type: common.VerifyJobComplete},
+			clusterObjs: []runtime.Object{newJob(condition("Failed", "True", "BackoffLimitExceeded"))},
+			expectedErr: "job failed: BackoffLimitExceeded",
+		},
+		"job running": {
+			id:          jobID,
+			check:       common.VerifyCheck{Type: common.VerifyJobComplete},
+			clusterObjs: []runtime.Object{newJob(condition("Complete", "False", ""))},
+			expectedErr: `timed out waiting for check "job-complete": job not complete`,
+		},
+		"job not found": {
+			id:          jobID,
+			check:       common.VerifyCheck{Type: common.VerifyJobComplete},
+			expectedErr: `timed out waiting for check "job-complete": failed to get job: jobs.batch "migrate" not found`,
+		},
+		"http get succeeds": {
+			id:    serviceID,
+			check: common.VerifyCheck{Type: common.VerifyHTTPGet, Port: "8080", Path: "/healthz"},
+		},
+		"http get fails": {
+			id:       serviceID,
+			check:    common.VerifyCheck{Type: common.VerifyHTTPGet, Port: "8080", Path: "/healthz"},
+			proxyErr: errors.New("the server is currently unable to handle the request"),
+			expectedErr: `timed out waiting for check "http-get:8080/healthz": ` +
+				"GET /healthz on port 8080 failed: the server is currently unable to handle the request",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			coreClient := fake.NewSimpleClientset()
+			var proxied []clienttesting.ProxyGetAction
+			coreClient.PrependProxyReactor("services",
+				func(action clienttesting.Action) (bool, rest.ResponseWrapper, error) {
+					proxied = append(proxied, action.(clienttesting.ProxyGetAction))
+					return true, fakeResponse{err: tc.proxyErr}, nil
+				})
+			verifier := &Verifier{
+				Client: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+					map[schema.GroupVersionResource]string{
+						{Group: "batch", Version: "v1", Resource: "jobs"}: "JobList",
+					}, tc.clusterObjs...),
+				Mapper:       testutil.NewFakeRESTMapper(jobGVK),
+				CoreClient:   coreClient.CoreV1(),
+				PollInterval: 10 * time.Millisecond,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := verifier.Verify(ctx, tc.id, tc.check)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.check.Type == common.VerifyHTTPGet {
+				if assert.NotEmpty(t, proxied) {
+					assert.Equal(t, tc.id.Name, proxied[0].GetName())
+					assert.Equal(t, tc.check.Port, proxied[0].GetPort())
+					assert.Equal(t, tc.check.Path, proxied[0].GetPath())
+				}
+			}
+		})
+	}
+}
+
+func TestVerify_JobCompletesLater(t *testing.T) {
+	testCases := map[string]struct {
+		// laterJob is returned after the first gets of the running Job.
+		laterJob    *unstructured.Unstructured
+		expectedErr string
+	}{
+		"job completes": {
+			laterJob: newJob(condition("Complete", "True", "")),
+		},
+		"job fails": {
+			laterJob:    newJob(condition("Failed", "True", "BackoffLimitExceeded")),
+			expectedErr: "job failed: BackoffLimitExceeded",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			gets := 0
+			client.PrependReactor("get", "jobs", func(clienttesting.Action) (bool, runtime.Object, error) {
+				gets++
+				if gets < 3 {
+					return true, newJob(), nil
+				}
+				return true, tc.laterJob, nil
+			})
+			verifier := &Verifier{
+				Client:       client,
+				Mapper:       testutil.NewFakeRESTMapper(jobGVK),
+				PollInterval: 10 * time.Millisecond,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := verifier.Verify(ctx, jobID, common.VerifyCheck{Type: common.VerifyJobComplete})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 3, gets)
+		})
+	}
+}
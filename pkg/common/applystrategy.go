@@ -0,0 +1,58 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyStrategyAnnotation is the annotation key which selects how an object
+// is actuated by the apply. The value must be one of ApplyStrategies.
+const ApplyStrategyAnnotation = "config.kubernetes.io/apply-strategy"
+
+// ApplyStrategy defines how an object is actuated by the apply.
+type ApplyStrategy string
+
+const (
+	// ApplyStrategyApply creates the object if it does not exist, and
+	// updates it otherwise. This is the default.
+	ApplyStrategyApply ApplyStrategy = "apply"
+	// ApplyStrategyCreateOnly creates the object if it does not exist, and
+	// never updates it. An existing object counts as successfully applied,
+	// so that its dependents are applied and it stays in the inventory.
+	ApplyStrategyCreateOnly ApplyStrategy = "create-only"
+	// ApplyStrategyUpdateOnly updates the object if it exists, and never
+	// creates it.
+	ApplyStrategyUpdateOnly ApplyStrategy = "update-only"
+	// ApplyStrategyReplace deletes and re-creates the object if it exists,
+	// and creates it otherwise.
+	ApplyStrategyReplace ApplyStrategy = "replace"
+)
+
+// ApplyStrategies are the valid values of the ApplyStrategyAnnotation.
+var ApplyStrategies = []ApplyStrategy{
+	ApplyStrategyApply,
+	ApplyStrategyCreateOnly,
+	ApplyStrategyUpdateOnly,
+	ApplyStrategyReplace,
+}
+
+// ReadApplyStrategy returns the apply strategy of the object, from the
+// ApplyStrategyAnnotation. Returns ApplyStrategyApply if the annotation is
+// not set, or an error if the value is not a valid strategy.
+func ReadApplyStrategy(obj metav1.Object) (ApplyStrategy, error) {
+	value, found := obj.GetAnnotations()[ApplyStrategyAnnotation]
+	if !found {
+		return ApplyStrategyApply, nil
+	}
+	for _, strategy := range ApplyStrategies {
+		if value == string(strategy) {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %q annotation: %q, must be one of %q",
+		ApplyStrategyAnnotation, value, ApplyStrategies)
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadApplyStrategy(t *testing.T) {
+	tests := map[string]struct {
+		annotations      map[string]string
+		expectedStrategy ApplyStrategy
+		expectedErr      bool
+	}{
+		"no annotation defaults to apply": {
+			expectedStrategy: ApplyStrategyApply,
+		},
+		"apply": {
+			annotations:      map[string]string{ApplyStrategyAnnotation: "apply"},
+			expectedStrategy: ApplyStrategyApply,
+		},
+		"create-only": {
+			annotations:      map[string]string{ApplyStrategyAnnotation: "create-only"},
+			expectedStrategy: ApplyStrategyCreateOnly,
+		},
+		"update-only": {
+			annotations:      map[string]string{ApplyStrategyAnnotation: "update-only"},
+			expectedStrategy: ApplyStrategyUpdateOnly,
+		},
+		"replace": {
+			annotations:      map[string]string{ApplyStrategyAnnotation: "replace"},
+			expectedStrategy: ApplyStrategyReplace,
+		},
+		"invalid value": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "create-once"},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tc.annotations}
+			strategy, err := ReadApplyStrategy(obj)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStrategy, strategy)
+		})
+	}
+}
@@ -25,6 +25,10 @@ const (
 	// used as a suffix of the inventory object name. Example:
 	//   inventory-1e5824fb
 	InventoryHash = "cli-utils.sigs.k8s.io/inventory-hash"
+	// RecreateOnImmutableAnnotation is the annotation key which opts an
+	// object into being deleted and re-created when apply fails because
+	// an immutable field changed. The only supported value is "true".
+	RecreateOnImmutableAnnotation = "cli-utils.sigs.k8s.io/recreate-on-immutable"
 	// Resource lifecycle annotation key for "on-remove" operations.
 	OnRemoveAnnotation = "cli-utils.sigs.k8s.io/on-remove"
 	// Resource lifecycle annotation value to prevent deletion.
@@ -128,4 +132,16 @@ type ServerSideOptions struct {
 
 	// FieldManager identifies the client "owner" of the applied fields (e.g. kubectl)
 	FieldManager string
+
+	// ForceConflictsManagers overwrites the fields when applying, only if all
+	// the conflicting fields are owned by these field managers. This allows
+	// taking over fields from known managers, without ForceConflicts.
+	ForceConflictsManagers []string
+
+	// MigrateClientSideApply migrates the field ownership of objects
+	// previously applied with client-side apply to FieldManager, and removes
+	// their last-applied-configuration annotation, before the server-side
+	// apply. This allows fields removed from the configuration to be removed
+	// from the objects. Objects which fail to migrate are not applied.
+	MigrateClientSideApply bool
 }
@@ -0,0 +1,89 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VerifyAnnotation is the annotation key which declares a verification
+// check, that must pass after the object is applied and reconciled for the
+// apply to count as successful. The value must be one of:
+//   - "job-complete", on a Job: the Job must have completed.
+//   - "http-get:PORT/PATH", on a Service: a GET request to the path on the
+//     port of the Service, through the API server proxy, must return 2xx.
+const VerifyAnnotation = "config.kubernetes.io/verify"
+
+// VerifyCheckType defines what a verification check asserts.
+type VerifyCheckType string
+
+const (
+	// VerifyJobComplete checks that a Job has completed.
+	VerifyJobComplete VerifyCheckType = "job-complete"
+	// VerifyHTTPGet checks that a GET request to a Service succeeds.
+	VerifyHTTPGet VerifyCheckType = "http-get"
+)
+
+var (
+	jobGK     = schema.GroupKind{Group: "batch", Kind: "Job"}
+	serviceGK = schema.GroupKind{Kind: "Service"}
+)
+
+// VerifyCheck is a verification check declared with the VerifyAnnotation.
+type VerifyCheck struct {
+	Type VerifyCheckType
+	// Port is the name or number of the Service port, for VerifyHTTPGet.
+	Port string
+	// Path is the request path, for VerifyHTTPGet.
+	Path string
+}
+
+// String returns the check in the format of the VerifyAnnotation value.
+func (c VerifyCheck) String() string {
+	if c.Type == VerifyHTTPGet {
+		return fmt.Sprintf("%s:%s%s", c.Type, c.Port, c.Path)
+	}
+	return string(c.Type)
+}
+
+// ReadVerifyCheck returns the verification check of the object, from the
+// VerifyAnnotation. Returns false if the annotation is not set, or an error
+// if the value is invalid or not supported by the kind of the object.
+func ReadVerifyCheck(obj *unstructured.Unstructured) (VerifyCheck, bool, error) {
+	value, found := obj.GetAnnotations()[VerifyAnnotation]
+	if !found {
+		return VerifyCheck{}, false, nil
+	}
+	gk := obj.GroupVersionKind().GroupKind()
+	switch {
+	case value == string(VerifyJobComplete):
+		if gk != jobGK {
+			return VerifyCheck{}, false, fmt.Errorf("invalid %q annotation: %q is only supported on %s",
+				VerifyAnnotation, value, jobGK)
+		}
+		return VerifyCheck{Type: VerifyJobComplete}, true, nil
+	case strings.HasPrefix(value, string(VerifyHTTPGet)+":"):
+		if gk != serviceGK {
+			return VerifyCheck{}, false, fmt.Errorf("invalid %q annotation: %q is only supported on %s",
+				VerifyAnnotation, value, serviceGK.Kind)
+		}
+		target := strings.TrimPrefix(value, string(VerifyHTTPGet)+":")
+		port, path := target, "/"
+		if i := strings.Index(target, "/"); i >= 0 {
+			port, path = target[:i], target[i:]
+		}
+		if port == "" {
+			return VerifyCheck{}, false, fmt.Errorf("invalid %q annotation: %q: missing port",
+				VerifyAnnotation, value)
+		}
+		return VerifyCheck{Type: VerifyHTTPGet, Port: port, Path: path}, true, nil
+	default:
+		return VerifyCheck{}, false, fmt.Errorf("invalid %q annotation: %q, must be %q or %q",
+			VerifyAnnotation, value, VerifyJobComplete, string(VerifyHTTPGet)+":PORT/PATH")
+	}
+}
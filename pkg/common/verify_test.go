@@ -0,0 +1,95 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReadVerifyCheck(t *testing.T) {
+	tests := map[string]struct {
+		apiVersion    string
+		kind          string
+		annotations   map[string]string
+		expectedCheck VerifyCheck
+		expectedFound bool
+		expectedErr   bool
+	}{
+		"no annotation": {
+			apiVersion: "batch/v1",
+			kind:       "Job",
+		},
+		"job-complete": {
+			apiVersion:    "batch/v1",
+			kind:          "Job",
+			annotations:   map[string]string{VerifyAnnotation: "job-complete"},
+			expectedCheck: VerifyCheck{Type: VerifyJobComplete},
+			expectedFound: true,
+		},
+		"job-complete on a Service": {
+			apiVersion:  "v1",
+			kind:        "Service",
+			annotations: map[string]string{VerifyAnnotation: "job-complete"},
+			expectedErr: true,
+		},
+		"http-get with path": {
+			apiVersion:    "v1",
+			kind:          "Service",
+			annotations:   map[string]string{VerifyAnnotation: "http-get:8080/healthz"},
+			expectedCheck: VerifyCheck{Type: VerifyHTTPGet, Port: "8080", Path: "/healthz"},
+			expectedFound: true,
+		},
+		"http-get with named port and no path": {
+			apiVersion:    "v1",
+			kind:          "Service",
+			annotations:   map[string]string{VerifyAnnotation: "http-get:http"},
+			expectedCheck: VerifyCheck{Type: VerifyHTTPGet, Port: "http", Path: "/"},
+			expectedFound: true,
+		},
+		"http-get without port": {
+			apiVersion:  "v1",
+			kind:        "Service",
+			annotations: map[string]string{VerifyAnnotation: "http-get:/healthz"},
+			expectedErr: true,
+		},
+		"http-get on a Job": {
+			apiVersion:  "batch/v1",
+			kind:        "Job",
+			annotations: map[string]string{VerifyAnnotation: "http-get:80/"},
+			expectedErr: true,
+		},
+		"invalid value": {
+			apiVersion:  "batch/v1",
+			kind:        "Job",
+			annotations: map[string]string{VerifyAnnotation: "complete"},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(tc.apiVersion)
+			obj.SetKind(tc.kind)
+			obj.SetAnnotations(tc.annotations)
+			check, found, err := ReadVerifyCheck(obj)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedCheck, check)
+		})
+	}
+}
+
+func TestVerifyCheckString(t *testing.T) {
+	assert.Equal(t, "job-complete", VerifyCheck{Type: VerifyJobComplete}.String())
+	assert.Equal(t, "http-get:8080/healthz",
+		VerifyCheck{Type: VerifyHTTPGet, Port: "8080", Path: "/healthz"}.String())
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffFields returns the sorted paths of the fields which differ between
+// the two passed object contents. Maps are compared recursively. Lists of
+// equal length are compared by index, otherwise the list path is returned.
+func DiffFields(a, b map[string]interface{}) []string {
+	var fields []string
+	diffValues("", a, b, &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+func diffValues(path string, a, b interface{}, fields *[]string) {
+	switch aVal := a.(type) {
+	case map[string]interface{}:
+		bVal, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, aField := range aVal {
+			diffValues(path+"."+key, aField, bVal[key], fields)
+		}
+		for key, bField := range bVal {
+			if _, found := aVal[key]; !found {
+				diffValues(path+"."+key, nil, bField, fields)
+			}
+		}
+		return
+	case []interface{}:
+		bVal, ok := b.([]interface{})
+		if !ok || len(aVal) != len(bVal) {
+			break
+		}
+		for i := range aVal {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), aVal[i], bVal[i], fields)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*fields = append(*fields, path)
+	}
+}
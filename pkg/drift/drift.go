@@ -0,0 +1,444 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Package drift detects and corrects differences between the configuration
+// last applied from a set of local manifests and the live state of the
+// objects in the cluster.
+//
+// Objects are compared using a server-side apply dry-run of the local
+// configuration against the live object, so only fields which are set by
+// the local configuration are considered. If the inventory stores the
+// fingerprint of the last apply (see inventory.AppliedHash), objects which
+// have not changed since then are reported in-sync without a dry-run.
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/info"
+	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
+	"sigs.k8s.io/cli-utils/pkg/apply/task"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Status describes the drift state of a single object.
+type Status string
+
+const (
+	// InSync means the live object matches the local configuration.
+	InSync Status = "InSync"
+	// Modified means one or more fields set by the local configuration
+	// have been changed in the cluster.
+	Modified Status = "Modified"
+	// Deleted means the object is stored in the inventory, but no longer
+	// exists in the cluster.
+	Deleted Status = "Deleted"
+	// NotInInventory means the object is in the local configuration, but
+	// has not been applied with the inventory yet. This is not drift.
+	NotInInventory Status = "NotInInventory"
+	// Unknown means the drift state could not be determined. See Error.
+	Unknown Status = "Unknown"
+)
+
+// Result is the drift detection result for a single object.
+type Result struct {
+	// Identifier of the object.
+	Identifier object.ObjMetadata
+	// Status is the drift state of the object.
+	Status Status
+	// Fields are the paths of the fields which differ from the local
+	// configuration, in the format ".spec.template.spec.containers[0].image".
+	Fields []string
+	// ForeignManagers are the field managers, other than the one used to
+	// apply, which own fields set by the local configuration.
+	ForeignManagers []string
+	// Fixed is true if the drift was corrected by re-applying the object.
+	Fixed bool
+	// Error encountered while detecting or fixing drift, if any.
+	Error error
+}
+
+// Drifted returns true if the object has drifted from the local
+// configuration and was not fixed.
+func (r Result) Drifted() bool {
+	return (r.Status == Modified || r.Status == Deleted) && !r.Fixed
+}
+
+// Results is the list of drift detection results for a set of objects.
+type Results []Result
+
+// DriftedCount returns the number of objects which have drifted from the
+// local configuration and were not fixed.
+func (rs Results) DriftedCount() int {
+	count := 0
+	for _, r := range rs {
+		if r.Drifted() {
+			count++
+		}
+	}
+	return count
+}
+
+// ErrorCount returns the number of objects for which drift detection or
+// correction failed.
+func (rs Results) ErrorCount() int {
+	count := 0
+	for _, r := range rs {
+		if r.Error != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Options defines a set of parameters that can be used to tune
+// drift detection.
+type Options struct {
+	// FieldManager is the field manager used to apply the objects.
+	// Defaults to common.DefaultFieldManager.
+	FieldManager string
+
+	// ForceConflicts overwrites the fields owned by other field managers
+	// when fixing drift.
+	ForceConflicts bool
+
+	// ForceConflictsManagers overwrites the fields when fixing drift, only
+	// if all the conflicting field managers are in this list.
+	ForceConflictsManagers []string
+}
+
+// Detector implements Detect to find objects which have drifted from the
+// local configuration and Fix to re-apply them.
+type Detector struct {
+	InvClient     inventory.Client
+	Client        dynamic.Interface
+	OpenAPIGetter discovery.OpenAPISchemaInterface
+	InfoHelper    info.Helper
+	Mapper        meta.RESTMapper
+}
+
+// NewDetector returns a new Detector.
+// Returns an error if dependency injection fails using the factory.
+func NewDetector(factory util.Factory, invClient inventory.Client) (*Detector, error) {
+	client, err := factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := factory.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return &Detector{
+		InvClient:     invClient,
+		Client:        client,
+		OpenAPIGetter: discoveryClient,
+		InfoHelper:    info.NewHelper(mapper, factory.UnstructuredClientForMapping),
+		Mapper:        mapper,
+	}, nil
+}
+
+// Detect compares the passed local objects against the live objects in the
+// cluster and returns a Result for each local object, in the same order.
+// Objects stored in the inventory but missing from the local objects are
+// prune candidates, not drift, and are ignored. Returns an error if the
+// inventory could not be read.
+func (d *Detector) Detect(ctx context.Context, inv inventory.Info, localObjs object.UnstructuredSet, o Options) (Results, error) {
+	setDefaults(&o)
+	invIds, err := d.InvClient.GetClusterObjs(inv)
+	if err != nil {
+		return nil, err
+	}
+	invStatus, err := inventory.GetClusterObjStatus(d.InvClient, inv)
+	if err != nil {
+		return nil, err
+	}
+	statusMap := make(map[object.ObjMetadata]actuation.ObjectStatus, len(invStatus))
+	for _, s := range invStatus {
+		statusMap[inventory.ObjMetadataFromObjectReference(s.ObjectReference)] = s
+	}
+
+	results := make(Results, 0, len(localObjs))
+	for _, localObj := range localObjs {
+		id := object.UnstructuredToObjMetadata(localObj)
+		if !invIds.Contains(id) {
+			results = append(results, Result{Identifier: id, Status: NotInInventory})
+			continue
+		}
+		// The applier adds the inventory annotation before applying, so it
+		// must be added here too, to compare the same configuration.
+		obj := localObj.DeepCopy()
+		inventory.AddInventoryIDAnnotation(obj, inv)
+		result := d.detect(ctx, obj, statusMap, o)
+		klog.V(4).Infof("drift detection result (object: %s): %s", id, result.Status)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (d *Detector) detect(ctx context.Context, obj *unstructured.Unstructured,
+	statusMap map[object.ObjMetadata]actuation.ObjectStatus, o Options) Result {
+	id := object.UnstructuredToObjMetadata(obj)
+	result := Result{Identifier: id, Status: Unknown}
+
+	client, err := d.resourceClient(obj)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			result.Status = Deleted
+			return result
+		}
+		result.Error = fmt.Errorf("failed to get live object: %w", err)
+		return result
+	}
+
+	// Skip the dry-run if the object has not changed since it was applied
+	// and the local configuration is the same as what was applied.
+	if status, found := statusMap[id]; found && status.AppliedHash != "" &&
+		status.ResourceVersion == live.GetResourceVersion() {
+		if hash, err := inventory.AppliedHash(obj); err == nil && hash == status.AppliedHash {
+			result.Status = InSync
+			return result
+		}
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to serialize object: %w", err)
+		return result
+	}
+	dryRun, err := d.dryRunApply(ctx, client, obj.GetName(), data, o.FieldManager, false)
+	if err != nil && apierrors.IsConflict(err) {
+		// Fields owned by other managers with different values have
+		// drifted. Force the dry-run to find all the modified fields.
+		if conflictErr := applyerror.NewApplyConflictError(err); conflictErr != nil {
+			result.ForeignManagers = conflictErr.Managers()
+		}
+		dryRun, err = d.dryRunApply(ctx, client, obj.GetName(), data, o.FieldManager, true)
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("failed to dry-run apply: %w", err)
+		return result
+	}
+
+	result.Fields = DiffFields(normalize(live), normalize(dryRun))
+	if len(result.Fields) > 0 || len(result.ForeignManagers) > 0 {
+		result.Status = Modified
+	} else {
+		result.Status = InSync
+	}
+	return result
+}
+
+// Fix re-applies the local objects which have drifted, with the apply task
+// of the applier, so apply-time mutations and the conflict options are
+// honored. The passed results are updated in place, and must have been
+// returned by Detect for the same local objects. The status of the fixed
+// objects, including the applied fingerprint, is merged into the status
+// stored in the inventory. Returns an error if the inventory could not be
+// updated.
+func (d *Detector) Fix(ctx context.Context, inv inventory.Info, localObjs object.UnstructuredSet, results Results, o Options) error {
+	setDefaults(&o)
+	objMap := make(map[object.ObjMetadata]*unstructured.Unstructured, len(localObjs))
+	for _, localObj := range localObjs {
+		objMap[object.UnstructuredToObjMetadata(localObj)] = localObj
+	}
+	var fixObjs object.UnstructuredSet
+	fixIds := object.ObjMetadataSet{}
+	for _, result := range results {
+		if !result.Drifted() {
+			continue
+		}
+		localObj, found := objMap[result.Identifier]
+		if !found {
+			continue
+		}
+		obj := localObj.DeepCopy()
+		inventory.AddInventoryIDAnnotation(obj, inv)
+		fixObjs = append(fixObjs, obj)
+		fixIds = append(fixIds, result.Identifier)
+	}
+	if len(fixObjs) == 0 {
+		return nil
+	}
+
+	im, applyErrs := d.apply(fixObjs, o)
+	fixed := make(map[object.ObjMetadata]actuation.ObjectStatus)
+	for i := range results {
+		result := &results[i]
+		if !fixIds.Contains(result.Identifier) {
+			continue
+		}
+		if !im.IsSuccessfulApply(result.Identifier) {
+			if err, found := applyErrs[result.Identifier]; found {
+				result.Error = fmt.Errorf("failed to fix drift: %w", err)
+			}
+			continue
+		}
+		klog.V(4).Infof("drift fixed (object: %s)", result.Identifier)
+		result.Fixed = true
+		if status, found := im.ObjectStatus(result.Identifier); found {
+			fixed[result.Identifier] = *status
+		}
+	}
+	if len(fixed) == 0 {
+		return nil
+	}
+	return d.updateStatus(inv, fixed)
+}
+
+// apply applies the objects with an ApplyTask, and returns the inventory
+// manager with the results, and the errors of the objects which failed or
+// were skipped.
+func (d *Detector) apply(objs object.UnstructuredSet, o Options) (*inventory.Manager, map[object.ObjMetadata]error) {
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+	applyTask := &task.ApplyTask{
+		TaskName: "fix-drift",
+		Objects:  objs,
+		Mutators: []mutator.Interface{
+			&mutator.ApplyTimeMutator{
+				Client:        d.Client,
+				Mapper:        d.Mapper,
+				ResourceCache: resourceCache,
+			},
+		},
+		ServerSideOptions: common.ServerSideOptions{
+			ServerSideApply:        true,
+			ForceConflicts:         o.ForceConflicts,
+			ForceConflictsManagers: o.ForceConflictsManagers,
+			FieldManager:           o.FieldManager,
+		},
+		DynamicClient: d.Client,
+		OpenAPIGetter: d.OpenAPIGetter,
+		InfoHelper:    d.InfoHelper,
+		Mapper:        d.Mapper,
+	}
+
+	errs := make(map[object.ObjMetadata]error)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range eventChannel {
+			if e.Type == event.ApplyType && e.ApplyEvent.Error != nil {
+				errs[e.ApplyEvent.Identifier] = e.ApplyEvent.Error
+			}
+		}
+	}()
+	applyTask.Start(taskContext)
+	// The apply task does not support cancellation, so wait for the result.
+	<-taskContext.TaskChannel()
+	close(eventChannel)
+	<-done
+	return taskContext.InventoryManager(), errs
+}
+
+// updateStatus merges the status of the fixed objects into the object status
+// stored in the inventory, so they are not compared with the configuration
+// applied before the fix. The status of the other objects is kept. Nothing
+// is stored if the inventory does not store object status.
+func (d *Detector) updateStatus(inv inventory.Info, fixed map[object.ObjMetadata]actuation.ObjectStatus) error {
+	invStatus, err := inventory.GetClusterObjStatus(d.InvClient, inv)
+	if err != nil {
+		return err
+	}
+	for i := range invStatus {
+		s := &invStatus[i]
+		id := inventory.ObjMetadataFromObjectReference(s.ObjectReference)
+		fixedStatus, found := fixed[id]
+		if !found {
+			continue
+		}
+		s.Strategy = fixedStatus.Strategy
+		s.Actuation = fixedStatus.Actuation
+		s.UID = fixedStatus.UID
+		s.Generation = fixedStatus.Generation
+		s.AppliedHash = fixedStatus.AppliedHash
+		s.ResourceVersion = fixedStatus.ResourceVersion
+		delete(fixed, id)
+	}
+	// Objects without stored status, e.g. applied without it, are added.
+	ids := make(object.ObjMetadataSet, 0, len(fixed))
+	for id := range fixed {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	for _, id := range ids {
+		invStatus = append(invStatus, fixed[id])
+	}
+	invIds, err := d.InvClient.GetClusterObjs(inv)
+	if err != nil {
+		return err
+	}
+	if err := d.InvClient.Replace(inv, invIds, invStatus, common.DryRunNone); err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	return nil
+}
+
+func (d *Detector) dryRunApply(ctx context.Context, client dynamic.ResourceInterface, name string,
+	data []byte, fieldManager string, force bool) (*unstructured.Unstructured, error) {
+	return client.Patch(ctx, name, types.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: fieldManager,
+		Force:        &force,
+	})
+}
+
+func (d *Detector) resourceClient(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	mapping, err := d.Mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map object kind: %w", err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return d.Client.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+	return d.Client.Resource(mapping.Resource), nil
+}
+
+func setDefaults(o *Options) {
+	if o.FieldManager == "" {
+		o.FieldManager = common.DefaultFieldManager
+	}
+}
+
+// normalize returns the object content without the status and the
+// metadata fields which change on every write.
+func normalize(obj *unstructured.Unstructured) map[string]interface{} {
+	u := obj.DeepCopy()
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(u.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(u.Object, "metadata", "generation")
+	return u.Object
+}
@@ -0,0 +1,531 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic/fake"
+	restfake "k8s.io/client-go/rest/fake"
+	clienttesting "k8s.io/client-go/testing"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/info"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var inventoryObj = &unstructured.Unstructured{
+	Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "test-inventory-obj",
+			"namespace": "default",
+			"labels": map[string]interface{}{
+				"cli-utils.sigs.k8s.io/inventory-id": "test-inventory-id",
+			},
+		},
+	},
+}
+
+var deploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 1
+`
+
+// applyReactor returns a reactor that handles apply patches by replacing
+// the spec of the live object with the spec of the patch. If conflictErr is
+// not nil, it is returned by the first patch.
+func applyReactor(t *testing.T, live *unstructured.Unstructured, conflictErr error, patches *int) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clienttesting.PatchAction)
+		require.Equal(t, types.ApplyPatchType, patchAction.GetPatchType())
+		*patches++
+		if conflictErr != nil && *patches == 1 {
+			return true, nil, conflictErr
+		}
+		patch := &unstructured.Unstructured{}
+		require.NoError(t, patch.UnmarshalJSON(patchAction.GetPatch()))
+		result := live.DeepCopy()
+		result.Object["spec"] = patch.Object["spec"]
+		return true, result, nil
+	}
+}
+
+func TestDetect(t *testing.T) {
+	local := testutil.Unstructured(t, deploymentManifest)
+	localID := object.UnstructuredToObjMetadata(local)
+	inv := inventory.WrapInventoryInfoObj(inventoryObj)
+
+	// The live object as it was applied, including the inventory annotation.
+	applied := local.DeepCopy()
+	inventory.AddInventoryIDAnnotation(applied, inv)
+	applied.SetResourceVersion("42")
+	appliedHash, err := inventory.AppliedHash(applied)
+	require.NoError(t, err)
+
+	modified := applied.DeepCopy()
+	require.NoError(t, unstructured.SetNestedField(modified.Object, int64(3), "spec", "replicas"))
+
+	conflictErr := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+	}, "Apply failed with 1 conflict")
+
+	tests := map[string]struct {
+		invIds          object.ObjMetadataSet
+		invStatus       []actuation.ObjectStatus
+		live            *unstructured.Unstructured
+		conflictErr     error
+		expectedResult  Result
+		expectedPatches int
+	}{
+		"object not in inventory": {
+			invIds: object.ObjMetadataSet{},
+			expectedResult: Result{
+				Identifier: localID,
+				Status:     NotInInventory,
+			},
+		},
+		"object deleted": {
+			invIds: object.ObjMetadataSet{localID},
+			expectedResult: Result{
+				Identifier: localID,
+				Status:     Deleted,
+			},
+		},
+		"fingerprint matches": {
+			invIds: object.ObjMetadataSet{localID},
+			invStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(localID),
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+					AppliedHash:     appliedHash,
+					ResourceVersion: "42",
+				},
+			},
+			live: applied,
+			expectedResult: Result{
+				Identifier: localID,
+				Status:     InSync,
+			},
+		},
+		"dry-run in sync": {
+			invIds: object.ObjMetadataSet{localID},
+			live:   applied,
+			expectedResult: Result{
+				Identifier: localID,
+				Status:     InSync,
+			},
+			expectedPatches: 1,
+		},
+		"dry-run modified": {
+			invIds: object.ObjMetadataSet{localID},
+			live:   modified,
+			expectedResult: Result{
+				Identifier: localID,
+				Status:     Modified,
+				Fields:     []string{".spec.replicas"},
+			},
+			expectedPatches: 1,
+		},
+		"dry-run conflict with foreign manager": {
+			invIds:      object.ObjMetadataSet{localID},
+			live:        modified,
+			conflictErr: conflictErr,
+			expectedResult: Result{
+				Identifier:      localID,
+				Status:          Modified,
+				Fields:          []string{".spec.replicas"},
+				ForeignManagers: []string{"kubectl-edit"},
+			},
+			expectedPatches: 2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var clusterObjs []runtime.Object
+			if tc.live != nil {
+				clusterObjs = append(clusterObjs, tc.live)
+			}
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, clusterObjs...)
+			patches := 0
+			if tc.live != nil {
+				client.PrependReactor("patch", "deployments", applyReactor(t, tc.live, tc.conflictErr, &patches))
+			}
+			invClient := inventory.NewFakeClient(tc.invIds)
+			invClient.Status = tc.invStatus
+			detector := &Detector{
+				InvClient: invClient,
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+
+			results, err := detector.Detect(context.TODO(), inv, object.UnstructuredSet{local}, Options{})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tc.expectedResult, results[0])
+			assert.Equal(t, tc.expectedPatches, patches)
+		})
+	}
+}
+
+func TestDetectInventoryError(t *testing.T) {
+	invClient := inventory.NewFakeClient(object.ObjMetadataSet{})
+	invClient.SetError(errors.New("inventory error"))
+	detector := &Detector{InvClient: invClient}
+	_, err := detector.Detect(context.TODO(), inventory.WrapInventoryInfoObj(inventoryObj),
+		object.UnstructuredSet{}, Options{})
+	assert.EqualError(t, err, "inventory error")
+}
+
+// fakeApplyServer handles the server-side apply patches of the apply task,
+// by replacing the spec of the live object with the spec of the patch. If
+// conflictErr is not nil, it is returned by patches which do not force
+// conflicts.
+type fakeApplyServer struct {
+	live        *unstructured.Unstructured
+	conflictErr *apierrors.StatusError
+	patches     []*unstructured.Unstructured
+	forced      []bool
+}
+
+func (s *fakeApplyServer) infoHelper(t *testing.T, mapper meta.RESTMapper) info.Helper {
+	restClient := &restfake.RESTClient{
+		NegotiatedSerializer: resource.UnstructuredPlusDefaultContentConfig().NegotiatedSerializer,
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, http.MethodPatch, req.Method)
+			require.Equal(t, "/namespaces/default/deployments/foo", req.URL.Path)
+			data, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			patch := &unstructured.Unstructured{}
+			require.NoError(t, patch.UnmarshalJSON(data))
+			s.patches = append(s.patches, patch)
+			force := req.URL.Query().Get("force") == "true"
+			s.forced = append(s.forced, force)
+			if s.conflictErr != nil && !force {
+				status := s.conflictErr.ErrStatus
+				status.APIVersion = "v1"
+				status.Kind = "Status"
+				body, err := json.Marshal(status)
+				require.NoError(t, err)
+				return &http.Response{StatusCode: http.StatusConflict, Header: cmdtesting.DefaultHeader(),
+					Body: io.NopCloser(bytes.NewReader(body))}, nil
+			}
+			s.live.Object["spec"] = patch.Object["spec"]
+			body, err := s.live.MarshalJSON()
+			require.NoError(t, err)
+			return &http.Response{StatusCode: http.StatusOK, Header: cmdtesting.DefaultHeader(),
+				Body: io.NopCloser(bytes.NewReader(body))}, nil
+		}),
+	}
+	return info.NewHelper(mapper, func(*meta.RESTMapping) (resource.RESTClient, error) {
+		return restClient, nil
+	})
+}
+
+var mutatedDeploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    config.kubernetes.io/apply-time-mutation: |
+      - sourceRef:
+          kind: ConfigMap
+          name: source
+          namespace: default
+        sourcePath: $.data.image
+        targetPath: $.spec.template.spec.containers[0].image
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: placeholder
+`
+
+var sourceManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+  namespace: default
+data:
+  image: example:1.0
+`
+
+func TestFix(t *testing.T) {
+	local := testutil.Unstructured(t, deploymentManifest)
+	localID := object.UnstructuredToObjMetadata(local)
+	mutated := testutil.Unstructured(t, mutatedDeploymentManifest)
+	inv := inventory.WrapInventoryInfoObj(inventoryObj)
+	otherID := object.ObjMetadata{Name: "in-sync"}
+	otherStatus := actuation.ObjectStatus{
+		ObjectReference: inventory.ObjectReferenceFromObjMetadata(otherID),
+		AppliedHash:     "other",
+		ResourceVersion: "3",
+	}
+	staleStatus := actuation.ObjectStatus{
+		ObjectReference: inventory.ObjectReferenceFromObjMetadata(localID),
+		Strategy:        actuation.ActuationStrategyApply,
+		Actuation:       actuation.ActuationSucceeded,
+		UID:             "uid-1",
+		Generation:      1,
+		AppliedHash:     "before-drift",
+		ResourceVersion: "1",
+	}
+
+	conflictErr := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+	}, `Apply failed with 1 conflict: conflict with "kubectl-edit" using apps/v1: .spec.replicas`)
+
+	tests := map[string]struct {
+		local           *unstructured.Unstructured
+		invStatus       []actuation.ObjectStatus
+		conflictErr     *apierrors.StatusError
+		options         Options
+		expectedPatches int
+		expectedForce   bool
+		expectedFixed   bool
+		expectedErr     string
+		// expectedStatus of the fixed object, followed by otherStatus.
+		expectedStatus bool
+		// statusAppended is true if the fixed object had no stored status.
+		statusAppended bool
+	}{
+		"stored status updated": {
+			local:           local,
+			invStatus:       []actuation.ObjectStatus{staleStatus, otherStatus},
+			expectedPatches: 1,
+			expectedFixed:   true,
+			expectedStatus:  true,
+		},
+		"missing stored status appended": {
+			local:           local,
+			invStatus:       []actuation.ObjectStatus{otherStatus},
+			expectedPatches: 1,
+			expectedFixed:   true,
+			expectedStatus:  true,
+			statusAppended:  true,
+		},
+		"apply-time mutation": {
+			local:           mutated,
+			invStatus:       []actuation.ObjectStatus{staleStatus, otherStatus},
+			expectedPatches: 1,
+			expectedFixed:   true,
+			expectedStatus:  true,
+		},
+		"conflict not forced": {
+			local:           local,
+			invStatus:       []actuation.ObjectStatus{staleStatus, otherStatus},
+			conflictErr:     conflictErr,
+			expectedPatches: 1,
+			expectedErr:     `failed to fix drift: apply failed with 1 conflict: .spec.replicas (manager: "kubectl-edit")`,
+		},
+		"conflict forced from allowed manager": {
+			local:           local,
+			invStatus:       []actuation.ObjectStatus{staleStatus, otherStatus},
+			conflictErr:     conflictErr,
+			options:         Options{ForceConflictsManagers: []string{"kubectl-edit"}},
+			expectedPatches: 2,
+			expectedForce:   true,
+			expectedFixed:   true,
+			expectedStatus:  true,
+		},
+		"conflict forced": {
+			local:           local,
+			invStatus:       []actuation.ObjectStatus{staleStatus, otherStatus},
+			conflictErr:     conflictErr,
+			options:         Options{ForceConflicts: true},
+			expectedPatches: 1,
+			expectedForce:   true,
+			expectedFixed:   true,
+			expectedStatus:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			live := testutil.Unstructured(t, deploymentManifest)
+			live.SetUID("uid-2")
+			live.SetGeneration(2)
+			live.SetResourceVersion("5")
+			source := testutil.Unstructured(t, sourceManifest)
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, live.DeepCopy(), source)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			server := &fakeApplyServer{live: live, conflictErr: tc.conflictErr}
+			invClient := inventory.NewFakeClient(object.ObjMetadataSet{localID, otherID})
+			invClient.Status = tc.invStatus
+			detector := &Detector{
+				InvClient:  invClient,
+				Client:     client,
+				InfoHelper: server.infoHelper(t, mapper),
+				Mapper:     mapper,
+			}
+
+			results := Results{
+				{Identifier: localID, Status: Modified, Fields: []string{".spec.replicas"}},
+				{Identifier: otherID, Status: InSync},
+			}
+			err := detector.Fix(context.TODO(), inv, object.UnstructuredSet{tc.local}, results, tc.options)
+			require.NoError(t, err)
+
+			require.Len(t, server.patches, tc.expectedPatches)
+			lastPatch := server.patches[len(server.patches)-1]
+			assert.Equal(t, tc.expectedForce, server.forced[len(server.forced)-1])
+			assert.Equal(t, "test-inventory-id",
+				lastPatch.GetAnnotations()["config.k8s.io/owning-inventory"])
+			if tc.local == mutated {
+				image, found, err := unstructured.NestedSlice(lastPatch.Object, "spec", "template", "spec", "containers")
+				require.NoError(t, err)
+				require.True(t, found)
+				assert.Equal(t, "example:1.0", image[0].(map[string]interface{})["image"])
+			}
+			assert.Equal(t, tc.expectedFixed, results[0].Fixed)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, results[0].Error, tc.expectedErr)
+			} else {
+				assert.NoError(t, results[0].Error)
+			}
+			assert.False(t, results[1].Fixed)
+
+			if !tc.expectedStatus {
+				testutil.AssertEqual(t, tc.invStatus, invClient.Status)
+				return
+			}
+			// The fingerprint is the hash of the configuration before the
+			// apply-time mutations.
+			applied := tc.local.DeepCopy()
+			inventory.AddInventoryIDAnnotation(applied, inv)
+			appliedHash, err := inventory.AppliedHash(applied)
+			require.NoError(t, err)
+			fixedStatus := actuation.ObjectStatus{
+				ObjectReference: inventory.ObjectReferenceFromObjMetadata(localID),
+				Strategy:        actuation.ActuationStrategyApply,
+				Actuation:       actuation.ActuationSucceeded,
+				UID:             "uid-2",
+				Generation:      2,
+				AppliedHash:     appliedHash,
+				ResourceVersion: "5",
+			}
+			expectedStatus := []actuation.ObjectStatus{fixedStatus, otherStatus}
+			if tc.statusAppended {
+				expectedStatus = []actuation.ObjectStatus{otherStatus, fixedStatus}
+			}
+			testutil.AssertEqual(t, expectedStatus, invClient.Status)
+			testutil.AssertEqual(t, object.ObjMetadataSet{localID, otherID}, invClient.Objs)
+		})
+	}
+}
+
+func TestFixInventoryError(t *testing.T) {
+	local := testutil.Unstructured(t, deploymentManifest)
+	localID := object.UnstructuredToObjMetadata(local)
+
+	live := local.DeepCopy()
+	client := fake.NewSimpleDynamicClient(scheme.Scheme, live.DeepCopy())
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+	server := &fakeApplyServer{live: live}
+	invClient := inventory.NewFakeClient(object.ObjMetadataSet{localID})
+	invClient.Err = errors.New("inventory error")
+	detector := &Detector{
+		InvClient:  invClient,
+		Client:     client,
+		InfoHelper: server.infoHelper(t, mapper),
+		Mapper:     mapper,
+	}
+
+	results := Results{{Identifier: localID, Status: Modified}}
+	err := detector.Fix(context.TODO(), inventory.WrapInventoryInfoObj(inventoryObj),
+		object.UnstructuredSet{local}, results, Options{})
+	assert.EqualError(t, err, "inventory error")
+	assert.True(t, results[0].Fixed)
+}
+
+func TestDiffFields(t *testing.T) {
+	tests := map[string]struct {
+		a        map[string]interface{}
+		b        map[string]interface{}
+		expected []string
+	}{
+		"equal": {
+			a: map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+			b: map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+		},
+		"changed, added and removed fields": {
+			a: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(1), "paused": true},
+			},
+			b: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(2), "minReadySeconds": int64(5)},
+			},
+			expected: []string{".spec.minReadySeconds", ".spec.paused", ".spec.replicas"},
+		},
+		"list of equal length": {
+			a: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"image": "a"},
+					map[string]interface{}{"image": "b"},
+				},
+			},
+			b: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"image": "a"},
+					map[string]interface{}{"image": "c"},
+				},
+			},
+			expected: []string{".containers[1].image"},
+		},
+		"list of different length": {
+			a:        map[string]interface{}{"args": []interface{}{"a"}},
+			b:        map[string]interface{}{"args": []interface{}{"a", "b"}},
+			expected: []string{".args"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DiffFields(tc.a, tc.b))
+		})
+	}
+}
@@ -0,0 +1,469 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Introduces the ApplySet struct which implements the Inventory
+// interface using the ApplySet standard (KEP-3659). The members of the
+// set are labelled with the ID of the parent object, and the parent
+// object records the group kinds and namespaces of the members. The
+// parent object also records the members themselves, so that objects
+// which were never labelled, like objects which failed to apply, stay in
+// the inventory. That record is specific to this package: kubectl only
+// sees the labelled members.
+//
+// kubectl only manages an ApplySet if the tooling annotation of the
+// parent object names kubectl, so new parent objects are created with the
+// kubectl tooling by default (see DefaultApplySetTooling), and can be
+// inspected and pruned with `kubectl apply --prune --applyset`. The tooling
+// of existing parent objects is kept.
+
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const (
+	// ApplySetParentIDLabel is the label on the ApplySet parent object
+	// storing the ID of the ApplySet.
+	ApplySetParentIDLabel = "applyset.kubernetes.io/id"
+	// ApplySetPartOfLabel is the label on the ApplySet members storing the
+	// ID of the ApplySet they belong to.
+	ApplySetPartOfLabel = "applyset.kubernetes.io/part-of"
+	// ApplySetToolingAnnotation is the annotation on the ApplySet parent
+	// object storing the tool which manages the ApplySet.
+	ApplySetToolingAnnotation = "applyset.kubernetes.io/tooling"
+	// ApplySetGKsAnnotation is the annotation on the ApplySet parent object
+	// storing the sorted, comma-separated group kinds of the members.
+	ApplySetGKsAnnotation = "applyset.kubernetes.io/contains-group-kinds"
+	// ApplySetAdditionalNamespacesAnnotation is the annotation on the
+	// ApplySet parent object storing the sorted, comma-separated namespaces
+	// of the members, other than the namespace of the parent.
+	ApplySetAdditionalNamespacesAnnotation = "applyset.kubernetes.io/additional-namespaces"
+	// ApplySetMembersAnnotation is the annotation on the ApplySet parent
+	// object storing the sorted, comma-separated object metadata of the
+	// members, including the members which are not labelled. kubectl
+	// ignores it, and only sees the labelled members.
+	ApplySetMembersAnnotation = "cli-utils.sigs.k8s.io/applyset-members"
+	// ApplySetStatusAnnotation is the annotation on the ApplySet parent
+	// object storing the object status of the members, as a JSON object
+	// keyed by object metadata, when applied with StatusPolicyAll.
+	ApplySetStatusAnnotation = "cli-utils.sigs.k8s.io/applyset-status"
+
+	// DefaultApplySetTooling is the tooling set on new ApplySet parent
+	// objects, unless another one is configured. kubectl only compares the
+	// name of the tooling, not the version, so parent objects created with
+	// it are managed by any kubectl which supports ApplySets. The tooling of
+	// existing parent objects is preserved.
+	DefaultApplySetTooling = "kubectl/v1.27"
+)
+
+// ApplySetID returns the ID of the ApplySet with the passed parent object,
+// as defined by KEP-3659.
+func ApplySetID(parent *unstructured.Unstructured) string {
+	gvk := parent.GroupVersionKind()
+	unencoded := strings.Join([]string{parent.GetName(), parent.GetNamespace(), gvk.Kind, gvk.Group}, ".")
+	hashed := sha256.Sum256([]byte(unencoded))
+	return fmt.Sprintf("applyset-%s-v1", base64.RawURLEncoding.EncodeToString(hashed[:]))
+}
+
+// WrapApplySetInfoObj takes a passed ApplySet parent object, wraps it
+// with the ApplySet and upcasts the wrapper as an the Info interface.
+func WrapApplySetInfoObj(parent *unstructured.Unstructured) Info {
+	return &ApplySet{parent: parent}
+}
+
+// ApplySetStorageFactory returns a StorageFactoryFunc which wraps ApplySet
+// parent objects. The passed client and mapper are used to look up the
+// members of the ApplySet. The passed tooling is set on new parent objects;
+// empty means DefaultApplySetTooling.
+func ApplySetStorageFactory(dc dynamic.Interface, mapper meta.RESTMapper, tooling string) StorageFactoryFunc {
+	if tooling == "" {
+		tooling = DefaultApplySetTooling
+	}
+	return func(parent *unstructured.Unstructured) Storage {
+		return &ApplySet{parent: parent, dc: dc, mapper: mapper, tooling: tooling}
+	}
+}
+
+// InvInfoToApplySet returns the parent object of the passed ApplySet,
+// or nil if the passed Info is not an ApplySet.
+func InvInfoToApplySet(inv Info) *unstructured.Unstructured {
+	as, ok := inv.(*ApplySet)
+	if ok {
+		return as.parent
+	}
+	return nil
+}
+
+// ApplySet wraps an ApplySet parent object and implements the Inventory
+// interface. The object metadata is loaded by listing the objects labelled
+// as members of the ApplySet, and stored in the parent object as the
+// group kinds and namespaces of the members.
+type ApplySet struct {
+	parent    *unstructured.Unstructured
+	dc        dynamic.Interface
+	mapper    meta.RESTMapper
+	tooling   string
+	objMetas  object.ObjMetadataSet
+	objStatus []actuation.ObjectStatus
+}
+
+var _ Info = &ApplySet{}
+var _ Storage = &ApplySet{}
+var _ StatusLoader = &ApplySet{}
+
+func (as *ApplySet) Name() string {
+	return as.parent.GetName()
+}
+
+func (as *ApplySet) Namespace() string {
+	return as.parent.GetNamespace()
+}
+
+// ID returns the ApplySet ID, which is derived from the name, namespace
+// and group kind of the parent object.
+func (as *ApplySet) ID() string {
+	return ApplySetID(as.parent)
+}
+
+func (as *ApplySet) Strategy() Strategy {
+	return NameStrategy
+}
+
+// Load is an Inventory interface function returning the set of object
+// metadata of the ApplySet members, or an error. The members are the
+// objects recorded in the parent object, and the objects listed by the
+// part-of label, for the group kinds and namespaces recorded in the parent
+// object, like the members added by other tools.
+func (as *ApplySet) Load() (object.ObjMetadataSet, error) {
+	objs := object.ObjMetadataSet{}
+	if as.dc == nil || as.mapper == nil {
+		return objs, fmt.Errorf("unable to list ApplySet members without a client")
+	}
+	annotations := as.parent.GetAnnotations()
+	for _, str := range splitList(annotations[ApplySetMembersAnnotation]) {
+		id, err := object.ParseObjMetadata(str)
+		if err != nil {
+			return objs, object.InvalidAnnotationError{
+				Annotation: ApplySetMembersAnnotation,
+				Cause:      err,
+			}
+		}
+		objs = append(objs, id)
+	}
+	gks := splitList(annotations[ApplySetGKsAnnotation])
+	namespaces := append([]string{as.parent.GetNamespace()}, splitList(annotations[ApplySetAdditionalNamespacesAnnotation])...)
+	labelSelector := fmt.Sprintf("%s=%s", ApplySetPartOfLabel, as.ID())
+	for _, gkStr := range gks {
+		gk := schema.ParseGroupKind(gkStr)
+		mapping, err := as.mapper.RESTMapping(gk)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				// The type is not served anymore, e.g. its CRD was deleted.
+				// Keep the recorded members of the type.
+				klog.V(4).Infof("ApplySet member group kind not found: %q", gkStr)
+				continue
+			}
+			return objs, fmt.Errorf("failed to map ApplySet member group kind %q: %w", gkStr, err)
+		}
+		listNamespaces := namespaces
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			listNamespaces = []string{""}
+		}
+		for _, ns := range listNamespaces {
+			klog.V(4).Infof("ApplySet member fetch by label (resource: %q, namespace: %q, selector: %q)",
+				mapping.Resource, ns, labelSelector)
+			uList, err := as.dc.Resource(mapping.Resource).Namespace(ns).List(context.TODO(), metav1.ListOptions{
+				LabelSelector: labelSelector,
+			})
+			if err != nil {
+				return objs, err
+			}
+			for i := range uList.Items {
+				objs = objs.Union(object.ObjMetadataSet{object.UnstructuredToObjMetadata(&uList.Items[i])})
+			}
+		}
+	}
+	return objs, nil
+}
+
+// LoadStatus returns the object status stored in the wrapped parent object,
+// or an error. Objects stored without status are omitted.
+func (as *ApplySet) LoadStatus() ([]actuation.ObjectStatus, error) {
+	var objStatus []actuation.ObjectStatus
+	data, found := as.parent.GetAnnotations()[ApplySetStatusAnnotation]
+	if !found {
+		return objStatus, nil
+	}
+	var objMap map[string]string
+	if err := json.Unmarshal([]byte(data), &objMap); err != nil {
+		return objStatus, object.InvalidAnnotationError{
+			Annotation: ApplySetStatusAnnotation,
+			Cause:      err,
+		}
+	}
+	for objStr, statusStr := range objMap {
+		if statusStr == "" {
+			continue
+		}
+		id, err := object.ParseObjMetadata(objStr)
+		if err != nil {
+			return objStatus, err
+		}
+		status, err := statusFrom(id, statusStr)
+		if err != nil {
+			return objStatus, err
+		}
+		objStatus = append(objStatus, status)
+	}
+	return objStatus, nil
+}
+
+// Store is an Inventory interface function implemented to store the
+// object metadata in the wrapped parent object. Actual storing happens
+// in "GetObject".
+func (as *ApplySet) Store(objMetas object.ObjMetadataSet, status []actuation.ObjectStatus) error {
+	as.objMetas = objMetas
+	as.objStatus = status
+	return nil
+}
+
+// GetObject returns a copy of the wrapped parent object with the ApplySet
+// labels and annotations for the stored object metadata.
+func (as *ApplySet) GetObject() (*unstructured.Unstructured, error) {
+	parent := as.parent.DeepCopy()
+
+	labels := parent.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ApplySetParentIDLabel] = ApplySetID(parent)
+	parent.SetLabels(labels)
+
+	gks := sets.NewString()
+	namespaces := sets.NewString()
+	members := sets.NewString()
+	for _, id := range as.objMetas {
+		members.Insert(id.String())
+		gks.Insert(id.GroupKind.String())
+		if id.Namespace != "" && id.Namespace != parent.GetNamespace() {
+			namespaces.Insert(id.Namespace)
+		}
+	}
+	annotations := parent.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if _, found := annotations[ApplySetToolingAnnotation]; !found {
+		tooling := as.tooling
+		if tooling == "" {
+			tooling = DefaultApplySetTooling
+		}
+		annotations[ApplySetToolingAnnotation] = tooling
+	}
+	annotations[ApplySetGKsAnnotation] = strings.Join(gks.List(), ",")
+	annotations[ApplySetMembersAnnotation] = strings.Join(members.List(), ",")
+	if namespaces.Len() > 0 {
+		annotations[ApplySetAdditionalNamespacesAnnotation] = strings.Join(namespaces.List(), ",")
+	} else {
+		delete(annotations, ApplySetAdditionalNamespacesAnnotation)
+	}
+	parent.SetAnnotations(annotations)
+	return parent, nil
+}
+
+// Apply is an Storage interface function implemented to apply the parent
+// object. With StatusPolicyAll, the object status and a StatusSummary are
+// stored in annotations. With StatusPolicyNone, no object status is stored.
+func (as *ApplySet) Apply(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy StatusPolicy) error {
+	parent, client, err := as.getNamespacedClient(dc, mapper, statusPolicy)
+	if err != nil {
+		return err
+	}
+
+	// Get cluster object, if exsists.
+	_, err = client.Get(context.TODO(), parent.GetName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// Create cluster parent object, if it does not exist on cluster.
+	if apierrors.IsNotFound(err) {
+		klog.V(4).Infof("creating ApplySet parent object: %s/%s", parent.GetNamespace(), parent.GetName())
+		_, err = client.Create(context.TODO(), parent, metav1.CreateOptions{})
+		return err
+	}
+
+	// Update the cluster parent object instead.
+	klog.V(4).Infof("updating ApplySet parent object: %s/%s", parent.GetNamespace(), parent.GetName())
+	_, err = client.Update(context.TODO(), parent, metav1.UpdateOptions{})
+	return err
+}
+
+// ApplyWithPrune is a Storage interface function implemented to apply the
+// parent object with a list of objects to be pruned. The StatusPolicy is
+// handled the same as in Apply.
+func (as *ApplySet) ApplyWithPrune(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy StatusPolicy, _ object.ObjMetadataSet) error {
+	parent, client, err := as.getNamespacedClient(dc, mapper, statusPolicy)
+	if err != nil {
+		return err
+	}
+
+	// Update the cluster parent object.
+	klog.V(4).Infof("updating ApplySet parent object: %s/%s", parent.GetNamespace(), parent.GetName())
+	_, err = client.Update(context.TODO(), parent, metav1.UpdateOptions{})
+	return err
+}
+
+// getNamespacedClient is a helper function for Apply and ApplyWithPrune
+// that returns the parent object to apply and a namespaced client for it.
+func (as *ApplySet) getNamespacedClient(dc dynamic.Interface, mapper meta.RESTMapper,
+	statusPolicy StatusPolicy) (*unstructured.Unstructured, dynamic.ResourceInterface, error) {
+	parent, err := as.GetObject()
+	if err != nil {
+		return nil, nil, err
+	}
+	if statusPolicy == StatusPolicyAll {
+		refs := make([]actuation.ObjectReference, 0, len(as.objMetas))
+		for _, id := range as.objMetas {
+			refs = append(refs, ObjectReferenceFromObjMetadata(id))
+		}
+		if err := setStatusSummary(parent, NewStatusSummary(refs, as.objStatus)); err != nil {
+			return nil, nil, err
+		}
+		if err := setApplySetStatus(parent, as.objMetas, as.objStatus); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		removeStatusSummary(parent)
+		removeApplySetStatus(parent)
+	}
+
+	mapping, err := mapper.RESTMapping(parent.GroupVersionKind().GroupKind(), parent.GroupVersionKind().Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parent, dc.Resource(mapping.Resource).Namespace(parent.GetNamespace()), nil
+}
+
+// setApplySetStatus stores the status of the passed members on the passed
+// parent object, as an annotation. Members without status are omitted.
+func setApplySetStatus(parent *unstructured.Unstructured, objMetas object.ObjMetadataSet, objStatus []actuation.ObjectStatus) error {
+	objMap := buildObjMap(objMetas, objStatus)
+	for objStr, statusStr := range objMap {
+		if statusStr == "" {
+			delete(objMap, objStr)
+		}
+	}
+	data, err := json.Marshal(objMap)
+	if err != nil {
+		return fmt.Errorf("failed to serialize ApplySet status: %w", err)
+	}
+	annotations := parent.GetAnnotations()
+	annotations[ApplySetStatusAnnotation] = string(data)
+	parent.SetAnnotations(annotations)
+	return nil
+}
+
+// removeApplySetStatus removes the object status from the passed parent
+// object, if present.
+func removeApplySetStatus(parent *unstructured.Unstructured) {
+	annotations := parent.GetAnnotations()
+	if _, found := annotations[ApplySetStatusAnnotation]; !found {
+		return
+	}
+	delete(annotations, ApplySetStatusAnnotation)
+	parent.SetAnnotations(annotations)
+}
+
+// splitList splits a comma-separated annotation value, ignoring empty
+// entries.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// addApplySetPartOfLabel labels the passed object as a member of the passed
+// inventory, if the inventory is an ApplySet.
+func addApplySetPartOfLabel(obj *unstructured.Unstructured, inv Info) {
+	if _, ok := inv.(*ApplySet); !ok {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[ApplySetPartOfLabel] = inv.ID()
+	obj.SetLabels(labels)
+}
+
+// ClusterInventoryID returns the inventory ID stored in the labels of the
+// passed cluster inventory object. This is the ApplySet ID for ApplySet
+// parent objects, otherwise the inventory-id label.
+func ClusterInventoryID(obj *unstructured.Unstructured) string {
+	labels := obj.GetLabels()
+	if id, found := labels[ApplySetParentIDLabel]; found {
+		return id
+	}
+	return labels[common.InventoryLabel]
+}
+
+// ApplySetClientFactory is a factory that creates instances of ClusterClient
+// inventory client which use ApplySet parent objects of the passed GVK.
+type ApplySetClientFactory struct {
+	StatusPolicy StatusPolicy
+	// ParentGVK is the GVK of the parent objects. Defaults to ConfigMap.
+	ParentGVK schema.GroupVersionKind
+	// Tooling is set on new parent objects. Defaults to
+	// DefaultApplySetTooling, which lets kubectl manage the ApplySets.
+	Tooling string
+}
+
+var _ ClientFactory = ApplySetClientFactory{}
+var _ InfoWrapper = ApplySetClientFactory{}
+
+// WrapInfo wraps the passed parent object with the ApplySet.
+func (acf ApplySetClientFactory) WrapInfo(obj *unstructured.Unstructured) Info {
+	return WrapApplySetInfoObj(obj)
+}
+
+func (acf ApplySetClientFactory) NewClient(factory cmdutil.Factory) (Client, error) {
+	dc, err := factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	gvk := acf.ParentGVK
+	if gvk.Empty() {
+		gvk = ConfigMapGVK
+	}
+	return NewClient(factory, ApplySetStorageFactory(dc, mapper, acf.Tooling), InvInfoToApplySet, acf.StatusPolicy, gvk)
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const testApplySetID = "applyset-_ZycLF4irr7JRG-NBbfGXwjEzKr_4i0JGAoE2hvh_9w-v1"
+
+var applySetParent = &unstructured.Unstructured{
+	Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "test-applyset",
+			"namespace": testNamespace,
+		},
+	},
+}
+
+func applySetMember(kind, namespace, name, applySetID string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		},
+	}
+	obj.SetNamespace(namespace)
+	if applySetID != "" {
+		obj.SetLabels(map[string]string{ApplySetPartOfLabel: applySetID})
+	}
+	return obj
+}
+
+func TestApplySetID(t *testing.T) {
+	assert.Equal(t, testApplySetID, ApplySetID(applySetParent))
+	assert.Equal(t, testApplySetID, WrapApplySetInfoObj(applySetParent).ID())
+}
+
+func TestApplySetGetObject(t *testing.T) {
+	tests := map[string]struct {
+		parent              *unstructured.Unstructured
+		tooling             string
+		objs                object.ObjMetadataSet
+		expectedAnnotations map[string]string
+	}{
+		"no objects": {
+			parent: applySetParent,
+			objs:   object.ObjMetadataSet{},
+			expectedAnnotations: map[string]string{
+				ApplySetToolingAnnotation: DefaultApplySetTooling,
+				ApplySetGKsAnnotation:     "",
+				ApplySetMembersAnnotation: "",
+			},
+		},
+		"objects in multiple namespaces": {
+			parent: applySetParent,
+			objs: object.ObjMetadataSet{
+				ignoreErrInfoToObjMeta(pod1Info),
+				{GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"}, Namespace: "other", Name: "foo"},
+				{GroupKind: schema.GroupKind{Kind: "Namespace"}, Name: "other"},
+			},
+			expectedAnnotations: map[string]string{
+				ApplySetToolingAnnotation:              DefaultApplySetTooling,
+				ApplySetGKsAnnotation:                  "Deployment.apps,Namespace,Pod",
+				ApplySetAdditionalNamespacesAnnotation: "other",
+				ApplySetMembersAnnotation:              "_other__Namespace,other_foo_apps_Deployment,test-inventory-namespace_pod-1__Pod",
+			},
+		},
+		"existing tooling is preserved": {
+			parent: func() *unstructured.Unstructured {
+				parent := applySetParent.DeepCopy()
+				parent.SetAnnotations(map[string]string{
+					ApplySetToolingAnnotation:              "example/v1",
+					ApplySetAdditionalNamespacesAnnotation: "stale",
+				})
+				return parent
+			}(),
+			objs: object.ObjMetadataSet{ignoreErrInfoToObjMeta(pod1Info)},
+			expectedAnnotations: map[string]string{
+				ApplySetToolingAnnotation: "example/v1",
+				ApplySetGKsAnnotation:     "Pod",
+				ApplySetMembersAnnotation: "test-inventory-namespace_pod-1__Pod",
+			},
+		},
+		"configured tooling": {
+			parent:  applySetParent,
+			tooling: "example/v1",
+			objs:    object.ObjMetadataSet{},
+			expectedAnnotations: map[string]string{
+				ApplySetToolingAnnotation: "example/v1",
+				ApplySetGKsAnnotation:     "",
+				ApplySetMembersAnnotation: "",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			as := ApplySetStorageFactory(nil, nil, tc.tooling)(tc.parent)
+			require.NoError(t, as.Store(tc.objs, nil))
+			parent, err := as.GetObject()
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{ApplySetParentIDLabel: testApplySetID}, parent.GetLabels())
+			assert.Equal(t, tc.expectedAnnotations, parent.GetAnnotations())
+		})
+	}
+}
+
+func TestApplySetLoad(t *testing.T) {
+	parent := applySetParent.DeepCopy()
+	parent.SetAnnotations(map[string]string{
+		ApplySetGKsAnnotation:                  "Namespace,Pod,Widget.example.com",
+		ApplySetAdditionalNamespacesAnnotation: "other",
+		// pod-3 failed to apply, so it is not labelled. The type of the
+		// widget is not served anymore.
+		ApplySetMembersAnnotation: "test-inventory-namespace_pod-1__Pod,test-inventory-namespace_pod-3__Pod," +
+			"test-inventory-namespace_widget_example.com_Widget",
+	})
+	clusterObjs := []runtime.Object{
+		applySetMember("Pod", testNamespace, "pod-1", testApplySetID),
+		applySetMember("Pod", "other", "pod-2", testApplySetID),
+		applySetMember("Pod", testNamespace, "not-a-member", ""),
+		applySetMember("Pod", testNamespace, "other-applyset", "applyset-other-v1"),
+		applySetMember("Namespace", "", "other", testApplySetID),
+		// Group kind not recorded in the parent
+		applySetMember("Service", testNamespace, "svc", testApplySetID),
+	}
+	client := fake.NewSimpleDynamicClient(scheme.Scheme, clusterObjs...)
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+
+	objs, err := ApplySetStorageFactory(client, mapper, "")(parent).Load()
+	require.NoError(t, err)
+	expected := object.ObjMetadataSet{
+		{GroupKind: schema.GroupKind{Kind: "Namespace"}, Name: "other"},
+		{GroupKind: schema.GroupKind{Kind: "Pod"}, Namespace: testNamespace, Name: "pod-1"},
+		{GroupKind: schema.GroupKind{Kind: "Pod"}, Namespace: "other", Name: "pod-2"},
+		{GroupKind: schema.GroupKind{Kind: "Pod"}, Namespace: testNamespace, Name: "pod-3"},
+		{GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"}, Namespace: testNamespace, Name: "widget"},
+	}
+	assert.ElementsMatch(t, expected, objs)
+}
+
+func TestApplySetLoadWithoutClient(t *testing.T) {
+	_, err := ApplySetStorageFactory(nil, nil, "")(applySetParent).Load()
+	assert.Error(t, err)
+}
+
+func TestApplySetApply(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(scheme.Scheme)
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+	cmResource := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	storageFunc := ApplySetStorageFactory(client, mapper, "")
+
+	// Create
+	as := storageFunc(applySetParent.DeepCopy())
+	require.NoError(t, as.Store(object.ObjMetadataSet{ignoreErrInfoToObjMeta(pod1Info)}, nil))
+	require.NoError(t, as.Apply(client, mapper, StatusPolicyNone))
+	clusterObj, err := client.Resource(cmResource).Namespace(testNamespace).
+		Get(context.TODO(), "test-applyset", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, testApplySetID, ClusterInventoryID(clusterObj))
+	assert.Equal(t, "Pod", clusterObj.GetAnnotations()[ApplySetGKsAnnotation])
+
+	// Replace with fewer objects
+	as = storageFunc(clusterObj)
+	require.NoError(t, as.Store(object.ObjMetadataSet{}, nil))
+	require.NoError(t, as.ApplyWithPrune(client, mapper, StatusPolicyNone, object.ObjMetadataSet{}))
+	clusterObj, err = client.Resource(cmResource).Namespace(testNamespace).
+		Get(context.TODO(), "test-applyset", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "", clusterObj.GetAnnotations()[ApplySetGKsAnnotation])
+}
+
+func TestApplySetStatus(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(scheme.Scheme)
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+	cmResource := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	storageFunc := ApplySetStorageFactory(client, mapper, "")
+	pod1ID := ignoreErrInfoToObjMeta(pod1Info)
+	pod2ID := ignoreErrInfoToObjMeta(pod2Info)
+	pod1Status := actuation.ObjectStatus{
+		ObjectReference: ObjectReferenceFromObjMetadata(pod1ID),
+		Strategy:        actuation.ActuationStrategyApply,
+		Actuation:       actuation.ActuationSucceeded,
+		Reconcile:       actuation.ReconcileSucceeded,
+		AppliedHash:     "hash",
+		ResourceVersion: "42",
+	}
+
+	// Stored with StatusPolicyAll, omitting the objects without status.
+	as := storageFunc(applySetParent.DeepCopy())
+	require.NoError(t, as.Store(object.ObjMetadataSet{pod1ID, pod2ID}, []actuation.ObjectStatus{pod1Status}))
+	require.NoError(t, as.Apply(client, mapper, StatusPolicyAll))
+	clusterObj, err := client.Resource(cmResource).Namespace(testNamespace).
+		Get(context.TODO(), "test-applyset", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, found, err := StatusSummaryFromObject(clusterObj)
+	require.NoError(t, err)
+	assert.True(t, found)
+	objStatus, err := storageFunc(clusterObj).(StatusLoader).LoadStatus()
+	require.NoError(t, err)
+	assert.Equal(t, []actuation.ObjectStatus{pod1Status}, objStatus)
+
+	// Removed with StatusPolicyNone
+	as = storageFunc(clusterObj)
+	require.NoError(t, as.Store(object.ObjMetadataSet{pod1ID, pod2ID}, []actuation.ObjectStatus{pod1Status}))
+	require.NoError(t, as.ApplyWithPrune(client, mapper, StatusPolicyNone, object.ObjMetadataSet{}))
+	clusterObj, err = client.Resource(cmResource).Namespace(testNamespace).
+		Get(context.TODO(), "test-applyset", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, clusterObj.GetAnnotations(), ApplySetStatusAnnotation)
+	objStatus, err = storageFunc(clusterObj).(StatusLoader).LoadStatus()
+	require.NoError(t, err)
+	assert.Empty(t, objStatus)
+}
+
+func TestApplySetLoadStatusInvalid(t *testing.T) {
+	parent := applySetParent.DeepCopy()
+	parent.SetAnnotations(map[string]string{ApplySetStatusAnnotation: "not json"})
+	_, err := WrapApplySetInfoObj(parent).(StatusLoader).LoadStatus()
+	assert.Error(t, err)
+}
+
+func TestAddInventoryIDAnnotationApplySet(t *testing.T) {
+	obj := applySetMember("Pod", testNamespace, "pod-1", "")
+	inv := WrapApplySetInfoObj(applySetParent)
+	AddInventoryIDAnnotation(obj, inv)
+	assert.Equal(t, testApplySetID, obj.GetAnnotations()[OwningInventoryKey])
+	assert.Equal(t, testApplySetID, obj.GetLabels()[ApplySetPartOfLabel])
+	assert.Equal(t, Match, IDMatch(inv, obj))
+
+	// Non-ApplySet inventories do not label the object
+	obj = applySetMember("Pod", testNamespace, "pod-1", "")
+	AddInventoryIDAnnotation(obj, localInv)
+	assert.Empty(t, obj.GetLabels())
+}
+
+func TestWrapInfoObjForClientFactory(t *testing.T) {
+	inv := WrapInfoObjForClientFactory(ApplySetClientFactory{}, applySetParent)
+	assert.Equal(t, applySetParent, InvInfoToApplySet(inv))
+	assert.Equal(t, testApplySetID, inv.ID())
+
+	inv = WrapInfoObjForClientFactory(ClusterClientFactory{}, applySetParent)
+	assert.Equal(t, applySetParent, InvInfoToConfigMap(inv))
+}
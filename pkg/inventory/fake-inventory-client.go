@@ -22,6 +22,7 @@ type FakeClient struct {
 
 var (
 	_ Client        = &FakeClient{}
+	_ StatusClient  = &FakeClient{}
 	_ ClientFactory = FakeClientFactory{}
 )
 
@@ -47,6 +48,14 @@ func (fic *FakeClient) GetClusterObjs(Info) (object.ObjMetadataSet, error) {
 	return fic.Objs, nil
 }
 
+// GetClusterObjStatus returns currently stored object status.
+func (fic *FakeClient) GetClusterObjStatus(Info) ([]actuation.ObjectStatus, error) {
+	if fic.Err != nil {
+		return nil, fic.Err
+	}
+	return fic.Status, nil
+}
+
 // Merge stores the passed objects with the current stored cluster inventory
 // objects. Returns the set difference of the current set of objects minus
 // the passed set of objects, or an error if one is set up.
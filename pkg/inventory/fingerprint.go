@@ -0,0 +1,52 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// serverSetMetadataFields are the metadata fields populated by the apiserver,
+// which are excluded when computing the applied hash.
+var serverSetMetadataFields = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// AppliedHash returns a fingerprint of the passed object configuration.
+// The status, server-populated metadata and the last-applied-configuration
+// annotation are excluded, so the hash of a local object matches the hash of
+// the same configuration after it has been applied. Returns an error if the
+// object could not be serialized.
+func AppliedHash(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", fmt.Errorf("attempting to hash a nil object")
+	}
+	u := obj.DeepCopy()
+	unstructured.RemoveNestedField(u.Object, "status")
+	for _, field := range serverSetMetadataFields {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(u.Object, "metadata", "annotations", v1.LastAppliedConfigAnnotation)
+	if len(u.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(u.Object, "metadata", "annotations")
+	}
+	// Map keys are sorted by the json encoder, so the output is stable.
+	data, err := json.Marshal(u.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize object for hashing: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
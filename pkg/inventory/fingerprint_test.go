@@ -0,0 +1,55 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var deploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+  namespace: default
+spec:
+  replicas: 1
+`
+
+func TestAppliedHash(t *testing.T) {
+	local := testutil.Unstructured(t, deploymentManifest)
+	localHash, err := AppliedHash(local)
+	require.NoError(t, err)
+	assert.Len(t, localHash, 64)
+
+	// Server-populated fields do not change the hash
+	applied := local.DeepCopy()
+	applied.SetUID("some-uid")
+	applied.SetResourceVersion("42")
+	applied.SetGeneration(3)
+	applied.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+	})
+	require.NoError(t, unstructured.SetNestedField(applied.Object, int64(1), "status", "readyReplicas"))
+	appliedHash, err := AppliedHash(applied)
+	require.NoError(t, err)
+	assert.Equal(t, localHash, appliedHash)
+	// Input object is not modified
+	assert.Equal(t, "42", applied.GetResourceVersion())
+
+	// Configuration changes do change the hash
+	changed := local.DeepCopy()
+	require.NoError(t, unstructured.SetNestedField(changed.Object, int64(2), "spec", "replicas"))
+	changedHash, err := AppliedHash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, localHash, changedHash)
+
+	_, err = AppliedHash(nil)
+	assert.Error(t, err)
+}
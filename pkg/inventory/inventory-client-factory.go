@@ -4,6 +4,7 @@
 package inventory
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
@@ -16,6 +17,22 @@ type ClientFactory interface {
 	NewClient(factory cmdutil.Factory) (Client, error)
 }
 
+// InfoWrapper is implemented by the ClientFactories whose clients require a
+// specific Info wrapper of the inventory object, like the ApplySet.
+type InfoWrapper interface {
+	WrapInfo(obj *unstructured.Unstructured) Info
+}
+
+// WrapInfoObjForClientFactory wraps the passed inventory object with the
+// Info of the clients of the passed factory. Defaults to the ConfigMap
+// wrapper.
+func WrapInfoObjForClientFactory(factory ClientFactory, obj *unstructured.Unstructured) Info {
+	if wrapper, ok := factory.(InfoWrapper); ok {
+		return wrapper.WrapInfo(obj)
+	}
+	return WrapInventoryInfoObj(obj)
+}
+
 // ClusterClientFactory is a factory that creates instances of ClusterClient inventory client.
 type ClusterClientFactory struct {
 	StatusPolicy StatusPolicy
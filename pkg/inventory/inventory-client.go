@@ -49,6 +49,26 @@ type Client interface {
 	ListClusterInventoryObjs(ctx context.Context) (map[string]object.ObjMetadataSet, error)
 }
 
+// StatusClient is an optional interface for Client implementations that
+// can retrieve the object status stored in the cluster inventory object.
+type StatusClient interface {
+	// GetClusterObjStatus returns the object status stored in the cluster
+	// inventory object, or an error if one occurred. Returns an empty list
+	// if the inventory does not store object status.
+	GetClusterObjStatus(inv Info) ([]actuation.ObjectStatus, error)
+}
+
+// GetClusterObjStatus returns the object status stored in the cluster
+// inventory object, if the client implements StatusClient. Returns an empty
+// list otherwise.
+func GetClusterObjStatus(client Client, inv Info) ([]actuation.ObjectStatus, error) {
+	statusClient, ok := client.(StatusClient)
+	if !ok {
+		return nil, nil
+	}
+	return statusClient.GetClusterObjStatus(inv)
+}
+
 // ClusterClient is a concrete implementation of the
 // Client interface.
 type ClusterClient struct {
@@ -62,6 +82,7 @@ type ClusterClient struct {
 }
 
 var _ Client = &ClusterClient{}
+var _ StatusClient = &ClusterClient{}
 
 // NewClient returns a concrete implementation of the
 // Client interface or an error.
@@ -268,6 +289,25 @@ func (cic *ClusterClient) GetClusterObjs(localInv Info) (object.ObjMetadataSet,
 	return wrapped.Load()
 }
 
+// GetClusterObjStatus returns the object status stored in the cluster
+// inventory object, or an error if one occurred. Returns an empty list if
+// the inventory object does not exist or its storage does not support
+// loading status.
+func (cic *ClusterClient) GetClusterObjStatus(localInv Info) ([]actuation.ObjectStatus, error) {
+	clusterInv, err := cic.GetClusterInventoryInfo(localInv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory from cluster: %w", err)
+	}
+	if clusterInv == nil {
+		return nil, nil
+	}
+	loader, ok := cic.InventoryFactoryFunc(clusterInv).(StatusLoader)
+	if !ok {
+		return nil, nil
+	}
+	return loader.LoadStatus()
+}
+
 // getClusterInventoryObj returns a pointer to the cluster inventory object, or
 // an error if one occurred. Returns the cached cluster inventory object if it
 // has been previously retrieved. Uses the ResourceBuilder to retrieve the
@@ -595,3 +595,21 @@ func storeObjsInInventory(info Info, objs object.ObjMetadataSet, status []actuat
 	inv, _ := wrapped.GetObject()
 	return inv
 }
+
+func TestGetClusterObjStatus(t *testing.T) {
+	status := []actuation.ObjectStatus{
+		{ObjectReference: ObjectReferenceFromObjMetadata(ignoreErrInfoToObjMeta(pod1Info))},
+	}
+	fakeClient := NewFakeClient(object.ObjMetadataSet{})
+	fakeClient.Status = status
+	inv := WrapInventoryInfoObj(inventoryObj)
+
+	result, err := GetClusterObjStatus(fakeClient, inv)
+	require.NoError(t, err)
+	assert.Equal(t, status, result)
+
+	// Clients which do not implement StatusClient store no status.
+	result, err = GetClusterObjStatus(struct{ Client }{fakeClient}, inv)
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
@@ -64,6 +64,7 @@ type ConfigMap struct {
 
 var _ Info = &ConfigMap{}
 var _ Storage = &ConfigMap{}
+var _ StatusLoader = &ConfigMap{}
 
 func (icm *ConfigMap) Name() string {
 	return icm.inv.GetName()
@@ -107,6 +108,35 @@ func (icm *ConfigMap) Load() (object.ObjMetadataSet, error) {
 	return objs, nil
 }
 
+// LoadStatus returns the object status stored in the wrapped ConfigMap,
+// or an error. Objects stored without status are omitted.
+func (icm *ConfigMap) LoadStatus() ([]actuation.ObjectStatus, error) {
+	var objStatus []actuation.ObjectStatus
+	objMap, exists, err := unstructured.NestedStringMap(icm.inv.Object, "data")
+	if err != nil {
+		err := fmt.Errorf("error retrieving object status from inventory object")
+		return objStatus, err
+	}
+	if !exists {
+		return objStatus, nil
+	}
+	for objStr, statusStr := range objMap {
+		if statusStr == "" {
+			continue
+		}
+		id, err := object.ParseObjMetadata(objStr)
+		if err != nil {
+			return objStatus, err
+		}
+		status, err := statusFrom(id, statusStr)
+		if err != nil {
+			return objStatus, err
+		}
+		objStatus = append(objStatus, status)
+	}
+	return objStatus, nil
+}
+
 // Store is an Inventory interface function implemented to store
 // the object metadata in the wrapped ConfigMap. Actual storing
 // happens in "GetObject".
@@ -119,8 +149,14 @@ func (icm *ConfigMap) Store(objMetas object.ObjMetadataSet, status []actuation.O
 // GetObject returns the wrapped object (ConfigMap) as a resource.Info
 // or an error if one occurs.
 func (icm *ConfigMap) GetObject() (*unstructured.Unstructured, error) {
+	return icm.buildObject(icm.objStatus)
+}
+
+// buildObject returns a copy of the wrapped ConfigMap storing the object
+// metadata and the passed object status.
+func (icm *ConfigMap) buildObject(objStatus []actuation.ObjectStatus) (*unstructured.Unstructured, error) {
 	// Create the objMap of all the resources, and compute the hash.
-	objMap := buildObjMap(icm.objMetas, icm.objStatus)
+	objMap := buildObjMap(icm.objMetas, objStatus)
 	// Create the inventory object by copying the template.
 	invCopy := icm.inv.DeepCopy()
 	// Adds the inventory map to the ConfigMap "data" section.
@@ -132,10 +168,38 @@ func (icm *ConfigMap) GetObject() (*unstructured.Unstructured, error) {
 	return invCopy, nil
 }
 
+// getObjectWithStatusPolicy returns the object to apply for the passed
+// StatusPolicy. With StatusPolicyNone, object status is not stored. With
+// StatusPolicyAll, object status and a StatusSummary are stored.
+func (icm *ConfigMap) getObjectWithStatusPolicy(statusPolicy StatusPolicy) (*unstructured.Unstructured, error) {
+	if statusPolicy != StatusPolicyAll {
+		invCopy, err := icm.buildObject(nil)
+		if err != nil {
+			return nil, err
+		}
+		removeStatusSummary(invCopy)
+		return invCopy, nil
+	}
+	invCopy, err := icm.buildObject(icm.objStatus)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]actuation.ObjectReference, 0, len(icm.objMetas))
+	for _, id := range icm.objMetas {
+		refs = append(refs, ObjectReferenceFromObjMetadata(id))
+	}
+	if err := setStatusSummary(invCopy, NewStatusSummary(refs, icm.objStatus)); err != nil {
+		return nil, err
+	}
+	return invCopy, nil
+}
+
 // Apply is an Storage interface function implemented to apply the inventory
-// object. StatusPolicy is not needed since ConfigMaps do not have a status subresource.
-func (icm *ConfigMap) Apply(dc dynamic.Interface, mapper meta.RESTMapper, _ StatusPolicy) error {
-	invInfo, namespacedClient, err := icm.getNamespacedClient(dc, mapper)
+// object. ConfigMaps do not have a status subresource, so with StatusPolicyAll
+// the object status is stored in the data and summarized in an annotation.
+// With StatusPolicyNone, no object status is stored.
+func (icm *ConfigMap) Apply(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy StatusPolicy) error {
+	invInfo, namespacedClient, err := icm.getNamespacedClient(dc, mapper, statusPolicy)
 	if err != nil {
 		return err
 	}
@@ -160,9 +224,9 @@ func (icm *ConfigMap) Apply(dc dynamic.Interface, mapper meta.RESTMapper, _ Stat
 }
 
 // ApplyWithPrune is a Storage interface function implemented to apply the inventory object with a list of objects
-// to be pruned. StatusPolicy is not needed since ConfigMaps do not have a status subresource.
-func (icm *ConfigMap) ApplyWithPrune(dc dynamic.Interface, mapper meta.RESTMapper, _ StatusPolicy, _ object.ObjMetadataSet) error {
-	invInfo, namespacedClient, err := icm.getNamespacedClient(dc, mapper)
+// to be pruned. The StatusPolicy is handled the same as in Apply.
+func (icm *ConfigMap) ApplyWithPrune(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy StatusPolicy, _ object.ObjMetadataSet) error {
+	invInfo, namespacedClient, err := icm.getNamespacedClient(dc, mapper, statusPolicy)
 	if err != nil {
 		return err
 	}
@@ -175,8 +239,9 @@ func (icm *ConfigMap) ApplyWithPrune(dc dynamic.Interface, mapper meta.RESTMappe
 
 // getNamespacedClient is a helper function for Apply and ApplyWithPrune that creates a namespaced client for interacting with the live
 // cluster, as well as returning the ConfigMap object as a wrapped resource.Info object.
-func (icm *ConfigMap) getNamespacedClient(dc dynamic.Interface, mapper meta.RESTMapper) (*unstructured.Unstructured, dynamic.ResourceInterface, error) {
-	invInfo, err := icm.GetObject()
+func (icm *ConfigMap) getNamespacedClient(dc dynamic.Interface, mapper meta.RESTMapper,
+	statusPolicy StatusPolicy) (*unstructured.Unstructured, dynamic.ResourceInterface, error) {
+	invInfo, err := icm.getObjectWithStatusPolicy(statusPolicy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -218,9 +283,64 @@ func stringFrom(status actuation.ObjectStatus) string {
 		"actuation": status.Actuation.String(),
 		"reconcile": status.Reconcile.String(),
 	}
+	if status.AppliedHash != "" {
+		tmp["appliedHash"] = status.AppliedHash
+	}
+	if status.ResourceVersion != "" {
+		tmp["resourceVersion"] = status.ResourceVersion
+	}
+	if status.Replaced {
+		tmp["replaced"] = "true"
+	}
 	data, err := json.Marshal(tmp)
 	if err != nil || string(data) == "{}" {
 		return ""
 	}
 	return string(data)
 }
+
+var (
+	strategyFromString = map[string]actuation.ActuationStrategy{
+		actuation.ActuationStrategyApply.String():  actuation.ActuationStrategyApply,
+		actuation.ActuationStrategyDelete.String(): actuation.ActuationStrategyDelete,
+	}
+	actuationFromString = map[string]actuation.ActuationStatus{
+		actuation.ActuationPending.String():   actuation.ActuationPending,
+		actuation.ActuationSucceeded.String(): actuation.ActuationSucceeded,
+		actuation.ActuationSkipped.String():   actuation.ActuationSkipped,
+		actuation.ActuationFailed.String():    actuation.ActuationFailed,
+	}
+	reconcileFromString = map[string]actuation.ReconcileStatus{
+		actuation.ReconcilePending.String():   actuation.ReconcilePending,
+		actuation.ReconcileSucceeded.String(): actuation.ReconcileSucceeded,
+		actuation.ReconcileSkipped.String():   actuation.ReconcileSkipped,
+		actuation.ReconcileFailed.String():    actuation.ReconcileFailed,
+		actuation.ReconcileTimeout.String():   actuation.ReconcileTimeout,
+	}
+)
+
+// statusFrom is the inverse of stringFrom. It parses the status string
+// stored for an object in the ConfigMap data.
+func statusFrom(id object.ObjMetadata, data string) (actuation.ObjectStatus, error) {
+	status := actuation.ObjectStatus{
+		ObjectReference: ObjectReferenceFromObjMetadata(id),
+	}
+	tmp := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &tmp); err != nil {
+		return status, fmt.Errorf("failed to parse inventory status for %q: %w", id, err)
+	}
+	var found bool
+	if status.Strategy, found = strategyFromString[tmp["strategy"]]; !found {
+		return status, fmt.Errorf("invalid inventory status for %q: unknown strategy %q", id, tmp["strategy"])
+	}
+	if status.Actuation, found = actuationFromString[tmp["actuation"]]; !found {
+		return status, fmt.Errorf("invalid inventory status for %q: unknown actuation %q", id, tmp["actuation"])
+	}
+	if status.Reconcile, found = reconcileFromString[tmp["reconcile"]]; !found {
+		return status, fmt.Errorf("invalid inventory status for %q: unknown reconcile %q", id, tmp["reconcile"])
+	}
+	status.AppliedHash = tmp["appliedHash"]
+	status.ResourceVersion = tmp["resourceVersion"]
+	status.Replaced = tmp["replaced"] == "true"
+	return status, nil
+}
@@ -4,9 +4,12 @@
 package inventory
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
@@ -52,6 +55,37 @@ func TestBuildObjMap(t *testing.T) {
 				"ns_na_group2_Kind": `{"actuation":"Skipped","reconcile":"Succeeded","strategy":"Delete"}`,
 			},
 		},
+		"object status with applied fingerprint": {
+			objSet: object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1)},
+			objStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: obj1,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+					AppliedHash:     "abc123",
+					ResourceVersion: "42",
+				},
+			},
+			expected: map[string]string{
+				"ns_na_group1_Kind": `{"actuation":"Succeeded","appliedHash":"abc123","reconcile":"Succeeded","resourceVersion":"42","strategy":"Apply"}`,
+			},
+		},
+		"replaced object status": {
+			objSet: object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1)},
+			objStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: obj1,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcilePending,
+					Replaced:        true,
+				},
+			},
+			expected: map[string]string{
+				"ns_na_group1_Kind": `{"actuation":"Succeeded","reconcile":"Pending","replaced":"true","strategy":"Apply"}`,
+			},
+		},
 		"empty object status list": {
 			objSet:   object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1), ObjMetadataFromObjectReference(obj2)},
 			hasError: false,
@@ -70,3 +104,94 @@ func TestBuildObjMap(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadStatus(t *testing.T) {
+	obj1 := actuation.ObjectReference{
+		Group:     "group1",
+		Kind:      "Kind",
+		Namespace: "ns",
+		Name:      "na",
+	}
+	obj2 := actuation.ObjectReference{
+		Group:     "group2",
+		Kind:      "Kind",
+		Namespace: "ns",
+		Name:      "na",
+	}
+
+	tests := map[string]struct {
+		objSet    object.ObjMetadataSet
+		objStatus []actuation.ObjectStatus
+		expected  []actuation.ObjectStatus
+	}{
+		"status round-trips through the ConfigMap": {
+			objSet: object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1), ObjMetadataFromObjectReference(obj2)},
+			objStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: obj1,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+					AppliedHash:     "abc123",
+					ResourceVersion: "42",
+					Replaced:        true,
+				},
+				{
+					ObjectReference: obj2,
+					Strategy:        actuation.ActuationStrategyDelete,
+					Actuation:       actuation.ActuationFailed,
+					Reconcile:       actuation.ReconcileSkipped,
+				},
+			},
+			expected: []actuation.ObjectStatus{
+				{
+					ObjectReference: obj1,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+					AppliedHash:     "abc123",
+					ResourceVersion: "42",
+					Replaced:        true,
+				},
+				{
+					ObjectReference: obj2,
+					Strategy:        actuation.ActuationStrategyDelete,
+					Actuation:       actuation.ActuationFailed,
+					Reconcile:       actuation.ReconcileSkipped,
+				},
+			},
+		},
+		"objects without status are omitted": {
+			objSet: object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1), ObjMetadataFromObjectReference(obj2)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := map[string]interface{}{}
+			for k, v := range buildObjMap(tc.objSet, tc.objStatus) {
+				data[k] = v
+			}
+			inv := &unstructured.Unstructured{Object: map[string]interface{}{
+				"data": data,
+			}}
+			actual, err := WrapInventoryObj(inv).(*ConfigMap).LoadStatus()
+			require.NoError(t, err)
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Group < actual[j].Group
+			})
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestLoadStatusInvalid(t *testing.T) {
+	inv := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{
+			"ns_na_group1_Kind": `{"actuation":"Unknown","reconcile":"Pending","strategy":"Apply"}`,
+		},
+	}}
+	_, err := WrapInventoryObj(inv).(*ConfigMap).LoadStatus()
+	require.EqualError(t, err, `invalid inventory status for "ns_na_group1_Kind": unknown actuation "Unknown"`)
+}
@@ -100,6 +100,33 @@ func (tc *Manager) AddSuccessfulApply(id object.ObjMetadata, uid types.UID, gen
 	})
 }
 
+// AddSuccessfulReplace updates the context with information about the
+// resource identified by the provided id, which was deleted and re-created
+// during apply.
+func (tc *Manager) AddSuccessfulReplace(id object.ObjMetadata, uid types.UID, gen int64) {
+	tc.SetObjectStatus(actuation.ObjectStatus{
+		ObjectReference: ObjectReferenceFromObjMetadata(id),
+		Strategy:        actuation.ActuationStrategyApply,
+		Actuation:       actuation.ActuationSucceeded,
+		Reconcile:       actuation.ReconcilePending,
+		UID:             uid,
+		Generation:      gen,
+		Replaced:        true,
+	})
+}
+
+// SetAppliedFingerprint registers the hash of the applied configuration and
+// the resource version returned by the server after apply.
+func (tc *Manager) SetAppliedFingerprint(id object.ObjMetadata, hash, resourceVersion string) error {
+	objStatus, found := tc.ObjectStatus(id)
+	if !found {
+		return fmt.Errorf("object not in inventory: %q", id)
+	}
+	objStatus.AppliedHash = hash
+	objStatus.ResourceVersion = resourceVersion
+	return nil
+}
+
 // SuccessfulApplies returns all the objects (as ObjMetadata) that
 // were added as applied resources to the Manager.
 func (tc *Manager) SuccessfulApplies() object.ObjMetadataSet {
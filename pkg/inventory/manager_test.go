@@ -65,3 +65,28 @@ func TestObjectStatusGetSet(t *testing.T) {
 	}
 	require.Equal(t, &expStatus, outStatus)
 }
+
+func TestSetAppliedFingerprint(t *testing.T) {
+	manager := NewManager()
+
+	id := object.ObjMetadata{
+		GroupKind: schema.GroupKind{
+			Group: "group",
+			Kind:  "kind",
+		},
+		Name:      "name",
+		Namespace: "namespace",
+	}
+
+	// Test set before the object is registered
+	err := manager.SetAppliedFingerprint(id, "abc123", "42")
+	require.Error(t, err)
+
+	manager.AddSuccessfulApply(id, "uid", 1)
+	err = manager.SetAppliedFingerprint(id, "abc123", "42")
+	require.NoError(t, err)
+	outStatus, found := manager.ObjectStatus(id)
+	require.True(t, found)
+	require.Equal(t, "abc123", outStatus.AppliedHash)
+	require.Equal(t, "42", outStatus.ResourceVersion)
+}
@@ -83,9 +83,11 @@ const (
 	NoMatch
 )
 
+// IDMatch compares the ID of the passed inventory with the owning-inventory
+// annotation of the passed object. Objects without the annotation, which are
+// labelled as members of an ApplySet, are compared by the ApplySet ID.
 func IDMatch(inv Info, obj *unstructured.Unstructured) IDMatchStatus {
-	annotations := obj.GetAnnotations()
-	value, found := annotations[OwningInventoryKey]
+	value, found := OwningInventory(obj)
 	if !found {
 		return Empty
 	}
@@ -95,6 +97,17 @@ func IDMatch(inv Info, obj *unstructured.Unstructured) IDMatchStatus {
 	return NoMatch
 }
 
+// OwningInventory returns the ID of the inventory which owns the passed
+// object, from the owning-inventory annotation, or from the ApplySet part-of
+// label if the annotation is not set. Returns false if neither is set.
+func OwningInventory(obj *unstructured.Unstructured) (string, bool) {
+	if value, found := obj.GetAnnotations()[OwningInventoryKey]; found {
+		return value, true
+	}
+	value, found := obj.GetLabels()[ApplySetPartOfLabel]
+	return value, found
+}
+
 func CanApply(inv Info, obj *unstructured.Unstructured, policy Policy) (bool, error) {
 	matchStatus := IDMatch(inv, obj)
 	switch matchStatus {
@@ -148,4 +161,5 @@ func AddInventoryIDAnnotation(obj *unstructured.Unstructured, inv Info) {
 	}
 	annotations[OwningInventoryKey] = inv.ID()
 	obj.SetAnnotations(annotations)
+	addApplySetPartOfLabel(obj, inv)
 }
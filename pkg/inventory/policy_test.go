@@ -51,6 +51,14 @@ func testObjectWithAnnotation(key, val string) *unstructured.Unstructured {
 	return obj
 }
 
+func testObjectWithLabel(key, val string) *unstructured.Unstructured {
+	obj := testObjectWithAnnotation("", "")
+	obj.SetLabels(map[string]string{
+		key: val,
+	})
+	return obj
+}
+
 func TestInventoryIDMatch(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -76,6 +84,18 @@ func TestInventoryIDMatch(t *testing.T) {
 			inv:      &fakeInventoryInfo{id: "random-id"},
 			expected: NoMatch,
 		},
+		{
+			name:     "matched applyset member",
+			obj:      testObjectWithLabel(ApplySetPartOfLabel, "matched"),
+			inv:      &fakeInventoryInfo{id: "matched"},
+			expected: Match,
+		},
+		{
+			name:     "unmatched applyset member",
+			obj:      testObjectWithLabel(ApplySetPartOfLabel, "unmatched"),
+			inv:      &fakeInventoryInfo{id: "random-id"},
+			expected: NoMatch,
+		},
 	}
 	for _, tc := range testcases {
 		actual := IDMatch(tc.inv, tc.obj)
@@ -0,0 +1,131 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const (
+	// AllowedNamespacesAnnotation is the annotation on the inventory object
+	// storing the comma-separated namespaces the objects of the package may
+	// be in. If not set, any namespace is allowed.
+	AllowedNamespacesAnnotation = "config.kubernetes.io/allowed-namespaces"
+	// AllowedClusterKindsAnnotation is the annotation on the inventory object
+	// storing the comma-separated kinds, in the format Kind.group, of the
+	// cluster-scoped objects of the package. If not set, any kind is allowed.
+	AllowedClusterKindsAnnotation = "config.kubernetes.io/allowed-cluster-kinds"
+)
+
+var namespaceGK = schema.GroupKind{Group: "", Kind: "Namespace"}
+
+// Scope restricts the namespaces and the kinds of the cluster-scoped objects
+// a package may apply or prune. The namespace of a Namespace object is its
+// name, so Namespace objects are allowed by the namespaces, not the kinds.
+type Scope struct {
+	// Namespaces, if not nil, are the only namespaces of the namespaced
+	// objects.
+	Namespaces []string
+	// ClusterGroupKinds, if not nil, are the only GroupKinds of the
+	// cluster-scoped objects.
+	ClusterGroupKinds []schema.GroupKind
+}
+
+// IsEmpty returns true if the scope does not restrict any object.
+func (s Scope) IsEmpty() bool {
+	return s.Namespaces == nil && s.ClusterGroupKinds == nil
+}
+
+// Check returns an OutOfScopeError if the object is outside the scope.
+func (s Scope) Check(id object.ObjMetadata) error {
+	namespace := id.Namespace
+	if id.GroupKind == namespaceGK {
+		namespace = id.Name
+	}
+	if namespace != "" {
+		if s.Namespaces != nil && !slices.Contains(s.Namespaces, namespace) {
+			return &OutOfScopeError{
+				Reason: fmt.Sprintf("namespace %q not in allowed namespaces", namespace),
+			}
+		}
+		return nil
+	}
+	if s.ClusterGroupKinds != nil && !containsGroupKind(s.ClusterGroupKinds, id.GroupKind) {
+		return &OutOfScopeError{
+			Reason: fmt.Sprintf("cluster-scoped kind %q not in allowed kinds", id.GroupKind),
+		}
+	}
+	return nil
+}
+
+// OutOfScopeError is returned if an object is outside the Scope of the
+// inventory.
+type OutOfScopeError struct {
+	Reason string
+}
+
+func (e *OutOfScopeError) Error() string {
+	return fmt.Sprintf("object is outside the scope of the inventory: %s", e.Reason)
+}
+
+func (e *OutOfScopeError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*OutOfScopeError)
+	if !ok {
+		return false
+	}
+	return e.Reason == tErr.Reason
+}
+
+// ReadScope returns the Scope declared by the annotations of the inventory
+// object. An annotation with an empty value allows nothing.
+func ReadScope(inv *unstructured.Unstructured) (Scope, error) {
+	scope := Scope{}
+	if inv == nil {
+		return scope, nil
+	}
+	annotations := inv.GetAnnotations()
+	if value, found := annotations[AllowedNamespacesAnnotation]; found {
+		scope.Namespaces = append([]string{}, splitList(value)...)
+	}
+	if value, found := annotations[AllowedClusterKindsAnnotation]; found {
+		scope.ClusterGroupKinds = []schema.GroupKind{}
+		for _, kind := range splitList(value) {
+			gk := schema.ParseGroupKind(kind)
+			if gk.Kind == "" {
+				return Scope{}, object.InvalidAnnotationError{
+					Annotation: AllowedClusterKindsAnnotation,
+					Cause:      fmt.Errorf("invalid kind %q: must be in the format Kind.group", kind),
+				}
+			}
+			scope.ClusterGroupKinds = append(scope.ClusterGroupKinds, gk)
+		}
+	}
+	return scope, nil
+}
+
+// ReadScopeFromInfo returns the Scope declared by the inventory object of the
+// passed Info, if it is a ConfigMap or an ApplySet.
+func ReadScopeFromInfo(inv Info) (Scope, error) {
+	if obj := InvInfoToConfigMap(inv); obj != nil {
+		return ReadScope(obj)
+	}
+	return ReadScope(InvInfoToApplySet(inv))
+}
+
+func containsGroupKind(gks []schema.GroupKind, gk schema.GroupKind) bool {
+	for _, g := range gks {
+		if g == gk {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestReadScope(t *testing.T) {
+	testCases := map[string]struct {
+		annotations   map[string]string
+		expected      Scope
+		expectedError string
+	}{
+		"no annotations": {
+			expected: Scope{},
+		},
+		"allowed namespaces": {
+			annotations: map[string]string{
+				AllowedNamespacesAnnotation: "team-b, team-a",
+			},
+			expected: Scope{
+				Namespaces: []string{"team-a", "team-b"},
+			},
+		},
+		"allowed cluster kinds": {
+			annotations: map[string]string{
+				AllowedClusterKindsAnnotation: "ClusterRole.rbac.authorization.k8s.io,PersistentVolume",
+			},
+			expected: Scope{
+				ClusterGroupKinds: []schema.GroupKind{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+					{Group: "", Kind: "PersistentVolume"},
+				},
+			},
+		},
+		"empty annotations allow nothing": {
+			annotations: map[string]string{
+				AllowedNamespacesAnnotation:   "",
+				AllowedClusterKindsAnnotation: "",
+			},
+			expected: Scope{
+				Namespaces:        []string{},
+				ClusterGroupKinds: []schema.GroupKind{},
+			},
+		},
+		"invalid cluster kind": {
+			annotations: map[string]string{
+				AllowedClusterKindsAnnotation: ".rbac.authorization.k8s.io",
+			},
+			expectedError: `invalid kind ".rbac.authorization.k8s.io"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			inv := copyInventoryInfo()
+			inv.SetAnnotations(tc.annotations)
+
+			scope, err := ReadScope(inv)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			testutil.AssertEqual(t, tc.expected, scope)
+		})
+	}
+}
+
+func TestScope_Check(t *testing.T) {
+	configMapID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+		Name:      "config",
+		Namespace: "team-a",
+	}
+	namespaceID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Namespace"},
+		Name:      "team-a",
+	}
+	clusterRoleID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+		Name:      "role",
+	}
+
+	testCases := map[string]struct {
+		scope         Scope
+		id            object.ObjMetadata
+		expectedError error
+	}{
+		"empty scope allows namespaced objects": {
+			id: configMapID,
+		},
+		"empty scope allows cluster-scoped objects": {
+			id: clusterRoleID,
+		},
+		"namespace in allowed namespaces": {
+			scope: Scope{Namespaces: []string{"team-a"}},
+			id:    configMapID,
+		},
+		"namespace not in allowed namespaces": {
+			scope: Scope{Namespaces: []string{"team-b"}},
+			id:    configMapID,
+			expectedError: &OutOfScopeError{
+				Reason: `namespace "team-a" not in allowed namespaces`,
+			},
+		},
+		"namespace object is checked by name": {
+			scope: Scope{
+				Namespaces:        []string{"team-a"},
+				ClusterGroupKinds: []schema.GroupKind{},
+			},
+			id: namespaceID,
+		},
+		"kind in allowed cluster kinds": {
+			scope: Scope{ClusterGroupKinds: []schema.GroupKind{clusterRoleID.GroupKind}},
+			id:    clusterRoleID,
+		},
+		"kind not in allowed cluster kinds": {
+			scope: Scope{
+				Namespaces:        []string{"team-a"},
+				ClusterGroupKinds: []schema.GroupKind{},
+			},
+			id: clusterRoleID,
+			expectedError: &OutOfScopeError{
+				Reason: `cluster-scoped kind "ClusterRole.rbac.authorization.k8s.io" not in allowed kinds`,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			err := tc.scope.Check(tc.id)
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+)
+
+// StatusSummaryAnnotation is the annotation on the inventory object which
+// stores the StatusSummary, when the inventory is applied with
+// StatusPolicyAll.
+const StatusSummaryAnnotation = "cli-utils.sigs.k8s.io/inventory-status"
+
+// now is the clock used to set StatusSummary.LastRunTime.
+// Replaced in tests.
+var now = time.Now
+
+// StatusSummary is an aggregate of the object status stored in the
+// inventory, so the health of a package can be read from the inventory
+// object alone.
+type StatusSummary struct {
+	// Objects is the total number of objects in the inventory.
+	Objects int `json:"objects"`
+	// Actuation is the number of objects for each actuation status.
+	Actuation map[string]int `json:"actuation,omitempty"`
+	// Reconcile is the number of objects for each reconcile status.
+	Reconcile map[string]int `json:"reconcile,omitempty"`
+	// LastRunTime is the time the inventory was last applied.
+	LastRunTime metav1.Time `json:"lastRunTime"`
+}
+
+// NewStatusSummary returns the StatusSummary of the passed inventory objects
+// and their status. Status of objects not in the inventory is ignored.
+func NewStatusSummary(objMetas []actuation.ObjectReference, objStatus []actuation.ObjectStatus) StatusSummary {
+	summary := StatusSummary{
+		Objects:     len(objMetas),
+		LastRunTime: metav1.NewTime(now().UTC().Truncate(time.Second)),
+	}
+	refs := make(map[actuation.ObjectReference]struct{}, len(objMetas))
+	for _, ref := range objMetas {
+		refs[ref] = struct{}{}
+	}
+	for _, status := range objStatus {
+		if _, found := refs[status.ObjectReference]; !found {
+			continue
+		}
+		if summary.Actuation == nil {
+			summary.Actuation = map[string]int{}
+			summary.Reconcile = map[string]int{}
+		}
+		summary.Actuation[status.Actuation.String()]++
+		summary.Reconcile[status.Reconcile.String()]++
+	}
+	return summary
+}
+
+// StatusSummaryFromObject returns the StatusSummary stored on the passed
+// inventory object. Returns false if the object does not have a summary,
+// or an error if the summary could not be parsed.
+func StatusSummaryFromObject(obj *unstructured.Unstructured) (StatusSummary, bool, error) {
+	var summary StatusSummary
+	data, found := obj.GetAnnotations()[StatusSummaryAnnotation]
+	if !found {
+		return summary, false, nil
+	}
+	if err := json.Unmarshal([]byte(data), &summary); err != nil {
+		return summary, true, fmt.Errorf("failed to parse inventory status summary: %w", err)
+	}
+	return summary, true, nil
+}
+
+// setStatusSummary stores the passed StatusSummary on the passed inventory
+// object, as an annotation.
+func setStatusSummary(obj *unstructured.Unstructured, summary StatusSummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to serialize inventory status summary: %w", err)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[StatusSummaryAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// removeStatusSummary removes the StatusSummary from the passed inventory
+// object, if present.
+func removeStatusSummary(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[StatusSummaryAnnotation]; !found {
+		return
+	}
+	delete(annotations, StatusSummaryAnnotation)
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+var testTime = time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func TestNewStatusSummary(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return testTime }
+
+	pod1Ref := ObjectReferenceFromObjMetadata(ignoreErrInfoToObjMeta(pod1Info))
+	pod2Ref := ObjectReferenceFromObjMetadata(ignoreErrInfoToObjMeta(pod2Info))
+	pod3Ref := ObjectReferenceFromObjMetadata(ignoreErrInfoToObjMeta(pod3Info))
+
+	tests := map[string]struct {
+		refs      []actuation.ObjectReference
+		objStatus []actuation.ObjectStatus
+		expected  StatusSummary
+	}{
+		"no status": {
+			refs: []actuation.ObjectReference{pod1Ref, pod2Ref},
+			expected: StatusSummary{
+				Objects:     2,
+				LastRunTime: metav1.NewTime(testTime),
+			},
+		},
+		"status counted per actuation and reconcile": {
+			refs: []actuation.ObjectReference{pod1Ref, pod2Ref},
+			objStatus: []actuation.ObjectStatus{
+				podStatus(pod1Info),
+				{
+					ObjectReference: pod2Ref,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationFailed,
+					Reconcile:       actuation.ReconcileSkipped,
+				},
+				// Not in the inventory
+				{
+					ObjectReference: pod3Ref,
+					Strategy:        actuation.ActuationStrategyDelete,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+				},
+			},
+			expected: StatusSummary{
+				Objects: 2,
+				Actuation: map[string]int{
+					"Succeeded": 1,
+					"Failed":    1,
+				},
+				Reconcile: map[string]int{
+					"Succeeded": 1,
+					"Skipped":   1,
+				},
+				LastRunTime: metav1.NewTime(testTime),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewStatusSummary(tc.refs, tc.objStatus))
+		})
+	}
+}
+
+func TestConfigMapApplyStatusPolicy(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return testTime }
+
+	objs := object.ObjMetadataSet{ignoreErrInfoToObjMeta(pod1Info)}
+	objStatus := []actuation.ObjectStatus{podStatus(pod1Info)}
+
+	tests := map[string]struct {
+		statusPolicy    StatusPolicy
+		existing        bool
+		expectedData    map[string]string
+		expectedSummary *StatusSummary
+	}{
+		"StatusPolicyNone create": {
+			statusPolicy: StatusPolicyNone,
+			expectedData: podDataNoStatus("pod-1"),
+		},
+		"StatusPolicyNone update": {
+			statusPolicy: StatusPolicyNone,
+			existing:     true,
+			expectedData: podDataNoStatus("pod-1"),
+		},
+		"StatusPolicyAll create": {
+			statusPolicy: StatusPolicyAll,
+			expectedData: podData("pod-1"),
+			expectedSummary: &StatusSummary{
+				Objects:     1,
+				Actuation:   map[string]int{"Succeeded": 1},
+				Reconcile:   map[string]int{"Succeeded": 1},
+				LastRunTime: metav1.NewTime(testTime),
+			},
+		},
+		"StatusPolicyAll update": {
+			statusPolicy: StatusPolicyAll,
+			existing:     true,
+			expectedData: podData("pod-1"),
+			expectedSummary: &StatusSummary{
+				Objects:     1,
+				Actuation:   map[string]int{"Succeeded": 1},
+				Reconcile:   map[string]int{"Succeeded": 1},
+				LastRunTime: metav1.NewTime(testTime),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClient(scheme.Scheme)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			cmResource := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+			invObj := inventoryObj.DeepCopy()
+			if tc.existing {
+				// The existing inventory has a stale summary
+				require.NoError(t, setStatusSummary(invObj, StatusSummary{Objects: 5}))
+				_, err := client.Resource(cmResource).Namespace(testNamespace).
+					Create(context.TODO(), invObj, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			wrappedInv := WrapInventoryObj(invObj)
+			require.NoError(t, wrappedInv.Store(objs, objStatus))
+			if tc.existing {
+				require.NoError(t, wrappedInv.ApplyWithPrune(client, mapper, tc.statusPolicy, nil))
+			} else {
+				require.NoError(t, wrappedInv.Apply(client, mapper, tc.statusPolicy))
+			}
+
+			clusterObj, err := client.Resource(cmResource).Namespace(testNamespace).
+				Get(context.TODO(), inventoryObjName, metav1.GetOptions{})
+			require.NoError(t, err)
+			data, _, err := unstructured.NestedStringMap(clusterObj.Object, "data")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedData, data)
+
+			summary, found, err := StatusSummaryFromObject(clusterObj)
+			require.NoError(t, err)
+			if tc.expectedSummary == nil {
+				assert.False(t, found)
+				return
+			}
+			assert.True(t, found)
+			// Parsed times use the local time zone
+			summary.LastRunTime = metav1.NewTime(summary.LastRunTime.UTC())
+			assert.Equal(t, *tc.expectedSummary, summary)
+		})
+	}
+}
+
+func TestStatusSummaryFromObjectInvalid(t *testing.T) {
+	obj := inventoryObj.DeepCopy()
+	obj.SetAnnotations(map[string]string{StatusSummaryAnnotation: "not-json"})
+	_, found, err := StatusSummaryFromObject(obj)
+	assert.True(t, found)
+	assert.Error(t, err)
+}
@@ -48,6 +48,13 @@ type Storage interface {
 	ApplyWithPrune(dynamic.Interface, meta.RESTMapper, StatusPolicy, object.ObjMetadataSet) error
 }
 
+// StatusLoader is an optional interface for Storage implementations that
+// can retrieve the object status previously stored in the inventory object.
+type StatusLoader interface {
+	// LoadStatus retrieves the set of object status from the inventory object
+	LoadStatus() ([]actuation.ObjectStatus, error)
+}
+
 // StorageFactoryFunc creates the object which implements the Inventory
 // interface from the passed info object.
 type StorageFactoryFunc func(*unstructured.Unstructured) Storage
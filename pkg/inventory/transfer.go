@@ -0,0 +1,210 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Transferer moves objects from one inventory to another, without deleting
+// the objects from the cluster.
+type Transferer struct {
+	InvClient Client
+	Client    dynamic.Interface
+	Mapper    meta.RESTMapper
+}
+
+// NewTransferer returns a new Transferer.
+// Returns an error if dependency injection fails using the factory.
+func NewTransferer(factory cmdutil.Factory, invClient Client) (*Transferer, error) {
+	client, err := factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	return &Transferer{
+		InvClient: invClient,
+		Client:    client,
+		Mapper:    mapper,
+	}, nil
+}
+
+// Transfer moves the passed objects from the inventory "from" to the
+// inventory "to". The owning-inventory annotation of the live objects is
+// rewritten first, then the objects are added to the "to" inventory, and
+// finally removed from the "from" inventory, together with their status.
+// This order makes sure that neither inventory prunes the objects if the
+// transfer is interrupted, in which case it can be run again. The "to"
+// inventory is created if it does not exist.
+// Returns an error, before making any change, if an object is in neither
+// inventory, or if a live object is owned by another inventory.
+func (t *Transferer) Transfer(ctx context.Context, from, to Info, ids object.ObjMetadataSet, dryRun common.DryRunStrategy) error {
+	if from.ID() == to.ID() {
+		return fmt.Errorf("cannot transfer objects to the same inventory: %q", from.ID())
+	}
+	fromObjs, err := t.InvClient.GetClusterObjs(from)
+	if err != nil {
+		return err
+	}
+	fromStatus, err := GetClusterObjStatus(t.InvClient, from)
+	if err != nil {
+		return err
+	}
+	toInv, err := t.InvClient.GetClusterInventoryInfo(to)
+	if err != nil {
+		return err
+	}
+	toObjs, err := t.InvClient.GetClusterObjs(to)
+	if err != nil {
+		return err
+	}
+	toStatus, err := GetClusterObjStatus(t.InvClient, to)
+	if err != nil {
+		return err
+	}
+
+	// Validate all the objects before making any change.
+	liveObjs := make(map[object.ObjMetadata]*unstructured.Unstructured, len(ids))
+	for _, id := range ids {
+		if !fromObjs.Contains(id) && !toObjs.Contains(id) {
+			return fmt.Errorf("object not in inventory %q: %s", from.ID(), id)
+		}
+		live, err := t.getObject(ctx, id)
+		if err != nil {
+			return err
+		}
+		if live == nil {
+			// Not in the cluster, so only the reference is moved.
+			continue
+		}
+		if IDMatch(from, live) == NoMatch && IDMatch(to, live) != Match {
+			return fmt.Errorf("object not owned by inventory %q: %s", from.ID(), id)
+		}
+		liveObjs[id] = live
+	}
+
+	// Rewrite the owning-inventory of the live objects.
+	for _, id := range ids {
+		live, found := liveObjs[id]
+		if !found || IDMatch(to, live) == Match {
+			continue
+		}
+		klog.V(4).Infof("transferring object to inventory %q: %s", to.ID(), id)
+		if dryRun.ClientOrServerDryRun() {
+			continue
+		}
+		if err := t.setOwningInventory(ctx, id, live, to); err != nil {
+			return err
+		}
+	}
+
+	// Add the objects to the "to" inventory.
+	newToObjs := toObjs.Union(ids)
+	newToStatus := filterStatus(toStatus, func(id object.ObjMetadata) bool {
+		return !ids.Contains(id)
+	})
+	newToStatus = append(newToStatus, filterStatus(fromStatus, ids.Contains)...)
+	if toInv == nil {
+		klog.V(4).Infof("creating inventory %q", to.ID())
+		if _, err := t.InvClient.Merge(to, newToObjs, dryRun); err != nil {
+			return fmt.Errorf("failed to create inventory %q: %w", to.ID(), err)
+		}
+	}
+	if err := t.InvClient.Replace(to, newToObjs, newToStatus, dryRun); err != nil {
+		return fmt.Errorf("failed to update inventory %q: %w", to.ID(), err)
+	}
+
+	// Remove the objects from the "from" inventory.
+	newFromStatus := filterStatus(fromStatus, func(id object.ObjMetadata) bool {
+		return !ids.Contains(id)
+	})
+	if err := t.InvClient.Replace(from, fromObjs.Diff(ids), newFromStatus, dryRun); err != nil {
+		return fmt.Errorf("failed to update inventory %q: %w", from.ID(), err)
+	}
+	return nil
+}
+
+// getObject returns the object from the cluster, or nil if it does not
+// exist.
+func (t *Transferer) getObject(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	mapping, err := t.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// The type is not served, so the object does not exist.
+			return nil, nil
+		}
+		return nil, err
+	}
+	live, err := t.Client.Resource(mapping.Resource).Namespace(id.Namespace).
+		Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get current object from cluster: %w", err)
+	}
+	return live, nil
+}
+
+// setOwningInventory patches the owning-inventory annotation of the live
+// object, and the ApplySet part-of label if used by either inventory.
+func (t *Transferer) setOwningInventory(ctx context.Context, id object.ObjMetadata, live *unstructured.Unstructured, to Info) error {
+	metadata := map[string]interface{}{
+		"annotations": map[string]interface{}{
+			OwningInventoryKey: to.ID(),
+		},
+	}
+	if _, ok := to.(*ApplySet); ok {
+		metadata["labels"] = map[string]interface{}{
+			ApplySetPartOfLabel: to.ID(),
+		}
+	} else if _, found := live.GetLabels()[ApplySetPartOfLabel]; found {
+		metadata["labels"] = map[string]interface{}{
+			ApplySetPartOfLabel: nil,
+		}
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	mapping, err := t.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return err
+	}
+	_, err = t.Client.Resource(mapping.Resource).Namespace(id.Namespace).
+		Patch(ctx, id.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update owning inventory of %s: %w", id, err)
+	}
+	return nil
+}
+
+// filterStatus returns the object status for which the passed function
+// returns true.
+func filterStatus(status []actuation.ObjectStatus, keep func(object.ObjMetadata) bool) []actuation.ObjectStatus {
+	var filtered []actuation.ObjectStatus
+	for _, s := range status {
+		if keep(ObjMetadataFromObjectReference(s.ObjectReference)) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,259 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// fakeInventories is a Client which stores multiple inventories, by ID.
+type fakeInventories struct {
+	*FakeClient
+	invs map[string]*FakeClient
+}
+
+func (f *fakeInventories) GetClusterObjs(inv Info) (object.ObjMetadataSet, error) {
+	if c, found := f.invs[inv.ID()]; found {
+		return c.Objs, nil
+	}
+	return object.ObjMetadataSet{}, nil
+}
+
+func (f *fakeInventories) GetClusterObjStatus(inv Info) ([]actuation.ObjectStatus, error) {
+	if c, found := f.invs[inv.ID()]; found {
+		return c.Status, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeInventories) GetClusterInventoryInfo(inv Info) (*unstructured.Unstructured, error) {
+	if _, found := f.invs[inv.ID()]; found {
+		return InvInfoToConfigMap(inv), nil
+	}
+	return nil, nil
+}
+
+func (f *fakeInventories) Merge(inv Info, objs object.ObjMetadataSet, _ common.DryRunStrategy) (object.ObjMetadataSet, error) {
+	if _, found := f.invs[inv.ID()]; !found {
+		f.invs[inv.ID()] = NewFakeClient(object.ObjMetadataSet{})
+	}
+	return f.invs[inv.ID()].Merge(inv, objs, common.DryRunNone)
+}
+
+func (f *fakeInventories) Replace(inv Info, objs object.ObjMetadataSet, status []actuation.ObjectStatus,
+	_ common.DryRunStrategy) error {
+	c, found := f.invs[inv.ID()]
+	if !found {
+		return fmt.Errorf("inventory not found: %q", inv.ID())
+	}
+	return c.Replace(inv, objs, status, common.DryRunNone)
+}
+
+func newInvInfo(id string) Info {
+	return WrapInventoryInfoObj(&unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "inventory-" + id,
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					common.InventoryLabel: id,
+				},
+			},
+		},
+	})
+}
+
+func newOwnedObj(kind, name, owner string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		},
+	}
+	if owner != "" {
+		obj.SetAnnotations(map[string]string{OwningInventoryKey: owner})
+	}
+	return obj
+}
+
+func objStatus(id object.ObjMetadata) actuation.ObjectStatus {
+	return actuation.ObjectStatus{
+		ObjectReference: ObjectReferenceFromObjMetadata(id),
+		Strategy:        actuation.ActuationStrategyApply,
+		Actuation:       actuation.ActuationSucceeded,
+		Reconcile:       actuation.ReconcileSucceeded,
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	cm1 := newOwnedObj("ConfigMap", "cm-1", "from-id")
+	cm1ID := object.UnstructuredToObjMetadata(cm1)
+	cm2 := newOwnedObj("ConfigMap", "cm-2", "from-id")
+	cm2ID := object.UnstructuredToObjMetadata(cm2)
+	svc := newOwnedObj("Service", "svc", "to-id")
+	svcID := object.UnstructuredToObjMetadata(svc)
+
+	tests := map[string]struct {
+		clusterObjs      []runtime.Object
+		fromObjs         object.ObjMetadataSet
+		toObjs           object.ObjMetadataSet
+		toMissing        bool
+		ids              object.ObjMetadataSet
+		expectedErr      string
+		expectedFromObjs object.ObjMetadataSet
+		expectedToObjs   object.ObjMetadataSet
+		expectedOwners   map[object.ObjMetadata]string
+	}{
+		"objects are moved": {
+			clusterObjs:      []runtime.Object{cm1.DeepCopy(), cm2.DeepCopy(), svc.DeepCopy()},
+			fromObjs:         object.ObjMetadataSet{cm1ID, cm2ID},
+			toObjs:           object.ObjMetadataSet{svcID},
+			ids:              object.ObjMetadataSet{cm1ID},
+			expectedFromObjs: object.ObjMetadataSet{cm2ID},
+			expectedToObjs:   object.ObjMetadataSet{svcID, cm1ID},
+			expectedOwners: map[object.ObjMetadata]string{
+				cm1ID: "to-id",
+				cm2ID: "from-id",
+			},
+		},
+		"to inventory is created": {
+			clusterObjs:      []runtime.Object{cm1.DeepCopy()},
+			fromObjs:         object.ObjMetadataSet{cm1ID},
+			toMissing:        true,
+			ids:              object.ObjMetadataSet{cm1ID},
+			expectedFromObjs: object.ObjMetadataSet{},
+			expectedToObjs:   object.ObjMetadataSet{cm1ID},
+			expectedOwners: map[object.ObjMetadata]string{
+				cm1ID: "to-id",
+			},
+		},
+		"interrupted transfer is completed": {
+			clusterObjs:      []runtime.Object{newOwnedObj("ConfigMap", "cm-1", "to-id")},
+			fromObjs:         object.ObjMetadataSet{cm1ID},
+			toObjs:           object.ObjMetadataSet{cm1ID},
+			ids:              object.ObjMetadataSet{cm1ID},
+			expectedFromObjs: object.ObjMetadataSet{},
+			expectedToObjs:   object.ObjMetadataSet{cm1ID},
+			expectedOwners: map[object.ObjMetadata]string{
+				cm1ID: "to-id",
+			},
+		},
+		"missing live object reference is moved": {
+			fromObjs:         object.ObjMetadataSet{cm1ID},
+			toObjs:           object.ObjMetadataSet{},
+			ids:              object.ObjMetadataSet{cm1ID},
+			expectedFromObjs: object.ObjMetadataSet{},
+			expectedToObjs:   object.ObjMetadataSet{cm1ID},
+		},
+		"object not in inventory": {
+			clusterObjs:      []runtime.Object{cm1.DeepCopy(), cm2.DeepCopy()},
+			fromObjs:         object.ObjMetadataSet{cm2ID},
+			toObjs:           object.ObjMetadataSet{},
+			ids:              object.ObjMetadataSet{cm2ID, cm1ID},
+			expectedErr:      `object not in inventory "from-id": default_cm-1__ConfigMap`,
+			expectedFromObjs: object.ObjMetadataSet{cm2ID},
+			expectedToObjs:   object.ObjMetadataSet{},
+			expectedOwners: map[object.ObjMetadata]string{
+				cm2ID: "from-id",
+			},
+		},
+		"object owned by another inventory": {
+			clusterObjs:      []runtime.Object{cm2.DeepCopy(), newOwnedObj("ConfigMap", "cm-1", "other-id")},
+			fromObjs:         object.ObjMetadataSet{cm1ID, cm2ID},
+			toObjs:           object.ObjMetadataSet{},
+			ids:              object.ObjMetadataSet{cm2ID, cm1ID},
+			expectedErr:      `object not owned by inventory "from-id": default_cm-1__ConfigMap`,
+			expectedFromObjs: object.ObjMetadataSet{cm1ID, cm2ID},
+			expectedToObjs:   object.ObjMetadataSet{},
+			expectedOwners: map[object.ObjMetadata]string{
+				cm1ID: "other-id",
+				cm2ID: "from-id",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			from := newInvInfo("from-id")
+			to := newInvInfo("to-id")
+			fromClient := NewFakeClient(tc.fromObjs)
+			for _, id := range tc.fromObjs {
+				fromClient.Status = append(fromClient.Status, objStatus(id))
+			}
+			invClient := &fakeInventories{
+				FakeClient: NewFakeClient(object.ObjMetadataSet{}),
+				invs: map[string]*FakeClient{
+					"from-id": fromClient,
+				},
+			}
+			if !tc.toMissing {
+				invClient.invs["to-id"] = NewFakeClient(tc.toObjs)
+			}
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, tc.clusterObjs...)
+			transferer := &Transferer{
+				InvClient: invClient,
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+
+			err := transferer.Transfer(context.TODO(), from, to, tc.ids, common.DryRunNone)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			fromObjs, err := invClient.GetClusterObjs(from)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedFromObjs, fromObjs)
+			toObjs, err := invClient.GetClusterObjs(to)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedToObjs, toObjs)
+			if tc.expectedErr == "" {
+				// Status is moved with the objects.
+				toStatus, err := invClient.GetClusterObjStatus(to)
+				require.NoError(t, err)
+				for _, id := range tc.ids {
+					assert.Contains(t, toStatus, objStatus(id))
+				}
+			}
+
+			for id, owner := range tc.expectedOwners {
+				live, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).
+					Namespace(id.Namespace).Get(context.TODO(), id.Name, metav1.GetOptions{})
+				require.NoError(t, err)
+				assert.Equal(t, owner, live.GetAnnotations()[OwningInventoryKey])
+			}
+		})
+	}
+}
+
+func TestTransferSameInventory(t *testing.T) {
+	inv := newInvInfo("inv-id")
+	transferer := &Transferer{InvClient: NewFakeClient(object.ObjMetadataSet{})}
+	err := transferer.Transfer(context.TODO(), inv, inv, object.ObjMetadataSet{}, common.DryRunNone)
+	assert.EqualError(t, err, `cannot transfer objects to the same inventory: "inv-id"`)
+}
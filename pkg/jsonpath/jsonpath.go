@@ -17,6 +17,13 @@ import (
 	"github.com/spyzhov/ajson"
 )
 
+// IsSensitive returns true if the input object has sensitive values, which
+// are not logged. The redact package, which imports this package, replaces
+// it on initialization.
+var IsSensitive = func(obj map[string]interface{}) bool {
+	return false
+}
+
 // Get evaluates the JSONPath expression to extract values from the input map.
 // Returns the node values that were found (zero or more), or an error.
 // For details about the JSONPath expression language, see:
@@ -28,7 +35,7 @@ func Get(obj map[string]interface{}, expression string) ([]interface{}, error) {
 		return nil, fmt.Errorf("failed to marshal input to json: %w", err)
 	}
 
-	klog.V(7).Info("jsonpath.Get input as json:\n%s", jsonBytes)
+	logJSON(obj, "jsonpath.Get input as json", jsonBytes)
 
 	// parse json into an ajson node
 	root, err := ajson.Unmarshal(jsonBytes)
@@ -52,7 +59,7 @@ func Get(obj map[string]interface{}, expression string) ([]interface{}, error) {
 			return nil, fmt.Errorf("failed to marshal jsonpath result to json: %w", err)
 		}
 
-		klog.V(7).Info("jsonpath.Get output as json:\n%s", jsonBytes)
+		logJSON(obj, "jsonpath.Get output as json", jsonBytes)
 
 		// parse json back into a Go primitive
 		var value interface{}
@@ -77,7 +84,7 @@ func Set(obj map[string]interface{}, expression string, value interface{}) (int,
 		return 0, fmt.Errorf("failed to marshal input to json: %w", err)
 	}
 
-	klog.V(7).Info("jsonpath.Set input as json:\n%s", jsonBytes)
+	logJSON(obj, "jsonpath.Set input as json", jsonBytes)
 
 	// parse json into an ajson node
 	root, err := ajson.Unmarshal(jsonBytes)
@@ -138,7 +145,7 @@ func Set(obj map[string]interface{}, expression string, value interface{}) (int,
 		return 0, fmt.Errorf("failed to marshal jsonpath result to json: %w", err)
 	}
 
-	klog.V(7).Info("jsonpath.Set output as json:\n%s", jsonBytes)
+	logJSON(obj, "jsonpath.Set output as json", jsonBytes)
 
 	// parse json back into the input map
 	err = yaml.Unmarshal(jsonBytes, &obj)
@@ -186,3 +193,15 @@ func toMapOfNodes(obj map[string]interface{}) (map[string]*ajson.Node, error) {
 	}
 	return out, nil
 }
+
+// logJSON logs the json at verbosity 7, unless the input object is sensitive.
+func logJSON(obj map[string]interface{}, msg string, jsonBytes []byte) {
+	if !klog.V(7).Enabled() {
+		return
+	}
+	if IsSensitive(obj) {
+		klog.V(7).Infof("%s: omitted, the input object is sensitive", msg)
+		return
+	}
+	klog.V(7).Infof("%s:\n%s", msg, jsonBytes)
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"filippo.io/age"
+)
+
+// AgeEncryptionType is the encryption type of values encrypted with age.
+const AgeEncryptionType = "age"
+
+// AgeDecryptor implements the Decryptor interface.
+var _ Decryptor = &AgeDecryptor{}
+
+// AgeDecryptor decrypts values encrypted with age (https://age-encryption.org),
+// using the X25519 identities in local key files.
+//
+// Values can be encrypted for the recipients of the identities with:
+//
+//	echo -n "$VALUE" | age --encrypt --recipient "$RECIPIENT" | base64
+type AgeDecryptor struct {
+	// IdentityFiles are the paths of the files with the age identities
+	// (private keys) used for decryption.
+	IdentityFiles []string
+
+	// mu protects the parsed identities
+	mu         sync.Mutex
+	identities []age.Identity
+}
+
+// Type returns the encryption type of values encrypted with age.
+func (a *AgeDecryptor) Type() string {
+	return AgeEncryptionType
+}
+
+// Decrypt returns the plaintext of the age ciphertext.
+func (a *AgeDecryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	identities, err := a.loadIdentities()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return nil, fmt.Errorf("age decryption failed: %w", err)
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("age decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+// loadIdentities parses the identity files on the first call, and returns
+// the cached identities afterwards.
+func (a *AgeDecryptor) loadIdentities() ([]age.Identity, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.identities != nil {
+		return a.identities, nil
+	}
+	if len(a.IdentityFiles) == 0 {
+		return nil, errors.New("no age identity files configured")
+	}
+	var identities []age.Identity
+	for _, identityFile := range a.IdentityFiles {
+		parsed, err := parseIdentityFile(identityFile)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, parsed...)
+	}
+	a.identities = identities
+	return identities, nil
+}
+
+func parseIdentityFile(path string) ([]age.Identity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read age identity file: %w", err)
+	}
+	defer f.Close()
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity file %q: %w", path, err)
+	}
+	return identities, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeDecryptor_Decrypt(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	otherIdentity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(keyFile,
+		[]byte("# created: 2022-01-01T00:00:00Z\n"+identity.String()+"\n"), 0600))
+	otherKeyFile := filepath.Join(dir, "other.txt")
+	require.NoError(t, os.WriteFile(otherKeyFile, []byte(otherIdentity.String()+"\n"), 0600))
+	invalidKeyFile := filepath.Join(dir, "invalid.txt")
+	require.NoError(t, os.WriteFile(invalidKeyFile, []byte("not a key\n"), 0600))
+
+	var ciphertext bytes.Buffer
+	writer, err := age.Encrypt(&ciphertext, identity.Recipient())
+	require.NoError(t, err)
+	_, err = io.WriteString(writer, "hunter2")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	testCases := map[string]struct {
+		identityFiles []string
+		expected      string
+		expectedErr   string
+	}{
+		"decrypted with the identity files": {
+			identityFiles: []string{otherKeyFile, keyFile},
+			expected:      "hunter2",
+		},
+		"no identity files": {
+			expectedErr: "no age identity files configured",
+		},
+		"no matching identity": {
+			identityFiles: []string{otherKeyFile},
+			expectedErr:   "age decryption failed: no identity matched any of the recipients",
+		},
+		"missing identity file": {
+			identityFiles: []string{filepath.Join(dir, "missing.txt")},
+			expectedErr:   "failed to read age identity file",
+		},
+		"invalid identity file": {
+			identityFiles: []string{invalidKeyFile},
+			expectedErr:   `failed to parse age identity file "` + invalidKeyFile + `"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			decryptor := &AgeDecryptor{IdentityFiles: tc.identityFiles}
+
+			plaintext, err := decryptor.Decrypt(ciphertext.Bytes())
+
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(plaintext))
+		})
+	}
+}
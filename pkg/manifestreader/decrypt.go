@@ -0,0 +1,179 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/redact"
+)
+
+// EncryptedAnnotation marks an object which contains encrypted values. Only
+// the values of marked objects are decrypted.
+//
+// Encrypted values use the envelope ENC[<type>,<data>], where type is the
+// type of the Decryptor and data is the base64 encoded ciphertext.
+const EncryptedAnnotation = "config.kubernetes.io/encrypted"
+
+// encryptedValueRegexp matches the envelope of an encrypted value.
+var encryptedValueRegexp = regexp.MustCompile(`^ENC\[([A-Za-z0-9_-]+),([A-Za-z0-9+/=\s]*)\]$`)
+
+// Decryptor decrypts the encrypted values of manifests.
+type Decryptor interface {
+	// Type returns the encryption type handled by the Decryptor, as used in
+	// the envelope of the encrypted values.
+	Type() string
+	// Decrypt returns the plaintext of the ciphertext.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// DecryptError is returned if the values of an object could not be
+// decrypted. It never includes the plaintext.
+type DecryptError struct {
+	Identifier object.ObjMetadata
+	Field      string
+	Err        error
+}
+
+func (e *DecryptError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("failed to decrypt object %s: %v", e.Identifier, e.Err)
+	}
+	return fmt.Sprintf("failed to decrypt field %s of object %s: %v", e.Field, e.Identifier, e.Err)
+}
+
+func (e *DecryptError) Unwrap() error {
+	return e.Err
+}
+
+// DecryptedFields maps the decrypted objects to the JSONPath expressions of
+// their decrypted fields.
+type DecryptedFields map[*unstructured.Unstructured][]string
+
+// Register registers the decrypted fields as sensitive fields of the
+// objects, so that the plaintext is redacted from events, logs and diffs.
+// The fields are kept client-side, so they are not applied to the cluster.
+// Register must be called once the identifiers of the objects are final,
+// after their namespaces are set and they are transformed.
+func (df DecryptedFields) Register() {
+	for obj, paths := range df {
+		redact.AddSensitiveFields(object.UnstructuredToObjMetadata(obj), paths...)
+	}
+}
+
+// DecryptObjects decrypts the encrypted values of the objects with the
+// EncryptedAnnotation, in place, and returns the decrypted fields. The
+// annotation is removed. It is an error for an object to be marked as
+// encrypted if the decryptor is nil.
+func DecryptObjects(objs []*unstructured.Unstructured, decryptor Decryptor) (DecryptedFields, error) {
+	decrypted := make(DecryptedFields)
+	for _, obj := range objs {
+		if _, found := obj.GetAnnotations()[EncryptedAnnotation]; !found {
+			continue
+		}
+		paths, err := decryptObject(obj, decryptor)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) > 0 {
+			decrypted[obj] = paths
+		}
+	}
+	return decrypted, nil
+}
+
+func decryptObject(obj *unstructured.Unstructured, decryptor Decryptor) ([]string, error) {
+	id := object.UnstructuredToObjMetadata(obj)
+	if decryptor == nil {
+		return nil, &DecryptError{
+			Identifier: id,
+			Err:        fmt.Errorf("object has the %q annotation, but no decryptor is configured", EncryptedAnnotation),
+		}
+	}
+	var paths []string
+	for key, value := range obj.Object {
+		if key == "metadata" {
+			continue
+		}
+		decrypted, err := decryptValue(value, "$"+jsonPathKey(key), decryptor, &paths)
+		if err != nil {
+			return nil, &DecryptError{Identifier: id, Field: err.path, Err: err.err}
+		}
+		obj.Object[key] = decrypted
+	}
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, EncryptedAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// fieldError is the error of decrypting the field with the JSONPath path.
+type fieldError struct {
+	path string
+	err  error
+}
+
+// decryptValue returns the value with the encrypted values it contains
+// decrypted, and appends the JSONPath of the decrypted values to paths.
+func decryptValue(value interface{}, path string, decryptor Decryptor, paths *[]string) (interface{}, *fieldError) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range typedValue {
+			decrypted, err := decryptValue(fieldValue, path+jsonPathKey(key), decryptor, paths)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[key] = decrypted
+		}
+		return typedValue, nil
+	case []interface{}:
+		for i, item := range typedValue {
+			decrypted, err := decryptValue(item, fmt.Sprintf("%s[%d]", path, i), decryptor, paths)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[i] = decrypted
+		}
+		return typedValue, nil
+	case string:
+		match := encryptedValueRegexp.FindStringSubmatch(typedValue)
+		if match == nil {
+			return typedValue, nil
+		}
+		if match[1] != decryptor.Type() {
+			return nil, &fieldError{path: path,
+				err: fmt.Errorf("unsupported encryption type %q, expected %q", match[1], decryptor.Type())}
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(match[2]), ""))
+		if err != nil {
+			return nil, &fieldError{path: path, err: fmt.Errorf("invalid ciphertext encoding: %w", err)}
+		}
+		plaintext, err := decryptor.Decrypt(ciphertext)
+		if err != nil {
+			return nil, &fieldError{path: path, err: err}
+		}
+		*paths = append(*paths, path)
+		return string(plaintext), nil
+	default:
+		return value, nil
+	}
+}
+
+// jsonPathKey returns the JSONPath child operator for the map key.
+func jsonPathKey(key string) string {
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	key = strings.ReplaceAll(key, `'`, `\'`)
+	return "['" + key + "']"
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"sigs.k8s.io/cli-utils/pkg/redact"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+// fakeDecryptor "decrypts" values by reversing them.
+type fakeDecryptor struct {
+	err error
+}
+
+func (f *fakeDecryptor) Type() string {
+	return "fake"
+}
+
+func (f *fakeDecryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		plaintext[len(ciphertext)-1-i] = b
+	}
+	return plaintext, nil
+}
+
+// fakeEncrypt returns the envelope of the value "encrypted" for the
+// fakeDecryptor.
+func fakeEncrypt(value string) string {
+	ciphertext, _ := (&fakeDecryptor{}).Decrypt([]byte(value))
+	return fmt.Sprintf("ENC[fake,%s]", base64.StdEncoding.EncodeToString(ciphertext))
+}
+
+var encryptedSecretManifest = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+  annotations:
+    config.kubernetes.io/encrypted: "true"
+stringData:
+  password: ` + fakeEncrypt("hunter2") + `
+  tls.key: ` + fakeEncrypt("private-key") + `
+  user: admin
+`
+
+func TestDecryptObjects(t *testing.T) {
+	testCases := map[string]struct {
+		manifest            string
+		decryptor           Decryptor
+		expectedStringData  map[string]interface{}
+		expectedAnnotations map[string]string
+		expectedFields      []string
+		expectedErr         string
+	}{
+		"encrypted values are decrypted": {
+			manifest:  encryptedSecretManifest,
+			decryptor: &fakeDecryptor{},
+			expectedStringData: map[string]interface{}{
+				"password": "hunter2",
+				"tls.key":  "private-key",
+				"user":     "admin",
+			},
+			expectedFields: []string{"$['stringData']['password']", "$['stringData']['tls.key']"},
+		},
+		"unmarked objects are not decrypted": {
+			manifest:  strings.Replace(encryptedSecretManifest, EncryptedAnnotation, "example.com/other", 1),
+			decryptor: &fakeDecryptor{},
+			expectedStringData: map[string]interface{}{
+				"password": fakeEncrypt("hunter2"),
+				"tls.key":  fakeEncrypt("private-key"),
+				"user":     "admin",
+			},
+			expectedAnnotations: map[string]string{
+				"example.com/other": "true",
+			},
+		},
+		"marked object without decryptor": {
+			manifest:    encryptedSecretManifest,
+			expectedErr: `failed to decrypt object default_creds__Secret: object has the "config.kubernetes.io/encrypted" annotation, but no decryptor is configured`,
+		},
+		"decryption failure": {
+			manifest:    encryptedSecretManifest,
+			decryptor:   &fakeDecryptor{err: errors.New("no matching key")},
+			expectedErr: "no matching key",
+		},
+		"unsupported encryption type": {
+			manifest:    strings.Replace(encryptedSecretManifest, "ENC[fake,", "ENC[pgp,", 1),
+			decryptor:   &fakeDecryptor{},
+			expectedErr: `unsupported encryption type "pgp", expected "fake"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := testutil.Unstructured(t, tc.manifest)
+
+			decrypted, err := DecryptObjects([]*unstructured.Unstructured{obj}, tc.decryptor)
+
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				// the error must never include the plaintext
+				assert.NotContains(t, err.Error(), "hunter2")
+				return
+			}
+			assert.NoError(t, err)
+			testutil.AssertEqual(t, tc.expectedStringData, obj.Object["stringData"])
+			testutil.AssertEqual(t, tc.expectedAnnotations, obj.GetAnnotations())
+			testutil.AssertEqual(t, tc.expectedFields, decrypted[obj])
+			// the decrypted values must be redacted
+			testutil.AssertEqual(t, map[string]interface{}{
+				"password": redact.Placeholder,
+				"tls.key":  redact.Placeholder,
+				"user":     redact.Placeholder,
+			}, redact.Object(obj).Object["stringData"])
+		})
+	}
+}
+
+func TestDecryptObjects_SensitiveFields(t *testing.T) {
+	obj := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+  annotations:
+    config.kubernetes.io/encrypted: "true"
+    config.kubernetes.io/sensitive-fields: $.data.other
+data:
+  apiKey: `+fakeEncrypt("key-1")+`
+  other: value
+`)
+
+	decrypted, err := DecryptObjects([]*unstructured.Unstructured{obj}, &fakeDecryptor{})
+	assert.NoError(t, err)
+	decrypted.Register()
+
+	testutil.AssertEqual(t, "key-1", obj.Object["data"].(map[string]interface{})["apiKey"])
+	// the decrypted fields are not added to the annotation, which is applied
+	testutil.AssertEqual(t, map[string]string{
+		redact.SensitiveFieldsAnnotation: "$.data.other",
+	}, obj.GetAnnotations())
+	testutil.AssertEqual(t, []string{"$.data.other", "$['data']['apiKey']"}, redact.SensitiveFields(obj))
+	testutil.AssertEqual(t, map[string]interface{}{
+		"apiKey": redact.Placeholder,
+		"other":  redact.Placeholder,
+	}, redact.Object(obj).Object["data"])
+
+	// the fields are also redacted from the versions read from the cluster
+	live := obj.DeepCopy()
+	live.SetAnnotations(nil)
+	testutil.AssertEqual(t, map[string]interface{}{
+		"apiKey": redact.Placeholder,
+		"other":  "value",
+	}, redact.Object(live).Object["data"])
+}
+
+func TestStreamManifestReader_Decrypt(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+	defer tf.Cleanup()
+
+	mapper, err := tf.ToRESTMapper()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	objs, err := (&StreamManifestReader{
+		ReaderName: "testReader",
+		Reader:     strings.NewReader(encryptedSecretManifest),
+		ReaderOptions: ReaderOptions{
+			Mapper:    mapper,
+			Namespace: "default",
+			Decryptor: &fakeDecryptor{},
+		},
+	}).Read()
+
+	assert.NoError(t, err)
+	if assert.Len(t, objs, 1) {
+		testutil.AssertEqual(t, "hunter2", objs[0].Object["stringData"].(map[string]interface{})["password"])
+	}
+}
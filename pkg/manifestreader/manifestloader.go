@@ -15,15 +15,45 @@ type ManifestLoader interface {
 	ManifestReader(reader io.Reader, path string) (ManifestReader, error)
 }
 
+// LoaderOptions defines the options of the ManifestReaders returned by a
+// ManifestLoader. They are read when a ManifestReader is created, so they can
+// be bound to command line flags.
+type LoaderOptions struct {
+	// Decryptor decrypts the encrypted values of the manifests.
+	Decryptor Decryptor
+	// Substitute enables the substitution of variables in the manifests,
+	// with the values of the environment variables.
+	Substitute bool
+	// ValuesFile is the path of a YAML file with the values of variables,
+	// which override the environment variables. Enables the substitution of
+	// variables if not empty.
+	ValuesFile string
+	// StrictSubstitution makes undefined variables an error.
+	StrictSubstitution bool
+	// Transform defines the common transformations of the objects.
+	Transform TransformOptions
+}
+
 // manifestLoader implements the ManifestLoader interface
 type manifestLoader struct {
 	factory util.Factory
+	options *LoaderOptions
 }
 
 // NewManifestLoader returns an instance of manifestLoader.
 func NewManifestLoader(f util.Factory) ManifestLoader {
 	return &manifestLoader{
 		factory: f,
+		options: &LoaderOptions{},
+	}
+}
+
+// NewManifestLoaderWithOptions returns an instance of manifestLoader, which
+// creates ManifestReaders with the options.
+func NewManifestLoaderWithOptions(f util.Factory, options *LoaderOptions) ManifestLoader {
+	return &manifestLoader{
+		factory: f,
+		options: options,
 	}
 }
 
@@ -47,6 +77,19 @@ func (f *manifestLoader) ManifestReader(reader io.Reader, path string) (Manifest
 		Mapper:           mapper,
 		Namespace:        namespace,
 		EnforceNamespace: enforceNamespace,
+		Decryptor:        f.options.Decryptor,
+		Transform:        f.options.Transform,
+	}
+
+	if f.options.Substitute || f.options.ValuesFile != "" {
+		variables, err := SubstitutionVariables(f.options.ValuesFile)
+		if err != nil {
+			return nil, err
+		}
+		readerOptions.Substitution = &SubstitutionOptions{
+			Variables: variables,
+			Strict:    f.options.StrictSubstitution,
+		}
 	}
 
 	return mReader(path, reader, readerOptions), nil
@@ -21,4 +21,15 @@ type ReaderOptions struct {
 	Validate         bool
 	Namespace        string
 	EnforceNamespace bool
+	// Decryptor, if set, decrypts the encrypted values of the objects with
+	// the EncryptedAnnotation.
+	Decryptor Decryptor
+	// Substitution, if set, enables the substitution of the variable
+	// placeholders in the manifests. The objects with placeholders that
+	// could not be substituted are still returned, and the error is a
+	// validation error for each of them. See SplitObjectErrors.
+	Substitution *SubstitutionOptions
+	// Transform defines the common transformations of the objects, which
+	// are applied after the namespaces of the objects are set.
+	Transform TransformOptions
 }
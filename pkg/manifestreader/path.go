@@ -24,6 +24,7 @@ type PathManifestReader struct {
 // Read reads the manifests and returns them as Info objects.
 func (p *PathManifestReader) Read() ([]*unstructured.Unstructured, error) {
 	var objs []*unstructured.Unstructured
+	var substErrs substitutionErrors
 	nodes, err := (&kio.LocalPackageReader{
 		PackagePath: p.Path,
 	}).Read()
@@ -36,15 +37,31 @@ func (p *PathManifestReader) Read() ([]*unstructured.Unstructured, error) {
 		if err != nil {
 			return objs, err
 		}
+		errs := substErrs.substitute(p.Substitution, n, p.Path)
 		u, err := KyamlNodeToUnstructured(n)
 		if err != nil {
 			return objs, err
 		}
+		substErrs.add(u, errs)
 		objs = append(objs, u)
 	}
 
 	objs = FilterLocalConfig(objs)
 
+	decrypted, err := DecryptObjects(objs, p.Decryptor)
+	if err != nil {
+		return objs, err
+	}
+
 	err = SetNamespaces(p.Mapper, objs, p.Namespace, p.EnforceNamespace)
-	return objs, err
+	if err != nil {
+		return objs, err
+	}
+
+	err = TransformObjects(objs, p.Transform)
+	if err != nil {
+		return objs, err
+	}
+	decrypted.Register()
+	return objs, substErrs.toError(objs)
 }
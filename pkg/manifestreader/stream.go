@@ -27,6 +27,7 @@ type StreamManifestReader struct {
 // Read reads the manifests and returns them as Info objects.
 func (r *StreamManifestReader) Read() ([]*unstructured.Unstructured, error) {
 	var objs []*unstructured.Unstructured
+	var substErrs substitutionErrors
 	nodes, err := (&kio.ByteReader{
 		Reader: r.Reader,
 	}).Read()
@@ -39,15 +40,31 @@ func (r *StreamManifestReader) Read() ([]*unstructured.Unstructured, error) {
 		if err != nil {
 			return objs, err
 		}
+		errs := substErrs.substitute(r.Substitution, n, r.ReaderName)
 		u, err := KyamlNodeToUnstructured(n)
 		if err != nil {
 			return objs, err
 		}
+		substErrs.add(u, errs)
 		objs = append(objs, u)
 	}
 
 	objs = FilterLocalConfig(objs)
 
+	decrypted, err := DecryptObjects(objs, r.Decryptor)
+	if err != nil {
+		return objs, err
+	}
+
 	err = SetNamespaces(r.Mapper, objs, r.Namespace, r.EnforceNamespace)
-	return objs, err
+	if err != nil {
+		return objs, err
+	}
+
+	err = TransformObjects(objs, r.Transform)
+	if err != nil {
+		return objs, err
+	}
+	decrypted.Register()
+	return objs, substErrs.toError(objs)
 }
@@ -0,0 +1,249 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// SubstitutionOptions enables the substitution of variable placeholders in
+// the string values of the manifests:
+//
+//   - ${VAR} is replaced by the value of the variable VAR.
+//   - ${VAR:-default} is replaced by the value of the variable VAR, or by
+//     default if VAR is undefined or empty.
+//   - $${VAR} is replaced by the literal ${VAR}, without substitution.
+//
+// Placeholders in unquoted values are substituted before the type of the
+// value is resolved, so "replicas: ${REPLICAS}" results in a number if the
+// value of REPLICAS is a number. Quote the value to keep it a string.
+type SubstitutionOptions struct {
+	// Variables are the values of the variables.
+	Variables map[string]string
+	// Strict makes undefined variables without a default value an error.
+	// Otherwise, they are replaced by an empty string.
+	Strict bool
+}
+
+// SubstitutionError is returned if the placeholders in a field of an
+// object could not be substituted.
+type SubstitutionError struct {
+	// Path is the path of the file the object was read from, or the name of
+	// the reader.
+	Path string
+	// Field is the path of the field in the object.
+	Field string
+	Err   error
+}
+
+func (e *SubstitutionError) Error() string {
+	return fmt.Sprintf("variable substitution failed (file: %q, field: %s): %v", e.Path, e.Field, e.Err)
+}
+
+func (e *SubstitutionError) Unwrap() error {
+	return e.Err
+}
+
+// UndefinedVariableError is returned by a strict substitution if a variable
+// without a default value is undefined.
+type UndefinedVariableError struct {
+	Name string
+}
+
+func (e *UndefinedVariableError) Error() string {
+	return fmt.Sprintf("undefined variable %q", e.Name)
+}
+
+// placeholderRegexp matches the escaped placeholders, the placeholders
+// and the unterminated placeholders.
+var placeholderRegexp = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}|\$\{`)
+
+// variableRegexp matches the content of a placeholder.
+var variableRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?::-(.*))?$`)
+
+// SubstitutionVariables returns the variables for substitution: the
+// environment variables, overridden by the values in the values file, if
+// the path is not empty. The values file must be a YAML map of the
+// variable names to scalar values.
+func SubstitutionVariables(valuesFile string) (map[string]string, error) {
+	variables := make(map[string]string)
+	for _, env := range os.Environ() {
+		if name, value, found := strings.Cut(env, "="); found {
+			variables[name] = value
+		}
+	}
+	if valuesFile == "" {
+		return variables, nil
+	}
+	data, err := os.ReadFile(valuesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file: %w", err)
+	}
+	var values map[string]interface{}
+	if err := k8syaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values file %q: %w", valuesFile, err)
+	}
+	for name, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("invalid values file %q: value of %q is not a scalar", valuesFile, name)
+		case nil:
+			variables[name] = ""
+		default:
+			variables[name] = fmt.Sprint(value)
+		}
+	}
+	return variables, nil
+}
+
+// substitute replaces the placeholders in the string.
+func (o *SubstitutionOptions) substitute(s string) (string, error) {
+	var errs []error
+	result := placeholderRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		if match == "${" {
+			errs = append(errs, errors.New("unterminated placeholder"))
+			return match
+		}
+		parts := variableRegexp.FindStringSubmatch(match[2 : len(match)-1])
+		if parts == nil {
+			errs = append(errs, fmt.Errorf("invalid placeholder %q", match))
+			return match
+		}
+		name, defaultValue := parts[1], parts[2]
+		value, found := o.Variables[name]
+		switch {
+		case found && value != "":
+			return value
+		case strings.Contains(match, ":-"):
+			return defaultValue
+		case found:
+			return value
+		case o.Strict:
+			errs = append(errs, &UndefinedVariableError{Name: name})
+			return match
+		default:
+			return ""
+		}
+	})
+	return result, multierror.Wrap(errs...)
+}
+
+// substituteNode replaces the placeholders in the scalar values of the
+// node, and returns the errors of the fields which could not be substituted.
+func (o *SubstitutionOptions) substituteNode(n *yaml.RNode, path string) []error {
+	if path == "" {
+		path = "<unknown>"
+	}
+	var errs []error
+	o.substituteYNode(n.YNode(), "", path, &errs)
+	return errs
+}
+
+func (o *SubstitutionOptions) substituteYNode(n *yaml.Node, field, path string, errs *[]error) {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i, item := range n.Content {
+			itemField := field
+			if n.Kind == yaml.SequenceNode {
+				itemField = fmt.Sprintf("%s[%d]", field, i)
+			}
+			o.substituteYNode(item, itemField, path, errs)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			o.substituteYNode(n.Content[i+1], strings.TrimPrefix(field+"."+key, "."), path, errs)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(n.Value, "${") {
+			return
+		}
+		value, err := o.substitute(n.Value)
+		if err != nil {
+			*errs = append(*errs, &SubstitutionError{Path: path, Field: field, Err: err})
+			return
+		}
+		n.Value = value
+		if n.Style == 0 {
+			// Resolve the type of unquoted values again, after substitution.
+			n.Tag = ""
+		}
+	}
+}
+
+// substitutionErrors collects the substitution errors of the objects, to
+// report them after the objects are complete, with their final identifiers.
+type substitutionErrors struct {
+	errs map[*unstructured.Unstructured][]error
+}
+
+// substitute replaces the placeholders in the node of the object, if
+// substitution is enabled. The path is used as provenance if the node has
+// no path annotation.
+func (se *substitutionErrors) substitute(o *SubstitutionOptions, n *yaml.RNode, path string) []error {
+	if o == nil {
+		return nil
+	}
+	if nodePath, _, err := kioutil.GetFileAnnotations(n); err == nil && nodePath != "" {
+		path = nodePath
+	}
+	return o.substituteNode(n, path)
+}
+
+// add records the errors of the object.
+func (se *substitutionErrors) add(obj *unstructured.Unstructured, errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+	if se.errs == nil {
+		se.errs = make(map[*unstructured.Unstructured][]error)
+	}
+	se.errs[obj] = errs
+}
+
+// toError returns the errors of the objects, which were not filtered out,
+// as validation errors.
+func (se *substitutionErrors) toError(objs []*unstructured.Unstructured) error {
+	var errs []error
+	for _, obj := range objs {
+		if objErrs, found := se.errs[obj]; found {
+			errs = append(errs, validation.NewError(multierror.Wrap(objErrs...),
+				object.UnstructuredToObjMetadata(obj)))
+		}
+	}
+	return multierror.Wrap(errs...)
+}
+
+// SplitObjectErrors splits the error returned by ManifestReader.Read into
+// the validation errors of specific objects, like the substitution errors,
+// and any other error. The validation errors do not prevent the use of the
+// other objects, and can be reported with ApplierOptions.ValidationErrors.
+func SplitObjectErrors(err error) ([]error, error) {
+	if err == nil {
+		return nil, nil
+	}
+	errs := multierror.Unwrap(err)
+	for _, e := range errs {
+		var vErr *validation.Error
+		if !errors.As(e, &vErr) {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
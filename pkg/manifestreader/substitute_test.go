@@ -0,0 +1,215 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestSubstitutionOptions_Substitute(t *testing.T) {
+	variables := map[string]string{
+		"NAME":  "app",
+		"EMPTY": "",
+	}
+
+	testCases := map[string]struct {
+		value         string
+		strict        bool
+		expected      string
+		expectedError string
+	}{
+		"no placeholders": {
+			value:    "app:v1",
+			expected: "app:v1",
+		},
+		"defined variable": {
+			value:    "${NAME}-config",
+			expected: "app-config",
+		},
+		"default value of undefined variable": {
+			value:    "${UNDEFINED:-default}",
+			expected: "default",
+		},
+		"default value of empty variable": {
+			value:    "${EMPTY:-default}",
+			expected: "default",
+		},
+		"default value is ignored for defined variable": {
+			value:    "${NAME:-default}",
+			expected: "app",
+		},
+		"empty default value": {
+			value:    "${UNDEFINED:-}",
+			strict:   true,
+			expected: "",
+		},
+		"escaped placeholder": {
+			value:    "$${NAME} is ${NAME}",
+			expected: "${NAME} is app",
+		},
+		"undefined variable": {
+			value:    "x${UNDEFINED}x",
+			expected: "xx",
+		},
+		"undefined variable in strict mode": {
+			value:         "${UNDEFINED}",
+			strict:        true,
+			expectedError: `undefined variable "UNDEFINED"`,
+		},
+		"empty variable in strict mode": {
+			value:    "${EMPTY}",
+			strict:   true,
+			expected: "",
+		},
+		"invalid placeholder": {
+			value:         "${1NAME}",
+			expectedError: `invalid placeholder "${1NAME}"`,
+		},
+		"unterminated placeholder": {
+			value:         "${NAME",
+			expectedError: "unterminated placeholder",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			o := &SubstitutionOptions{
+				Variables: variables,
+				Strict:    tc.strict,
+			}
+
+			actual, err := o.substitute(tc.value)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSubstitutionVariables(t *testing.T) {
+	t.Setenv("SUBST_TEST_NAME", "from-env")
+	t.Setenv("SUBST_TEST_IMAGE", "app:v1")
+
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	err := os.WriteFile(valuesFile, []byte("SUBST_TEST_NAME: from-file\nREPLICAS: 3\nDEBUG: true\n"), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	variables, err := SubstitutionVariables(valuesFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", variables["SUBST_TEST_NAME"])
+	assert.Equal(t, "app:v1", variables["SUBST_TEST_IMAGE"])
+	assert.Equal(t, "3", variables["REPLICAS"])
+	assert.Equal(t, "true", variables["DEBUG"])
+}
+
+func TestSubstitutionVariables_InvalidValuesFile(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	err := os.WriteFile(valuesFile, []byte("LABELS:\n  app: test\n"), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = SubstitutionVariables(valuesFile)
+
+	assert.ErrorContains(t, err, `value of "LABELS" is not a scalar`)
+}
+
+var substitutedManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${NAME}
+spec:
+  replicas: ${REPLICAS}
+  template:
+    metadata:
+      annotations:
+        replicas: "${REPLICAS}"
+    spec:
+      containers:
+      - name: app
+        image: ${IMAGE:-app:latest}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ${NAME}-config
+data:
+  url: ${URL}
+`
+
+func TestStreamManifestReader_Substitute(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+	defer tf.Cleanup()
+
+	mapper, err := tf.ToRESTMapper()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	objs, err := (&StreamManifestReader{
+		ReaderName: "testReader",
+		Reader:     strings.NewReader(substitutedManifests),
+		ReaderOptions: ReaderOptions{
+			Mapper:    mapper,
+			Namespace: "default",
+			Substitution: &SubstitutionOptions{
+				Variables: map[string]string{
+					"NAME":     "app",
+					"REPLICAS": "3",
+				},
+				Strict: true,
+			},
+		},
+	}).Read()
+
+	// All objects are returned, with the substitution errors reported as
+	// validation errors of the objects.
+	if !assert.Len(t, objs, 2) {
+		t.FailNow()
+	}
+	deployment := objs[0]
+	testutil.AssertEqual(t, "app", deployment.GetName())
+	// Unquoted values are numbers after substitution.
+	testutil.AssertEqual(t, float64(3), deployment.Object["spec"].(map[string]interface{})["replicas"])
+	testutil.AssertEqual(t, "3", deployment.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})["replicas"])
+	containers := deployment.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	testutil.AssertEqual(t, "app:latest", containers[0].(map[string]interface{})["image"])
+	testutil.AssertEqual(t, "app-config", objs[1].GetName())
+
+	objErrs, err := SplitObjectErrors(err)
+	assert.NoError(t, err)
+	if assert.Len(t, objErrs, 1) {
+		var vErr *validation.Error
+		if assert.True(t, errors.As(objErrs[0], &vErr)) {
+			assert.Equal(t, "app-config", vErr.Identifiers()[0].Name)
+		}
+		assert.ErrorContains(t, objErrs[0], `variable substitution failed (file: "testReader", field: data.url): undefined variable "URL"`)
+	}
+}
+
+func TestSplitObjectErrors(t *testing.T) {
+	_, err := SplitObjectErrors(errors.New("read failed"))
+	assert.EqualError(t, err, "read failed")
+
+	objErrs, err := SplitObjectErrors(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, objErrs)
+}
@@ -0,0 +1,225 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/dependson"
+	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+)
+
+// TransformOptions defines common transformations of the objects, which
+// allow the same manifests to be applied to different namespaces and
+// environments.
+type TransformOptions struct {
+	// Namespace, if set, overrides the namespace of all the namespaced
+	// objects, including the inventory object. A Namespace object with the
+	// name of the original namespace is renamed. The references to the
+	// objects in the depends-on and apply-time-mutation annotations are
+	// updated to match. The inventory ID is suffixed with the namespace, so
+	// the same package applied to different namespaces has different
+	// inventories, which do not prune each other's cluster-scoped objects.
+	Namespace string
+	// Labels are added to the labels of all the objects, and override the
+	// existing values. Label selectors and templates are not changed.
+	Labels map[string]string
+	// Annotations are added to the annotations of all the objects, and
+	// override the existing values.
+	Annotations map[string]string
+}
+
+// IsEmpty returns true if the options do not transform the objects.
+func (o TransformOptions) IsEmpty() bool {
+	return o.Namespace == "" && len(o.Labels) == 0 && len(o.Annotations) == 0
+}
+
+// Validate returns an error if the namespace, labels or annotations are
+// invalid.
+func (o TransformOptions) Validate() error {
+	if o.Namespace != "" {
+		if errs := validation.IsDNS1123Label(o.Namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q: %s", o.Namespace, strings.Join(errs, "; "))
+		}
+	}
+	for key, value := range o.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value of label %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for key := range o.Annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// TransformObjects applies the transformations to the objects, in place. The
+// namespaces of the objects must already be set, like by SetNamespaces, so
+// that only namespaced objects have a namespace.
+func TransformObjects(objs []*unstructured.Unstructured, opts TransformOptions) error {
+	if opts.IsEmpty() {
+		return nil
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Namespace != "" {
+		if err := overrideNamespace(objs, opts.Namespace); err != nil {
+			return err
+		}
+	}
+	for _, obj := range objs {
+		if len(opts.Labels) > 0 {
+			obj.SetLabels(mergeStringMaps(obj.GetLabels(), opts.Labels))
+		}
+		if len(opts.Annotations) > 0 {
+			obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), opts.Annotations))
+		}
+	}
+	return nil
+}
+
+// overrideNamespace sets the namespace of the namespaced objects, renames
+// the Namespace object of the original namespace, and updates the references
+// to the changed objects.
+func overrideNamespace(objs []*unstructured.Unstructured, namespace string) error {
+	// ids maps the original identifiers of the changed objects to the new ones.
+	ids := make(map[object.ObjMetadata]object.ObjMetadata)
+	namespaces := make(map[string]bool)
+	for _, obj := range objs {
+		// Inventory objects are always namespaced, but their namespace is
+		// not set by SetNamespaces.
+		if obj.GetNamespace() == "" && !inventory.IsInventoryObject(obj) {
+			continue
+		}
+		if ns := obj.GetNamespace(); ns != "" {
+			namespaces[ns] = true
+		}
+		id := object.UnstructuredToObjMetadata(obj)
+		if inventory.IsInventoryObject(obj) && obj.GetNamespace() != namespace {
+			setNamespacedInventoryID(obj, namespace)
+		}
+		obj.SetNamespace(namespace)
+		ids[id] = object.UnstructuredToObjMetadata(obj)
+	}
+
+	renamed := ""
+	for _, obj := range objs {
+		if !object.IsNamespace(obj) || !namespaces[obj.GetName()] {
+			continue
+		}
+		if renamed != "" {
+			return fmt.Errorf("cannot override the namespace: found Namespace objects %q and %q for the original namespaces",
+				renamed, obj.GetName())
+		}
+		renamed = obj.GetName()
+		id := object.UnstructuredToObjMetadata(obj)
+		obj.SetName(namespace)
+		ids[id] = object.UnstructuredToObjMetadata(obj)
+	}
+
+	for _, obj := range objs {
+		if err := updateDependsOn(obj, ids); err != nil {
+			return err
+		}
+		if err := updateMutation(obj, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setNamespacedInventoryID suffixes the inventory ID label of the inventory
+// object with the namespace. IDs which would be too long for a label value
+// are replaced by a hash of the ID and the namespace.
+func setNamespacedInventoryID(obj *unstructured.Unstructured, namespace string) {
+	labels := obj.GetLabels()
+	id, found := labels[common.InventoryLabel]
+	if !found {
+		return
+	}
+	newID := id + "-" + namespace
+	if len(newID) > validation.LabelValueMaxLength {
+		newID = fmt.Sprintf("%x", sha256.Sum256([]byte(id+"/"+namespace)))[:40]
+	}
+	labels[common.InventoryLabel] = newID
+	obj.SetLabels(labels)
+}
+
+// updateDependsOn updates the references in the depends-on annotation of the
+// object to the changed objects.
+func updateDependsOn(obj *unstructured.Unstructured, ids map[object.ObjMetadata]object.ObjMetadata) error {
+	if !dependson.HasAnnotation(obj) {
+		return nil
+	}
+	depSet, err := dependson.ReadAnnotation(obj)
+	if err != nil {
+		// Invalid annotations are reported by the validation of the objects.
+		return nil
+	}
+	changed := false
+	for i, dep := range depSet {
+		if id, found := ids[dep]; found {
+			depSet[i] = id
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return dependson.WriteAnnotation(obj, depSet)
+}
+
+// updateMutation updates the source references in the apply-time-mutation
+// annotation of the object to the changed objects. References without a
+// namespace default to the namespace of the object, so they are kept as is.
+func updateMutation(obj *unstructured.Unstructured, ids map[object.ObjMetadata]object.ObjMetadata) error {
+	if !mutation.HasAnnotation(obj) {
+		return nil
+	}
+	subs, err := mutation.ReadAnnotation(obj)
+	if err != nil {
+		// Invalid annotations are reported by the validation of the objects.
+		return nil
+	}
+	changed := false
+	for i, sub := range subs {
+		if sub.SourceRef.Namespace == "" && !object.IsNamespace(sub.SourceRef.ToUnstructured()) {
+			continue
+		}
+		if id, found := ids[sub.SourceRef.ToObjMetadata()]; found {
+			subs[i].SourceRef.Namespace = id.Namespace
+			subs[i].SourceRef.Name = id.Name
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return mutation.WriteAnnotation(obj, subs)
+}
+
+// mergeStringMaps returns the values of the first map, overridden by the
+// values of the second map.
+func mergeStringMaps(m, overrides map[string]string) map[string]string {
+	if m == nil {
+		m = make(map[string]string, len(overrides))
+	}
+	for key, value := range overrides {
+		m[key] = value
+	}
+	return m
+}
@@ -0,0 +1,330 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var transformNamespace = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: original
+`
+
+var transformInventory = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: original
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test
+`
+
+var transformDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: original
+  labels:
+    app: app
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/original/ConfigMap/config,/namespaces/external/Secret/creds,/Namespace/original
+`
+
+var transformConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: original
+`
+
+var transformClusterRole = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/original/ConfigMap/config
+`
+
+func TestTransformObjects(t *testing.T) {
+	testCases := map[string]struct {
+		manifests     []string
+		opts          TransformOptions
+		expected      []string
+		expectedError string
+	}{
+		"no transformations": {
+			manifests: []string{transformDeployment, transformClusterRole},
+			expected:  []string{transformDeployment, transformClusterRole},
+		},
+		"override namespace": {
+			manifests: []string{
+				transformNamespace,
+				transformInventory,
+				transformDeployment,
+				transformConfigMap,
+				transformClusterRole,
+			},
+			opts: TransformOptions{Namespace: "test"},
+			expected: []string{
+				`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`,
+				`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: test
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test-test
+`,
+				`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: test
+  labels:
+    app: app
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/test/ConfigMap/config,/namespaces/external/Secret/creds,/Namespace/test
+`,
+				`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: test
+`,
+				`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/test/ConfigMap/config
+`,
+			},
+		},
+		"override namespace with the original namespace keeps the inventory ID": {
+			manifests: []string{transformInventory},
+			opts:      TransformOptions{Namespace: "original"},
+			expected:  []string{transformInventory},
+		},
+		"override namespace hashes long inventory IDs": {
+			manifests: []string{strings.ReplaceAll(transformInventory, "inventory-id: test",
+				"inventory-id: "+strings.Repeat("a", 60))},
+			opts: TransformOptions{Namespace: "test"},
+			expected: []string{`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: test
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: 59b0458744af505c8288ab0522e77d5cb034d390
+`},
+		},
+		"common labels and annotations": {
+			manifests: []string{transformDeployment, transformConfigMap},
+			opts: TransformOptions{
+				Labels: map[string]string{
+					"app":                          "override",
+					"app.kubernetes.io/managed-by": "kapply",
+				},
+				Annotations: map[string]string{
+					"example.com/owner": "team",
+				},
+			},
+			expected: []string{
+				`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: original
+  labels:
+    app: override
+    app.kubernetes.io/managed-by: kapply
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/original/ConfigMap/config,/namespaces/external/Secret/creds,/Namespace/original
+    example.com/owner: team
+`,
+				`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: original
+  labels:
+    app: override
+    app.kubernetes.io/managed-by: kapply
+  annotations:
+    example.com/owner: team
+`,
+			},
+		},
+		"multiple namespace objects": {
+			manifests: []string{
+				transformNamespace,
+				strings.ReplaceAll(transformNamespace, "original", "other"),
+				transformConfigMap,
+				strings.ReplaceAll(transformConfigMap, "original", "other"),
+			},
+			opts:          TransformOptions{Namespace: "test"},
+			expectedError: `cannot override the namespace: found Namespace objects "original" and "other" for the original namespaces`,
+		},
+		"invalid namespace": {
+			manifests:     []string{transformConfigMap},
+			opts:          TransformOptions{Namespace: "Test"},
+			expectedError: `invalid namespace "Test"`,
+		},
+		"invalid label value": {
+			manifests: []string{transformConfigMap},
+			opts: TransformOptions{
+				Labels: map[string]string{"app": "not valid"},
+			},
+			expectedError: `invalid value of label "app"`,
+		},
+		"invalid annotation key": {
+			manifests: []string{transformConfigMap},
+			opts: TransformOptions{
+				Annotations: map[string]string{"not valid": "value"},
+			},
+			expectedError: `invalid annotation key "not valid"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var objs []*unstructured.Unstructured
+			for _, manifest := range tc.manifests {
+				objs = append(objs, testutil.Unstructured(t, manifest))
+			}
+
+			err := TransformObjects(objs, tc.opts)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			var expected []*unstructured.Unstructured
+			for _, manifest := range tc.expected {
+				expected = append(expected, testutil.Unstructured(t, manifest))
+			}
+			testutil.AssertEqual(t, expected, objs)
+		})
+	}
+}
+
+func TestTransformObjects_Mutation(t *testing.T) {
+	obj := testutil.Unstructured(t, transformConfigMap)
+	err := mutation.WriteAnnotation(obj, mutation.ApplyTimeMutation{
+		{
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config", Namespace: "original"},
+			SourcePath: "$.data.a",
+			TargetPath: "$.data.a",
+		},
+		{
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config"},
+			SourcePath: "$.data.b",
+			TargetPath: "$.data.b",
+		},
+		{
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config", Namespace: "external"},
+			SourcePath: "$.data.c",
+			TargetPath: "$.data.c",
+		},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	err = TransformObjects([]*unstructured.Unstructured{obj}, TransformOptions{Namespace: "test"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	subs, err := mutation.ReadAnnotation(obj)
+	assert.NoError(t, err)
+	testutil.AssertEqual(t, mutation.ApplyTimeMutation{
+		{
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config", Namespace: "test"},
+			SourcePath: "$.data.a",
+			TargetPath: "$.data.a",
+		},
+		{
+			// References without a namespace default to the namespace of the
+			// object, so they are not changed.
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config"},
+			SourcePath: "$.data.b",
+			TargetPath: "$.data.b",
+		},
+		{
+			SourceRef:  mutation.ResourceReference{Kind: "ConfigMap", Name: "config", Namespace: "external"},
+			SourcePath: "$.data.c",
+			TargetPath: "$.data.c",
+		},
+	}, subs)
+}
+
+func TestStreamManifestReader_Transform(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+	defer tf.Cleanup()
+
+	mapper, err := tf.ToRESTMapper()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	objs, err := (&StreamManifestReader{
+		ReaderName: "testReader",
+		Reader: strings.NewReader(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: defaulted
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: explicit
+  namespace: other
+`),
+		ReaderOptions: ReaderOptions{
+			Mapper:    mapper,
+			Namespace: "default",
+			Transform: TransformOptions{
+				Namespace: "test",
+				Labels:    map[string]string{"env": "test"},
+			},
+		},
+	}).Read()
+
+	assert.NoError(t, err)
+	if assert.Len(t, objs, 2) {
+		for _, obj := range objs {
+			assert.Equal(t, "test", obj.GetNamespace())
+			assert.Equal(t, map[string]string{"env": "test"}, obj.GetLabels())
+		}
+	}
+}
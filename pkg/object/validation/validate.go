@@ -7,8 +7,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/jsonpath"
 	"sigs.k8s.io/cli-utils/pkg/multierror"
 	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/redact"
 )
 
 // Validator contains functionality for validating a set of resources prior
@@ -17,6 +21,9 @@ import (
 type Validator struct {
 	Mapper    meta.RESTMapper
 	Collector *Collector
+	// Scope, if not empty, restricts the namespaces and the cluster-scoped
+	// kinds of the resources.
+	Scope inventory.Scope
 }
 
 // Validate validates the provided resources. A RESTMapper will be used
@@ -34,6 +41,18 @@ func (v *Validator) Validate(objs []*unstructured.Unstructured) {
 		if err := v.validateNamespace(obj, crds); err != nil {
 			objErrors = append(objErrors, err)
 		}
+		if err := v.validateApplyStrategy(obj); err != nil {
+			objErrors = append(objErrors, err)
+		}
+		if err := v.validateVerifyCheck(obj); err != nil {
+			objErrors = append(objErrors, err)
+		}
+		if err := v.validateSensitiveFields(obj); err != nil {
+			objErrors = append(objErrors, err)
+		}
+		if err := v.validateScope(obj); err != nil {
+			objErrors = append(objErrors, err)
+		}
 		if len(objErrors) > 0 {
 			// one error per object
 			v.Collector.Collect(NewError(
@@ -92,3 +111,49 @@ func (v *Validator) validateNamespace(u *unstructured.Unstructured, crds []*unst
 	}
 	return nil
 }
+
+// validateScope validates that the resource is inside the scope of the
+// inventory.
+func (v *Validator) validateScope(u *unstructured.Unstructured) error {
+	// skip scope validation if kind is missing (avoid redundant error)
+	if u.GetKind() == "" {
+		return nil
+	}
+	return v.Scope.Check(object.UnstructuredToObjMetadata(u))
+}
+
+// validateApplyStrategy validates the value of the apply strategy annotation
+// of the resource, if set.
+func (v *Validator) validateApplyStrategy(u *unstructured.Unstructured) error {
+	if _, err := common.ReadApplyStrategy(u); err != nil {
+		validValues := make([]string, 0, len(common.ApplyStrategies))
+		for _, strategy := range common.ApplyStrategies {
+			validValues = append(validValues, string(strategy))
+		}
+		return field.NotSupported(field.NewPath("metadata", "annotations").Key(common.ApplyStrategyAnnotation),
+			u.GetAnnotations()[common.ApplyStrategyAnnotation], validValues)
+	}
+	return nil
+}
+
+// validateVerifyCheck validates the value of the verify annotation of the
+// resource, if set.
+func (v *Validator) validateVerifyCheck(u *unstructured.Unstructured) error {
+	if _, _, err := common.ReadVerifyCheck(u); err != nil {
+		return field.Invalid(field.NewPath("metadata", "annotations").Key(common.VerifyAnnotation),
+			u.GetAnnotations()[common.VerifyAnnotation], err.Error())
+	}
+	return nil
+}
+
+// validateSensitiveFields validates the JSONPath expressions of the
+// sensitive-fields annotation of the resource, if set.
+func (v *Validator) validateSensitiveFields(u *unstructured.Unstructured) error {
+	for _, expr := range redact.SensitiveFields(u) {
+		if _, err := jsonpath.Get(u.Object, expr); err != nil {
+			return field.Invalid(field.NewPath("metadata", "annotations").Key(redact.SensitiveFieldsAnnotation),
+				u.GetAnnotations()[redact.SensitiveFieldsAnnotation], err.Error())
+		}
+	}
+	return nil
+}
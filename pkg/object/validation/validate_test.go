@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/multierror"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
@@ -22,6 +23,7 @@ import (
 func TestValidate(t *testing.T) {
 	testCases := map[string]struct {
 		resources     []*unstructured.Unstructured
+		scope         inventory.Scope
 		expectedError error
 	}{
 		"missing kind": {
@@ -244,6 +246,183 @@ metadata:
 				},
 			),
 		},
+		"apply strategy must be supported": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    config.kubernetes.io/apply-strategy: create-once
+`,
+				),
+			},
+			expectedError: validation.NewError(
+				&field.Error{
+					Type:     field.ErrorTypeNotSupported,
+					Field:    "metadata.annotations[config.kubernetes.io/apply-strategy]",
+					BadValue: "create-once",
+					Detail:   `supported values: "apply", "create-only", "update-only", "replace"`,
+				},
+				object.ObjMetadata{
+					GroupKind: schema.GroupKind{
+						Group: "",
+						Kind:  "Secret",
+					},
+					Name:      "foo",
+					Namespace: "default",
+				},
+			),
+		},
+		"verify check must be supported by the kind": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    config.kubernetes.io/verify: job-complete
+`,
+				),
+			},
+			expectedError: validation.NewError(
+				&field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "metadata.annotations[config.kubernetes.io/verify]",
+					BadValue: "job-complete",
+					Detail:   `invalid "config.kubernetes.io/verify" annotation: "job-complete" is only supported on Job.batch`,
+				},
+				object.ObjMetadata{
+					GroupKind: schema.GroupKind{
+						Group: "",
+						Kind:  "Secret",
+					},
+					Name:      "foo",
+					Namespace: "default",
+				},
+			),
+		},
+		"sensitive fields must be valid JSONPath expressions": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    config.kubernetes.io/sensitive-fields: $.data.password,$.spec[
+`,
+				),
+			},
+			expectedError: validation.NewError(
+				&field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "metadata.annotations[config.kubernetes.io/sensitive-fields]",
+					BadValue: "$.data.password,$.spec[",
+					Detail:   "failed to evaluate jsonpath expression ($.spec[): unexpected end of file",
+				},
+				object.ObjMetadata{
+					GroupKind: schema.GroupKind{
+						Group: "",
+						Kind:  "ConfigMap",
+					},
+					Name:      "foo",
+					Namespace: "default",
+				},
+			),
+		},
+		"supported apply strategy is valid": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    config.kubernetes.io/apply-strategy: create-only
+`,
+				),
+			},
+		},
+		"objects inside the scope are valid": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+`,
+				),
+				testutil.Unstructured(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: foo
+`,
+				),
+			},
+			scope: inventory.Scope{
+				Namespaces:        []string{"default"},
+				ClusterGroupKinds: []schema.GroupKind{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}},
+			},
+		},
+		"objects outside the scope are invalid": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: kube-system
+`,
+				),
+				testutil.Unstructured(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: foo
+`,
+				),
+			},
+			scope: inventory.Scope{
+				Namespaces:        []string{"default"},
+				ClusterGroupKinds: []schema.GroupKind{},
+			},
+			expectedError: multierror.New(
+				validation.NewError(
+					&inventory.OutOfScopeError{
+						Reason: `namespace "kube-system" not in allowed namespaces`,
+					},
+					object.ObjMetadata{
+						GroupKind: schema.GroupKind{
+							Group: "",
+							Kind:  "ConfigMap",
+						},
+						Name:      "foo",
+						Namespace: "kube-system",
+					},
+				),
+				validation.NewError(
+					&inventory.OutOfScopeError{
+						Reason: `cluster-scoped kind "ClusterRole.rbac.authorization.k8s.io" not in allowed kinds`,
+					},
+					object.ObjMetadata{
+						GroupKind: schema.GroupKind{
+							Group: "rbac.authorization.k8s.io",
+							Kind:  "ClusterRole",
+						},
+						Name: "foo",
+					},
+				),
+			),
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -264,6 +443,7 @@ metadata:
 			validator := &validation.Validator{
 				Mapper:    mapper,
 				Collector: vCollector,
+				Scope:     tc.scope,
 			}
 			validator.Validate(tc.resources)
 			err = vCollector.ToError()
@@ -10,10 +10,10 @@ import (
 )
 
 // ResultErrorFromStats takes a stats object and returns either a ResultError or
-// nil depending on whether the stats reports that resources failed apply/prune/delete
-// or reconciliation.
+// nil depending on whether the stats reports that resources failed apply/prune/delete,
+// reconciliation or verification.
 func ResultErrorFromStats(s stats.Stats) error {
-	if s.FailedActuationSum() > 0 || s.FailedReconciliationSum() > 0 {
+	if s.FailedActuationSum() > 0 || s.FailedReconciliationSum() > 0 || s.FailedVerificationSum() > 0 {
 		return &ResultError{
 			Stats: s,
 		}
@@ -22,12 +22,25 @@ func ResultErrorFromStats(s stats.Stats) error {
 }
 
 // ResultError is returned from printers when the apply/destroy operations completed, but one or
-// more resources either failed apply/prune/delete, or failed to reconcile.
+// more resources either failed apply/prune/delete, failed to reconcile, or failed verification.
 type ResultError struct {
 	Stats stats.Stats
 }
 
 func (a *ResultError) Error() string {
+	if a.Stats.FailedVerificationSum() > 0 {
+		msg := fmt.Sprintf("%d resources failed verification", a.Stats.FailedVerificationSum())
+		if a.Stats.FailedActuationSum() > 0 || a.Stats.FailedReconciliationSum() > 0 {
+			msg = a.actuationError() + ", " + msg
+		}
+		return msg
+	}
+	return a.actuationError()
+}
+
+// actuationError returns the message for the actuation and reconciliation
+// failures.
+func (a *ResultError) actuationError() string {
 	switch {
 	case a.Stats.FailedActuationSum() > 0 && a.Stats.FailedReconciliationSum() > 0:
 		return fmt.Sprintf("%d resources failed, %d resources failed to reconcile before timeout",
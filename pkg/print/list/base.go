@@ -18,6 +18,7 @@ type Formatter interface {
 	FormatPruneEvent(pe event.PruneEvent) error
 	FormatDeleteEvent(de event.DeleteEvent) error
 	FormatWaitEvent(we event.WaitEvent) error
+	FormatVerifyEvent(ve event.VerifyEvent) error
 	FormatErrorEvent(ee event.ErrorEvent) error
 	FormatActionGroupEvent(
 		age event.ActionGroupEvent,
@@ -98,6 +99,10 @@ func (b *BaseListPrinter) Print(ch <-chan event.Event, previewStrategy common.Dr
 			if err := formatter.FormatWaitEvent(e.WaitEvent); err != nil {
 				return err
 			}
+		case event.VerifyType:
+			if err := formatter.FormatVerifyEvent(e.VerifyEvent); err != nil {
+				return err
+			}
 		case event.ActionGroupType:
 			if err := formatter.FormatActionGroupEvent(
 				e.ActionGroupEvent,
@@ -34,6 +34,7 @@ type countingFormatter struct {
 	pruneEvents      []event.PruneEvent
 	deleteEvents     []event.DeleteEvent
 	waitEvents       []event.WaitEvent
+	verifyEvents     []event.VerifyEvent
 	errorEvent       event.ErrorEvent
 	actionGroupEvent []event.ActionGroupEvent
 }
@@ -68,6 +69,11 @@ func (c *countingFormatter) FormatWaitEvent(e event.WaitEvent) error {
 	return nil
 }
 
+func (c *countingFormatter) FormatVerifyEvent(e event.VerifyEvent) error {
+	c.verifyEvents = append(c.verifyEvents, e)
+	return nil
+}
+
 func (c *countingFormatter) FormatErrorEvent(e event.ErrorEvent) error {
 	c.errorEvent = e
 	return nil
@@ -17,6 +17,7 @@ type Stats struct {
 	PruneStats  PruneStats
 	DeleteStats DeleteStats
 	WaitStats   WaitStats
+	VerifyStats VerifyStats
 }
 
 // FailedActuationSum returns the number of resources that failed actuation.
@@ -29,17 +30,28 @@ func (s *Stats) FailedReconciliationSum() int {
 	return s.WaitStats.Failed + s.WaitStats.Timeout
 }
 
+// FailedVerificationSum returns the number of resources that failed
+// verification.
+func (s *Stats) FailedVerificationSum() int {
+	return s.VerifyStats.Failed
+}
+
 // Handle updates the stats based on an event.
 func (s *Stats) Handle(e event.Event) {
 	switch e.Type {
 	case event.ApplyType:
 		s.ApplyStats.Inc(e.ApplyEvent.Status)
+		if e.ApplyEvent.Adopted != nil {
+			s.ApplyStats.IncAdopted()
+		}
 	case event.PruneType:
 		s.PruneStats.Inc(e.PruneEvent.Status)
 	case event.DeleteType:
 		s.DeleteStats.Inc(e.DeleteEvent.Status)
 	case event.WaitType:
 		s.WaitStats.Inc(e.WaitEvent.Status)
+	case event.VerifyType:
+		s.VerifyStats.Inc(e.VerifyEvent.Status)
 	}
 }
 
@@ -47,6 +59,9 @@ type ApplyStats struct {
 	Successful int
 	Skipped    int
 	Failed     int
+	// Adopted is the number of successfully applied objects which were
+	// taken over from another inventory, or from none.
+	Adopted int
 }
 
 func (a *ApplyStats) Inc(op event.ApplyEventStatus) {
@@ -66,6 +81,10 @@ func (a *ApplyStats) IncFailed() {
 	a.Failed++
 }
 
+func (a *ApplyStats) IncAdopted() {
+	a.Adopted++
+}
+
 func (a *ApplyStats) Sum() int {
 	return a.Successful + a.Skipped + a.Failed
 }
@@ -151,3 +170,26 @@ func (w *WaitStats) Inc(status event.WaitEventStatus) {
 func (w *WaitStats) Sum() int {
 	return w.Successful + w.Skipped + w.Failed + w.Timeout
 }
+
+type VerifyStats struct {
+	Successful int
+	Skipped    int
+	Failed     int
+}
+
+func (v *VerifyStats) Inc(status event.VerifyEventStatus) {
+	switch status {
+	case event.VerifySuccessful:
+		v.Successful++
+	case event.VerifySkipped:
+		v.Skipped++
+	case event.VerifyFailed:
+		v.Failed++
+	default:
+		panic(fmt.Errorf("invalid verify status %s", status.String()))
+	}
+}
+
+func (v *VerifyStats) Sum() int {
+	return v.Successful + v.Skipped + v.Failed
+}
@@ -64,6 +64,12 @@ func (ef *formatter) FormatApplyEvent(e event.ApplyEvent) error {
 	if e.Error != nil {
 		ef.print("%s apply %s: %s", resourceIDToString(gk, name),
 			strings.ToLower(e.Status.String()), e.Error.Error())
+	} else if e.Replaced {
+		ef.print("%s apply %s (replaced)", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()))
+	} else if e.Adopted != nil {
+		ef.print("%s apply %s (adopted from %s by %q)", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), previousOwnerString(e.Adopted), e.Adopted.NewOwner)
 	} else {
 		ef.print("%s apply %s", resourceIDToString(gk, name),
 			strings.ToLower(e.Status.String()))
@@ -106,8 +112,36 @@ func (ef *formatter) FormatDeleteEvent(e event.DeleteEvent) error {
 func (ef *formatter) FormatWaitEvent(e event.WaitEvent) error {
 	gk := e.Identifier.GroupKind
 	name := e.Identifier.Name
-	ef.print("%s reconcile %s", resourceIDToString(gk, name),
-		strings.ToLower(e.Status.String()))
+	switch {
+	case e.Terminating != nil && e.Terminating.FinalizersRemoved:
+		ef.print("%s reconcile %s: removed finalizers %q after terminating for %s", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Terminating.Finalizers, e.Terminating.Duration)
+	case e.Terminating != nil:
+		ef.print("%s reconcile %s: terminating for %s, waiting for finalizers %q", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Terminating.Duration, e.Terminating.Finalizers)
+	case e.Progress != nil && e.Progress.Message != "":
+		ef.print("%s reconcile %s: %s (%s elapsed)", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Progress.Message, e.Progress.Elapsed)
+	case e.Progress != nil:
+		ef.print("%s reconcile %s (%s elapsed)", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Progress.Elapsed)
+	default:
+		ef.print("%s reconcile %s", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()))
+	}
+	return nil
+}
+
+func (ef *formatter) FormatVerifyEvent(e event.VerifyEvent) error {
+	gk := e.Identifier.GroupKind
+	name := e.Identifier.Name
+	if e.Error != nil {
+		ef.print("%s verify %s (%s): %s", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Check, e.Error.Error())
+	} else {
+		ef.print("%s verify %s (%s)", resourceIDToString(gk, name),
+			strings.ToLower(e.Status.String()), e.Check)
+	}
 	return nil
 }
 
@@ -130,6 +164,8 @@ func (ef *formatter) FormatActionGroupEvent(
 		ef.print("delete phase %s", strings.ToLower(age.Status.String()))
 	case event.WaitAction:
 		ef.print("reconcile phase %s", strings.ToLower(age.Status.String()))
+	case event.VerifyAction:
+		ef.print("verify phase %s", strings.ToLower(age.Status.String()))
 	case event.InventoryAction:
 		ef.print("inventory update %s", strings.ToLower(age.Status.String()))
 	default:
@@ -141,8 +177,13 @@ func (ef *formatter) FormatActionGroupEvent(
 func (ef *formatter) FormatSummary(s stats.Stats) error {
 	if s.ApplyStats != (stats.ApplyStats{}) {
 		as := s.ApplyStats
-		ef.print("apply result: %d attempted, %d successful, %d skipped, %d failed",
-			as.Sum(), as.Successful, as.Skipped, as.Failed)
+		if as.Adopted > 0 {
+			ef.print("apply result: %d attempted, %d successful, %d skipped, %d failed, %d adopted",
+				as.Sum(), as.Successful, as.Skipped, as.Failed, as.Adopted)
+		} else {
+			ef.print("apply result: %d attempted, %d successful, %d skipped, %d failed",
+				as.Sum(), as.Successful, as.Skipped, as.Failed)
+		}
 	}
 	if s.PruneStats != (stats.PruneStats{}) {
 		ps := s.PruneStats
@@ -159,6 +200,11 @@ func (ef *formatter) FormatSummary(s stats.Stats) error {
 		ef.print("reconcile result: %d attempted, %d successful, %d skipped, %d failed, %d timed out",
 			ws.Sum(), ws.Successful, ws.Skipped, ws.Failed, ws.Timeout)
 	}
+	if s.VerifyStats != (stats.VerifyStats{}) {
+		vs := s.VerifyStats
+		ef.print("verify result: %d attempted, %d successful, %d skipped, %d failed",
+			vs.Sum(), vs.Successful, vs.Skipped, vs.Failed)
+	}
 	return nil
 }
 
@@ -171,6 +217,15 @@ func (ef *formatter) print(format string, a ...interface{}) {
 	_, _ = fmt.Fprintf(ef.ioStreams.Out, format+"\n", a...)
 }
 
+// previousOwnerString returns the quoted previous owner of an adopted object,
+// or "no inventory" if it was not owned.
+func previousOwnerString(ai *event.AdoptionInfo) string {
+	if ai.PreviousOwner == "" {
+		return "no inventory"
+	}
+	return fmt.Sprintf("%q", ai.PreviousOwner)
+}
+
 // resourceIDToString returns the string representation of a GroupKind and a resource name.
 func resourceIDToString(gk schema.GroupKind, name string) string {
 	return fmt.Sprintf("%s/%s", strings.ToLower(gk.String()), name)
@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -73,6 +75,48 @@ func TestFormatter_FormatApplyEvent(t *testing.T) {
 			},
 			expected: "deployment.apps/my-dep apply skipped: this is a test error",
 		},
+		"apply event with conflict error should display the conflicts once": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplyFailed,
+				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
+				Error: applyerror.NewApplyRunError(applyerror.NewApplyConflictError(
+					errors.New(`Apply failed with 1 conflict: conflict with "kubectl-edit" using apps/v1: .spec.replicas`))),
+			},
+			expected: `deployment.apps/my-dep apply failed: apply failed with 1 conflict: .spec.replicas (manager: "kubectl-edit")`,
+		},
+		"replaced apply event should display the replace": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("batch", "Job", "", "my-job"),
+				Replaced:   true,
+			},
+			expected: "job.batch/my-job apply successful (replaced)",
+		},
+		"adopted apply event should display the previous owner": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Adopted: &event.AdoptionInfo{
+					PreviousOwner: "other-id",
+					NewOwner:      "inv-id",
+				},
+			},
+			expected: `deployment.apps/my-dep apply successful (adopted from "other-id" by "inv-id")`,
+		},
+		"adopted apply event of unowned object": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Adopted: &event.AdoptionInfo{
+					NewOwner: "inv-id",
+				},
+			},
+			expected: `deployment.apps/my-dep apply successful (adopted from no inventory by "inv-id")`,
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -349,6 +393,58 @@ func TestFormatter_FormatWaitEvent(t *testing.T) {
 			},
 			expected: "deployment.apps/my-dep reconcile failed",
 		},
+		"resource reconcile timeout while terminating": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcileTimeout,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Terminating: &event.TerminatingInfo{
+					Finalizers: []string{"example.com/cleanup"},
+					Duration:   5 * time.Minute,
+				},
+			},
+			expected: `deployment.apps/my-dep reconcile timeout: terminating for 5m0s, waiting for finalizers ["example.com/cleanup"]`,
+		},
+		"resource reconcile pending after removing finalizers": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcilePending,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Terminating: &event.TerminatingInfo{
+					Finalizers:        []string{"example.com/cleanup"},
+					Duration:          10 * time.Minute,
+					FinalizersRemoved: true,
+				},
+			},
+			expected: `deployment.apps/my-dep reconcile pending: removed finalizers ["example.com/cleanup"] after terminating for 10m0s`,
+		},
+		"resource reconcile pending with progress": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcilePending,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Progress: &event.ProgressInfo{
+					Message: "Ready: 2/5",
+					Elapsed: 30 * time.Second,
+				},
+			},
+			expected: "deployment.apps/my-dep reconcile pending: Ready: 2/5 (30s elapsed)",
+		},
+		"resource reconcile pending with progress without message": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcilePending,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Progress: &event.ProgressInfo{
+					Elapsed: time.Minute,
+				},
+			},
+			expected: "deployment.apps/my-dep reconcile pending (1m0s elapsed)",
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -363,6 +459,47 @@ func TestFormatter_FormatWaitEvent(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatVerifyEvent(t *testing.T) {
+	testCases := map[string]struct {
+		previewStrategy common.DryRunStrategy
+		event           event.VerifyEvent
+		expected        string
+	}{
+		"job verified": {
+			previewStrategy: common.DryRunNone,
+			event: event.VerifyEvent{
+				GroupName:  "verify-0",
+				Status:     event.VerifySuccessful,
+				Identifier: createIdentifier("batch", "Job", "default", "migrate"),
+				Check:      "job-complete",
+			},
+			expected: "job.batch/migrate verify successful (job-complete)",
+		},
+		"service verification failed": {
+			previewStrategy: common.DryRunNone,
+			event: event.VerifyEvent{
+				GroupName:  "verify-0",
+				Status:     event.VerifyFailed,
+				Identifier: createIdentifier("", "Service", "default", "web"),
+				Check:      "http-get:8080/healthz",
+				Error:      fmt.Errorf("connection refused"),
+			},
+			expected: "service/web verify failed (http-get:8080/healthz): connection refused",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+			formatter := NewFormatter(ioStreams, tc.previewStrategy)
+			err := formatter.FormatVerifyEvent(tc.event)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expected, strings.TrimSpace(out.String()))
+		})
+	}
+}
+
 func TestFormatter_FormatValidationEvent(t *testing.T) {
 	testCases := map[string]struct {
 		previewStrategy common.DryRunStrategy
@@ -16,6 +16,7 @@
 //   - prune - PruneEvent
 //   - delete - DeleteEvent
 //   - wait - WaitEvent
+//   - verify - VerifyEvent
 //   - status - StatusEvent
 //   - summary - aggregate stats collected by the printer
 //
@@ -43,10 +44,10 @@
 // * error (string)  - a fatal error message
 //
 // Group events correspond to a group of events of the same type: apply, prune,
-// delete, or wait.
+// delete, wait, or verify.
 //
 // Group events have the following fields:
-// * action (string) - One of: "Apply", "Prune", "Delete", or "Wait".
+// * action (string) - One of: "Apply", "Prune", "Delete", "Wait", or "Verify".
 // * status (string) - One of: "Started" or "Finished"
 // * timestamp (string) - ISO-8601 format
 // * type (string) - "group"
@@ -66,6 +67,20 @@
 //   - type (string) - "apply", "prune", "delete", or "wait"
 //   - error (string, optional) - A non-fatal error message specific to this object
 //
+// Verify events correspond to a post-apply verification check performed on a
+// single object, as requested by its config.kubernetes.io/verify annotation.
+//
+// Verify events have the following fields:
+//   - group (string, optional) - The object's API group.
+//   - kind (string) - The object's kind.
+//   - name (string) - The object's name.
+//   - namespace (string, optional) - The object's namespace.
+//   - status (string) - One of: "Successful", "Skipped", or "Failed".
+//   - check (string) - The verification check, e.g. "job-complete".
+//   - timestamp (string) - ISO-8601 format
+//   - type (string) - "verify"
+//   - error (string, optional) - A non-fatal error message specific to this object
+//
 // Status types are asynchronous events that correspond to status updates for
 // a specific object.
 //
@@ -83,10 +98,10 @@
 // Summary types are a meta-event sent by the printer to summarize some stats
 // that have been collected from other events. For these events, the action
 // field corresponds to the event type being summarized: Apply, Prune, Delete,
-// and Wait.
+// Wait, and Verify.
 //
 // Summary events have the following fields:
-// * action (string) - One of: "Apply", "Prune", "Delete", or "Wait".
+// * action (string) - One of: "Apply", "Prune", "Delete", "Wait", or "Verify".
 // * count (number) - Total number of objects attempted for this action
 // * successful (number) - Number of objects for which the action was successful.
 // * skipped (number) - Number of objects for which the action was skipped.
@@ -5,10 +5,12 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -54,6 +56,26 @@ func (jf *formatter) FormatApplyEvent(e event.ApplyEvent) error {
 	eventInfo := jf.baseResourceEvent(e.Identifier)
 	if e.Error != nil {
 		eventInfo["error"] = e.Error.Error()
+		var conflictErr *applyerror.ApplyConflictError
+		if errors.As(e.Error, &conflictErr) {
+			conflicts := make([]map[string]interface{}, 0, len(conflictErr.Conflicts))
+			for _, c := range conflictErr.Conflicts {
+				conflicts = append(conflicts, map[string]interface{}{
+					"field":   c.Field,
+					"manager": c.Manager,
+				})
+			}
+			eventInfo["conflicts"] = conflicts
+		}
+	}
+	if e.Replaced {
+		eventInfo["replaced"] = true
+	}
+	if e.Adopted != nil {
+		eventInfo["adopted"] = map[string]interface{}{
+			"previousOwner": e.Adopted.PreviousOwner,
+			"newOwner":      e.Adopted.NewOwner,
+		}
 	}
 	eventInfo["status"] = e.Status.String()
 	return jf.printEvent("apply", eventInfo)
@@ -91,9 +113,32 @@ func (jf *formatter) FormatDeleteEvent(e event.DeleteEvent) error {
 func (jf *formatter) FormatWaitEvent(e event.WaitEvent) error {
 	eventInfo := jf.baseResourceEvent(e.Identifier)
 	eventInfo["status"] = e.Status.String()
+	if e.Terminating != nil {
+		eventInfo["terminating"] = map[string]interface{}{
+			"finalizers":        e.Terminating.Finalizers,
+			"duration":          e.Terminating.Duration.String(),
+			"finalizersRemoved": e.Terminating.FinalizersRemoved,
+		}
+	}
+	if e.Progress != nil {
+		eventInfo["progress"] = map[string]interface{}{
+			"message": e.Progress.Message,
+			"elapsed": e.Progress.Elapsed.String(),
+		}
+	}
 	return jf.printEvent("wait", eventInfo)
 }
 
+func (jf *formatter) FormatVerifyEvent(e event.VerifyEvent) error {
+	eventInfo := jf.baseResourceEvent(e.Identifier)
+	if e.Error != nil {
+		eventInfo["error"] = e.Error.Error()
+	}
+	eventInfo["status"] = e.Status.String()
+	eventInfo["check"] = e.Check
+	return jf.printEvent("verify", eventInfo)
+}
+
 func (jf *formatter) FormatErrorEvent(e event.ErrorEvent) error {
 	return jf.printEvent("error", map[string]interface{}{
 		"error": e.Err.Error(),
@@ -119,6 +164,9 @@ func (jf *formatter) FormatActionGroupEvent(
 			content["successful"] = as.Successful
 			content["skipped"] = as.Skipped
 			content["failed"] = as.Failed
+			if as.Adopted > 0 {
+				content["adopted"] = as.Adopted
+			}
 		}
 	case event.PruneAction:
 		if age.Status == event.Finished {
@@ -145,6 +193,14 @@ func (jf *formatter) FormatActionGroupEvent(
 			content["failed"] = ws.Failed
 			content["timeout"] = ws.Timeout
 		}
+	case event.VerifyAction:
+		if age.Status == event.Finished {
+			vs := s.VerifyStats
+			content["count"] = vs.Sum()
+			content["successful"] = vs.Successful
+			content["skipped"] = vs.Skipped
+			content["failed"] = vs.Failed
+		}
 	case event.InventoryAction:
 		// no extra content
 	default:
@@ -157,13 +213,17 @@ func (jf *formatter) FormatActionGroupEvent(
 func (jf *formatter) FormatSummary(s stats.Stats) error {
 	if s.ApplyStats != (stats.ApplyStats{}) {
 		as := s.ApplyStats
-		err := jf.printEvent("summary", map[string]interface{}{
+		content := map[string]interface{}{
 			"action":     event.ApplyAction.String(),
 			"count":      as.Sum(),
 			"successful": as.Successful,
 			"skipped":    as.Skipped,
 			"failed":     as.Failed,
-		})
+		}
+		if as.Adopted > 0 {
+			content["adopted"] = as.Adopted
+		}
+		err := jf.printEvent("summary", content)
 		if err != nil {
 			return err
 		}
@@ -208,6 +268,19 @@ func (jf *formatter) FormatSummary(s stats.Stats) error {
 			return err
 		}
 	}
+	if s.VerifyStats != (stats.VerifyStats{}) {
+		vs := s.VerifyStats
+		err := jf.printEvent("summary", map[string]interface{}{
+			"action":     event.VerifyAction.String(),
+			"count":      vs.Sum(),
+			"successful": vs.Successful,
+			"skipped":    vs.Skipped,
+			"failed":     vs.Failed,
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
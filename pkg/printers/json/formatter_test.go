@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -127,6 +128,79 @@ func TestFormatter_FormatApplyEvent(t *testing.T) {
 				},
 			},
 		},
+		"resource apply conflict": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplyFailed,
+				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
+				Error: applyerror.NewApplyRunError(applyerror.NewApplyConflictError(
+					errors.New(`Apply failed with 1 conflict: conflict with "kubectl-edit" using apps/v1: .spec.replicas`))),
+			},
+			expected: []map[string]interface{}{
+				{
+					"group":     "apps",
+					"kind":      "Deployment",
+					"name":      "my-dep",
+					"namespace": "",
+					"status":    "Failed",
+					"timestamp": "",
+					"type":      "apply",
+					"error":     `apply failed with 1 conflict: .spec.replicas (manager: "kubectl-edit")`,
+					"conflicts": []interface{}{
+						map[string]interface{}{
+							"field":   ".spec.replicas",
+							"manager": "kubectl-edit",
+						},
+					},
+				},
+			},
+		},
+		"resource apply replaced": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("batch", "Job", "", "my-job"),
+				Replaced:   true,
+			},
+			expected: []map[string]interface{}{
+				{
+					"group":     "batch",
+					"kind":      "Job",
+					"name":      "my-job",
+					"namespace": "",
+					"status":    "Successful",
+					"timestamp": "",
+					"type":      "apply",
+					"replaced":  true,
+				},
+			},
+		},
+		"resource apply adopted": {
+			previewStrategy: common.DryRunServer,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Adopted: &event.AdoptionInfo{
+					PreviousOwner: "other-id",
+					NewOwner:      "inv-id",
+				},
+			},
+			expected: []map[string]interface{}{
+				{
+					"group":     "apps",
+					"kind":      "Deployment",
+					"name":      "my-dep",
+					"namespace": "default",
+					"status":    "Successful",
+					"timestamp": "",
+					"type":      "apply",
+					"adopted": map[string]interface{}{
+						"previousOwner": "other-id",
+						"newOwner":      "inv-id",
+					},
+				},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -506,6 +580,57 @@ func TestFormatter_FormatWaitEvent(t *testing.T) {
 				"type":      "wait",
 			},
 		},
+		"resource reconcile timeout while terminating": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcileTimeout,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Terminating: &event.TerminatingInfo{
+					Finalizers: []string{"example.com/cleanup"},
+					Duration:   5 * time.Minute,
+				},
+			},
+			expected: map[string]interface{}{
+				"group":     "apps",
+				"kind":      "Deployment",
+				"name":      "my-dep",
+				"namespace": "default",
+				"status":    "Timeout",
+				"terminating": map[string]interface{}{
+					"finalizers":        []interface{}{"example.com/cleanup"},
+					"duration":          "5m0s",
+					"finalizersRemoved": false,
+				},
+				"timestamp": "",
+				"type":      "wait",
+			},
+		},
+		"resource reconcile pending with progress": {
+			previewStrategy: common.DryRunNone,
+			event: event.WaitEvent{
+				GroupName:  "wait-1",
+				Status:     event.ReconcilePending,
+				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+				Progress: &event.ProgressInfo{
+					Message: "Ready: 2/5",
+					Elapsed: 30 * time.Second,
+				},
+			},
+			expected: map[string]interface{}{
+				"group":     "apps",
+				"kind":      "Deployment",
+				"name":      "my-dep",
+				"namespace": "default",
+				"status":    "Pending",
+				"progress": map[string]interface{}{
+					"message": "Ready: 2/5",
+					"elapsed": "30s",
+				},
+				"timestamp": "",
+				"type":      "wait",
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -520,6 +645,66 @@ func TestFormatter_FormatWaitEvent(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatVerifyEvent(t *testing.T) {
+	testCases := map[string]struct {
+		previewStrategy common.DryRunStrategy
+		event           event.VerifyEvent
+		expected        map[string]interface{}
+	}{
+		"job verified": {
+			previewStrategy: common.DryRunNone,
+			event: event.VerifyEvent{
+				GroupName:  "verify-0",
+				Status:     event.VerifySuccessful,
+				Identifier: createIdentifier("batch", "Job", "default", "migrate"),
+				Check:      "job-complete",
+			},
+			expected: map[string]interface{}{
+				"group":     "batch",
+				"kind":      "Job",
+				"name":      "migrate",
+				"namespace": "default",
+				"status":    "Successful",
+				"check":     "job-complete",
+				"timestamp": "",
+				"type":      "verify",
+			},
+		},
+		"service verification failed": {
+			previewStrategy: common.DryRunNone,
+			event: event.VerifyEvent{
+				GroupName:  "verify-0",
+				Status:     event.VerifyFailed,
+				Identifier: createIdentifier("", "Service", "default", "web"),
+				Check:      "http-get:8080/healthz",
+				Error:      errors.New("connection refused"),
+			},
+			expected: map[string]interface{}{
+				"group":     "",
+				"kind":      "Service",
+				"name":      "web",
+				"namespace": "default",
+				"status":    "Failed",
+				"check":     "http-get:8080/healthz",
+				"error":     "connection refused",
+				"timestamp": "",
+				"type":      "verify",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+			formatter := NewFormatter(ioStreams, tc.previewStrategy)
+			err := formatter.FormatVerifyEvent(tc.event)
+			assert.NoError(t, err)
+
+			assertOutput(t, tc.expected, out.String())
+		})
+	}
+}
+
 func TestFormatter_FormatActionGroupEvent(t *testing.T) {
 	testCases := map[string]struct {
 		previewStrategy common.DryRunStrategy
@@ -862,6 +1047,26 @@ func TestFormatter_FormatSummary(t *testing.T) {
 				},
 			},
 		},
+		"apply with adoptions": {
+			statsCollector: stats.Stats{
+				ApplyStats: stats.ApplyStats{
+					Successful: 3,
+					Adopted:    2,
+				},
+			},
+			expected: []map[string]interface{}{
+				{
+					"action":     "Apply",
+					"count":      float64(3),
+					"successful": float64(3),
+					"skipped":    float64(0),
+					"failed":     float64(0),
+					"adopted":    float64(2),
+					"timestamp":  nowStr,
+					"type":       "summary",
+				},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -0,0 +1,74 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package printers
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/print/list"
+	"sigs.k8s.io/cli-utils/pkg/printers/events"
+	"sigs.k8s.io/cli-utils/pkg/printers/json"
+	"sigs.k8s.io/cli-utils/pkg/printers/table"
+)
+
+// PrintPlan prints the passed action groups with the printer of the passed
+// type, before any task runs. This makes a plan look the same as the output
+// of a run. The table printer prints the objects without status, the other
+// printers print an event with pending status for each object.
+func PrintPlan(printerType string, ioStreams genericclioptions.IOStreams, actionGroups []event.ActionGroup) error {
+	switch printerType {
+	case TablePrinter:
+		ch := make(chan event.Event, 1)
+		ch <- event.Event{
+			Type: event.InitType,
+			InitEvent: event.InitEvent{
+				ActionGroups: actionGroups,
+			},
+		}
+		close(ch)
+		printer := &table.Printer{
+			IOStreams: ioStreams,
+		}
+		return printer.Print(ch, common.DryRunNone, false)
+	case JSONPrinter:
+		return formatPlan(json.NewFormatter(ioStreams, common.DryRunNone), actionGroups)
+	default:
+		return formatPlan(events.NewFormatter(ioStreams, common.DryRunNone), actionGroups)
+	}
+}
+
+// formatPlan formats an event with pending status for each object of the
+// passed action groups.
+func formatPlan(formatter list.Formatter, actionGroups []event.ActionGroup) error {
+	for _, ag := range actionGroups {
+		for _, id := range ag.Identifiers {
+			var err error
+			switch ag.Action {
+			case event.ApplyAction:
+				err = formatter.FormatApplyEvent(event.ApplyEvent{
+					GroupName:  ag.Name,
+					Identifier: id,
+					Status:     event.ApplyPending,
+				})
+			case event.PruneAction:
+				err = formatter.FormatPruneEvent(event.PruneEvent{
+					GroupName:  ag.Name,
+					Identifier: id,
+					Status:     event.PrunePending,
+				})
+			case event.DeleteAction:
+				err = formatter.FormatDeleteEvent(event.DeleteEvent{
+					GroupName:  ag.Name,
+					Identifier: id,
+					Status:     event.DeletePending,
+				})
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
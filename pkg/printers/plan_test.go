@@ -0,0 +1,67 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package printers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestPrintPlan(t *testing.T) {
+	deploymentID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Namespace: "default",
+		Name:      "foo",
+	}
+	actionGroups := []event.ActionGroup{
+		{Name: "apply-0", Action: event.ApplyAction, Identifiers: object.ObjMetadataSet{deploymentID}},
+		{Name: "wait-0", Action: event.WaitAction, Identifiers: object.ObjMetadataSet{deploymentID}},
+		{Name: "delete-0", Action: event.DeleteAction, Identifiers: object.ObjMetadataSet{deploymentID}},
+	}
+
+	tests := map[string]struct {
+		printerType string
+		expected    []string
+	}{
+		"events": {
+			printerType: EventsPrinter,
+			expected: []string{
+				"deployment.apps/foo apply pending",
+				"deployment.apps/foo delete pending",
+			},
+		},
+		"json": {
+			printerType: JSONPrinter,
+			expected: []string{
+				`"type":"apply"`,
+				`"status":"Pending"`,
+				`"type":"delete"`,
+			},
+		},
+		"table": {
+			printerType: TablePrinter,
+			expected: []string{
+				"NAMESPACE",
+				"Deployment/foo",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			ioStreams := genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}
+			require.NoError(t, PrintPlan(tc.printerType, ioStreams, actionGroups))
+			for _, s := range tc.expected {
+				assert.Contains(t, out.String(), s)
+			}
+		})
+	}
+}
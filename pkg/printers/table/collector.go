@@ -25,8 +25,8 @@ func newResourceStateCollector(resourceGroups []event.ActionGroup) *resourceStat
 	for _, group := range resourceGroups {
 		action := group.Action
 		// Keep the action that describes the operation for the resource
-		// rather than that we will wait for it.
-		if action == event.WaitAction {
+		// rather than that we will wait for or verify it.
+		if action == event.WaitAction || action == event.VerifyAction {
 			continue
 		}
 		for _, identifier := range group.Identifiers {
@@ -200,6 +200,8 @@ func (r *resourceStateCollector) processEvent(ev event.Event) error {
 		r.processDeleteEvent(ev.DeleteEvent)
 	case event.WaitType:
 		r.processWaitEvent(ev.WaitEvent)
+	case event.VerifyType:
+		r.processVerifyEvent(ev.VerifyEvent)
 	case event.ErrorType:
 		return ev.ErrorEvent.Err
 	}
@@ -260,6 +262,9 @@ func (r *resourceStateCollector) processApplyEvent(e event.ApplyEvent) {
 	}
 	previous.ApplyStatus = e.Status
 	r.stats.ApplyStats.Inc(e.Status)
+	if e.Adopted != nil {
+		r.stats.ApplyStats.IncAdopted()
+	}
 }
 
 // processPruneEvent handles event related to prune operations.
@@ -307,6 +312,21 @@ func (r *resourceStateCollector) processWaitEvent(e event.WaitEvent) {
 	r.stats.WaitStats.Inc(e.Status)
 }
 
+// processVerifyEvent handles events related to verification checks.
+func (r *resourceStateCollector) processVerifyEvent(e event.VerifyEvent) {
+	identifier := e.Identifier
+	klog.V(7).Infof("processing verify event for %s", identifier)
+	previous, found := r.resourceInfos[identifier]
+	if !found {
+		klog.V(4).Infof("%s verify event not found in ResourceInfos; no processing", identifier)
+		return
+	}
+	if e.Error != nil {
+		previous.Error = e.Error
+	}
+	r.stats.VerifyStats.Inc(e.Status)
+}
+
 // ResourceState contains the latest state for all the resources.
 type ResourceState struct {
 	resourceInfos ResourceInfos
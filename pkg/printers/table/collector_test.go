@@ -96,6 +96,27 @@ func TestResourceStateCollector_New(t *testing.T) {
 				},
 			},
 		},
+		"verified resources keep the apply action": {
+			resourceGroups: []event.ActionGroup{
+				{
+					Action: event.ApplyAction,
+					Identifiers: object.ObjMetadataSet{
+						depID,
+					},
+				},
+				{
+					Action: event.VerifyAction,
+					Identifiers: object.ObjMetadataSet{
+						depID,
+					},
+				},
+			},
+			resourceInfos: map[object.ObjMetadata]*resourceInfo{
+				depID: {
+					ResourceAction: event.ApplyAction,
+				},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -275,3 +296,34 @@ func getID(e event.StatusEvent) (object.ObjMetadata, bool) {
 	}
 	return e.Identifier, true
 }
+
+func TestResourceStateCollector_ProcessVerifyEvent(t *testing.T) {
+	rsc := newResourceStateCollector([]event.ActionGroup{
+		{
+			Action:      event.ApplyAction,
+			Identifiers: object.ObjMetadataSet{depID, depID2},
+		},
+	})
+	verifyErr := errors.New("job not complete")
+	err := rsc.processEvent(event.Event{
+		Type: event.VerifyType,
+		VerifyEvent: event.VerifyEvent{
+			Identifier: depID,
+			Status:     event.VerifySuccessful,
+		},
+	})
+	assert.NoError(t, err)
+	err = rsc.processEvent(event.Event{
+		Type: event.VerifyType,
+		VerifyEvent: event.VerifyEvent{
+			Identifier: depID2,
+			Status:     event.VerifyFailed,
+			Error:      verifyErr,
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, rsc.stats.VerifyStats.Successful)
+	assert.Equal(t, 1, rsc.stats.VerifyStats.Failed)
+	assert.Equal(t, verifyErr, rsc.resourceInfos[depID2].Error)
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+// Event returns the event with the sensitive values of the objects it
+// contains redacted. The objects are copied before redaction, so the objects
+// of the passed event are not modified.
+func Event(e event.Event) event.Event {
+	switch e.Type {
+	case event.ApplyType:
+		e.ApplyEvent.Resource = Object(e.ApplyEvent.Resource)
+	case event.PruneType:
+		e.PruneEvent.Object = Object(e.PruneEvent.Object)
+	case event.DeleteType:
+		e.DeleteEvent.Object = Object(e.DeleteEvent.Object)
+	case event.StatusType:
+		e.StatusEvent.Resource = Object(e.StatusEvent.Resource)
+		if info := e.StatusEvent.PollResourceInfo; info != nil && IsSensitive(info.Resource) {
+			redactedInfo := *info
+			redactedInfo.Resource = Object(info.Resource)
+			e.StatusEvent.PollResourceInfo = &redactedInfo
+		}
+	}
+	return e
+}
+
+// Events returns a channel which forwards the events from the passed channel,
+// with the sensitive values of their objects redacted. The returned channel
+// is closed when the passed channel is closed.
+func Events(in <-chan event.Event) <-chan event.Event {
+	out := make(chan event.Event)
+	go func() {
+		defer close(out)
+		for e := range in {
+			out <- Event(e)
+		}
+	}()
+	return out
+}
@@ -0,0 +1,272 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redact masks sensitive values in object content, so that they are
+// not exposed by events, printers, diffs, or verbose logging.
+//
+// The values of the data and stringData fields of Secrets are always
+// sensitive. Other fields can be marked as sensitive with the
+// config.kubernetes.io/sensitive-fields annotation, which takes a
+// comma-separated list of JSONPath expressions. For example:
+//
+//	config.kubernetes.io/sensitive-fields: $.spec.password,$.spec.tls.key
+//
+// Fields can also be registered with AddSensitiveFields, which keeps them
+// client-side instead of applying them to the cluster with the objects.
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/jsonpath"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const (
+	// SensitiveFieldsAnnotation lists the JSONPath expressions of the fields
+	// of an object whose values must be redacted, separated by commas.
+	SensitiveFieldsAnnotation = "config.kubernetes.io/sensitive-fields"
+
+	// Placeholder replaces the redacted values.
+	Placeholder = "***"
+	// PlaceholderBefore replaces a redacted value which changed, in the
+	// older version of the object.
+	PlaceholderBefore = "*** (before)"
+	// PlaceholderAfter replaces a redacted value which changed, in the newer
+	// version of the object.
+	PlaceholderAfter = "*** (after)"
+)
+
+var secretGK = schema.GroupKind{Kind: "Secret"}
+
+var (
+	// localFieldsMu protects localFields
+	localFieldsMu sync.RWMutex
+	// localFields are the sensitive fields registered with
+	// AddSensitiveFields, by object.
+	localFields = make(map[object.ObjMetadata][]string)
+)
+
+// secretFields are the fields of a Secret whose values are redacted.
+var secretFields = []string{"data", "stringData"}
+
+//nolint:gochecknoinits
+func init() {
+	// Keep the verbose logs of the jsonpath package, which this package uses
+	// to redact the sensitive fields, free of sensitive values.
+	jsonpath.IsSensitive = func(obj map[string]interface{}) bool {
+		return IsSensitive(&unstructured.Unstructured{Object: obj})
+	}
+}
+
+// AddSensitiveFields registers JSONPath expressions of sensitive fields of
+// the object with the passed identifier, in addition to the fields of the
+// sensitive-fields annotation. The fields apply to every version of the
+// object, including the ones read from the cluster, for the lifetime of the
+// process.
+func AddSensitiveFields(id object.ObjMetadata, exprs ...string) {
+	localFieldsMu.Lock()
+	defer localFieldsMu.Unlock()
+
+	localFields[id] = uniqueStrings(append(localFields[id], exprs...))
+}
+
+// SensitiveFields returns the JSONPath expressions listed in the
+// sensitive-fields annotation of the object, if any, followed by the ones
+// registered with AddSensitiveFields.
+func SensitiveFields(obj *unstructured.Unstructured) []string {
+	var exprs []string
+	if value, found := obj.GetAnnotations()[SensitiveFieldsAnnotation]; found {
+		for _, expr := range strings.Split(value, ",") {
+			expr = strings.TrimSpace(expr)
+			if expr != "" {
+				exprs = append(exprs, expr)
+			}
+		}
+	}
+	localFieldsMu.RLock()
+	defer localFieldsMu.RUnlock()
+
+	if len(localFields) == 0 {
+		return exprs
+	}
+	return uniqueStrings(append(exprs, localFields[object.UnstructuredToObjMetadata(obj)]...))
+}
+
+// IsSensitive returns true if the object is a Secret or lists sensitive fields.
+func IsSensitive(obj *unstructured.Unstructured) bool {
+	if obj == nil {
+		return false
+	}
+	return obj.GroupVersionKind().GroupKind() == secretGK || len(SensitiveFields(obj)) > 0
+}
+
+// Object returns a copy of the object with its sensitive values replaced by
+// the Placeholder. If the object has no sensitive values, the object itself
+// is returned.
+func Object(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if !IsSensitive(obj) {
+		return obj
+	}
+	redacted, _ := Pair(obj, nil)
+	return redacted
+}
+
+// Pair returns copies of two versions of the same object, with their
+// sensitive values redacted. Values which differ between the versions are
+// replaced by the PlaceholderBefore and PlaceholderAfter, so that the change
+// is still visible in a diff. Either version may be nil.
+func Pair(from, to *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured) {
+	if !IsSensitive(from) && !IsSensitive(to) {
+		return from, to
+	}
+	if from != nil {
+		from = from.DeepCopy()
+	}
+	if to != nil {
+		to = to.DeepCopy()
+	}
+	if isSecret(from) || isSecret(to) {
+		for _, field := range secretFields {
+			redactMapValues(nestedMap(from, field), nestedMap(to, field))
+		}
+	}
+	exprs := append(sensitiveFields(from), sensitiveFields(to)...)
+	if len(exprs) > 0 {
+		for _, expr := range uniqueStrings(exprs) {
+			redactPath(from, to, expr)
+		}
+		from = normalize(from)
+		to = normalize(to)
+	}
+	return from, to
+}
+
+// redactMapValues replaces the values of both maps, keeping the keys.
+func redactMapValues(from, to map[string]interface{}) {
+	for key, fromValue := range from {
+		toValue, found := to[key]
+		switch {
+		case !found:
+			from[key] = Placeholder
+		case !reflect.DeepEqual(fromValue, toValue):
+			from[key] = PlaceholderBefore
+			to[key] = PlaceholderAfter
+		default:
+			from[key] = Placeholder
+			to[key] = Placeholder
+		}
+	}
+	for key := range to {
+		if _, found := from[key]; !found {
+			to[key] = Placeholder
+		}
+	}
+}
+
+// redactPath replaces the values matching the JSONPath expression in both
+// objects. Errors are logged and the expression is skipped, because the
+// annotation is validated before apply.
+func redactPath(from, to *unstructured.Unstructured, expr string) {
+	fromValues, err := getValues(from, expr)
+	if err != nil {
+		klog.Warningf("failed to redact sensitive field (expression: %q): %v", expr, err)
+		return
+	}
+	toValues, err := getValues(to, expr)
+	if err != nil {
+		klog.Warningf("failed to redact sensitive field (expression: %q): %v", expr, err)
+		return
+	}
+	fromPlaceholder, toPlaceholder := Placeholder, Placeholder
+	if len(fromValues) > 0 && len(toValues) > 0 && !reflect.DeepEqual(fromValues, toValues) {
+		fromPlaceholder, toPlaceholder = PlaceholderBefore, PlaceholderAfter
+	}
+	setValues(from, expr, fromPlaceholder, len(fromValues))
+	setValues(to, expr, toPlaceholder, len(toValues))
+}
+
+func getValues(obj *unstructured.Unstructured, expr string) ([]interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return jsonpath.Get(obj.Object, expr)
+}
+
+func setValues(obj *unstructured.Unstructured, expr, placeholder string, count int) {
+	if obj == nil || count == 0 {
+		return
+	}
+	if _, err := jsonpath.Set(obj.Object, expr, placeholder); err != nil {
+		klog.Warningf("failed to redact sensitive field (expression: %q): %v", expr, err)
+	}
+}
+
+// normalize round-trips the object through JSON, because jsonpath.Set
+// leaves numbers with types that unstructured objects do not support.
+func normalize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		klog.Warningf("failed to normalize redacted object: %v", err)
+		return obj
+	}
+	var content map[string]interface{}
+	if err := utiljson.Unmarshal(data, &content); err != nil {
+		klog.Warningf("failed to normalize redacted object: %v", err)
+		return obj
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+func isSecret(obj *unstructured.Unstructured) bool {
+	return obj != nil && obj.GroupVersionKind().GroupKind() == secretGK
+}
+
+func nestedMap(obj *unstructured.Unstructured, field string) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+	m, _ := obj.Object[field].(map[string]interface{})
+	return m
+}
+
+func sensitiveFields(obj *unstructured.Unstructured) []string {
+	if obj == nil {
+		return nil
+	}
+	return SensitiveFields(obj)
+}
+
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]struct{}, len(in))
+	var out []string
+	for _, s := range in {
+		if _, found := seen[s]; found {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out
+}
+
+// YamlStringer delays redaction and YAML marshalling for logging until
+// String() is called.
+type YamlStringer struct {
+	O *unstructured.Unstructured
+}
+
+// String redacts the wrapped object and marshals it to a YAML string.
+func (ys YamlStringer) String() string {
+	return object.YamlStringer{O: Object(ys.O)}.String()
+}
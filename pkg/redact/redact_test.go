@@ -0,0 +1,247 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/jsonpath"
+	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var secret = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+data:
+  password: c2VjcmV0
+  user: YWRtaW4=
+stringData:
+  token: abc
+type: Opaque
+`
+
+var configMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  mode: debug
+`
+
+var deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  annotations:
+    config.kubernetes.io/sensitive-fields: $.spec.template.spec.containers[*].env[?(@.name=='API_KEY')].value
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        env:
+        - name: API_KEY
+          value: key-1
+        - name: LOG_LEVEL
+          value: info
+`
+
+func TestObject(t *testing.T) {
+	testCases := map[string]struct {
+		manifest string
+		expected map[string]interface{}
+	}{
+		"secret data is redacted": {
+			manifest: secret,
+			expected: map[string]interface{}{
+				"data": map[string]interface{}{
+					"password": Placeholder,
+					"user":     Placeholder,
+				},
+				"stringData": map[string]interface{}{
+					"token": Placeholder,
+				},
+				"type": "Opaque",
+			},
+		},
+		"configmap data is not redacted": {
+			manifest: configMap,
+			expected: map[string]interface{}{
+				"data": map[string]interface{}{
+					"mode": "debug",
+				},
+			},
+		},
+		"sensitive fields are redacted": {
+			manifest: deployment,
+			expected: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "app",
+									"image": "app:v1",
+									"env": []interface{}{
+										map[string]interface{}{
+											"name":  "API_KEY",
+											"value": Placeholder,
+										},
+										map[string]interface{}{
+											"name":  "LOG_LEVEL",
+											"value": "info",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := testutil.Unstructured(t, tc.manifest)
+			original := obj.DeepCopy()
+
+			redacted := Object(obj)
+
+			for field, value := range tc.expected {
+				testutil.AssertEqual(t, value, redacted.Object[field], "field %q", field)
+			}
+			// the passed object must not be modified
+			testutil.AssertEqual(t, original, obj)
+			// the redacted object must still be a valid unstructured object
+			assert.NotPanics(t, func() { redacted.DeepCopy() })
+		})
+	}
+}
+
+func TestPair(t *testing.T) {
+	from := testutil.Unstructured(t, secret)
+	to := testutil.Unstructured(t, secret)
+	assert.NoError(t, unstructured.SetNestedField(to.Object, "bmV3", "data", "password"))
+	assert.NoError(t, unstructured.SetNestedField(to.Object, "bmV3", "data", "new"))
+	unstructured.RemoveNestedField(to.Object, "data", "user")
+
+	redactedFrom, redactedTo := Pair(from, to)
+
+	testutil.AssertEqual(t, map[string]interface{}{
+		"password": PlaceholderBefore,
+		"user":     Placeholder,
+	}, redactedFrom.Object["data"])
+	testutil.AssertEqual(t, map[string]interface{}{
+		"password": PlaceholderAfter,
+		"new":      Placeholder,
+	}, redactedTo.Object["data"])
+}
+
+func TestPair_SensitiveFields(t *testing.T) {
+	from := testutil.Unstructured(t, deployment)
+	to := testutil.Unstructured(t, deployment)
+	env, _, _ := unstructured.NestedSlice(to.Object, "spec", "template", "spec", "containers")
+	env[0].(map[string]interface{})["env"].([]interface{})[0].(map[string]interface{})["value"] = "key-2"
+	assert.NoError(t, unstructured.SetNestedSlice(to.Object, env, "spec", "template", "spec", "containers"))
+
+	redactedFrom, redactedTo := Pair(from, to)
+
+	apiKey := func(obj *unstructured.Unstructured) interface{} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		return containers[0].(map[string]interface{})["env"].([]interface{})[0].(map[string]interface{})["value"]
+	}
+	testutil.AssertEqual(t, PlaceholderBefore, apiKey(redactedFrom))
+	testutil.AssertEqual(t, PlaceholderAfter, apiKey(redactedTo))
+}
+
+func TestPair_Nil(t *testing.T) {
+	to := testutil.Unstructured(t, secret)
+
+	redactedFrom, redactedTo := Pair(nil, to)
+
+	assert.Nil(t, redactedFrom)
+	testutil.AssertEqual(t, map[string]interface{}{
+		"password": Placeholder,
+		"user":     Placeholder,
+	}, redactedTo.Object["data"])
+}
+
+func TestJSONPathIsSensitive(t *testing.T) {
+	assert.True(t, jsonpath.IsSensitive(testutil.Unstructured(t, secret).Object))
+	assert.True(t, jsonpath.IsSensitive(testutil.Unstructured(t, deployment).Object))
+	assert.False(t, jsonpath.IsSensitive(testutil.Unstructured(t, configMap).Object))
+}
+
+func TestEvent(t *testing.T) {
+	obj := testutil.Unstructured(t, secret)
+	id := testutil.ToIdentifier(t, secret)
+
+	testCases := map[string]struct {
+		event  event.Event
+		object func(event.Event) *unstructured.Unstructured
+	}{
+		"apply event": {
+			event: event.Event{
+				Type:       event.ApplyType,
+				ApplyEvent: event.ApplyEvent{Identifier: id, Resource: obj},
+			},
+			object: func(e event.Event) *unstructured.Unstructured { return e.ApplyEvent.Resource },
+		},
+		"prune event": {
+			event: event.Event{
+				Type:       event.PruneType,
+				PruneEvent: event.PruneEvent{Identifier: id, Object: obj},
+			},
+			object: func(e event.Event) *unstructured.Unstructured { return e.PruneEvent.Object },
+		},
+		"delete event": {
+			event: event.Event{
+				Type:        event.DeleteType,
+				DeleteEvent: event.DeleteEvent{Identifier: id, Object: obj},
+			},
+			object: func(e event.Event) *unstructured.Unstructured { return e.DeleteEvent.Object },
+		},
+		"status event": {
+			event: event.Event{
+				Type: event.StatusType,
+				StatusEvent: event.StatusEvent{
+					Identifier: id,
+					PollResourceInfo: &pollevent.ResourceStatus{
+						Identifier: id,
+						Resource:   obj,
+					},
+				},
+			},
+			object: func(e event.Event) *unstructured.Unstructured { return e.StatusEvent.PollResourceInfo.Resource },
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			redacted := Event(tc.event)
+
+			testutil.AssertEqual(t, map[string]interface{}{
+				"password": Placeholder,
+				"user":     Placeholder,
+			}, tc.object(redacted).Object["data"])
+			// the object of the passed event must not be modified
+			testutil.AssertEqual(t, "c2VjcmV0", tc.object(tc.event).Object["data"].(map[string]interface{})["password"])
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Package ssaupgrade migrates the field ownership of objects previously
+// applied with client-side apply to server-side apply.
+//
+// Fields set with client-side apply are owned by managers with an Update
+// operation, e.g. kubectl-client-side-apply. After switching to server-side
+// apply, these fields are never removed when they are removed from the local
+// configuration, because the server-side apply manager does not own them.
+// The upgrade merges the client-side apply managers into the server-side
+// apply manager, the way kubectl does with csaupgrade, and removes the
+// last-applied-configuration annotation, which is no longer kept up to date.
+
+package ssaupgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/cmd/apply"
+	"k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// maxPatchRetry is the number of times the upgrade patch is retried, when
+// the object was changed since it was read.
+const maxPatchRetry = 5
+
+// lastAppliedFieldPath is the path of the last-applied-configuration
+// annotation, used to find the client-side apply managers.
+var lastAppliedFieldPath = fieldpath.NewSet(fieldpath.MakePathOrDie(
+	"metadata", "annotations", corev1.LastAppliedConfigAnnotation))
+
+// Status describes the upgrade result of a single object.
+type Status string
+
+const (
+	// Upgraded means the field ownership of the object was migrated.
+	Upgraded Status = "Upgraded"
+	// Unchanged means the object had no client-side apply field ownership.
+	Unchanged Status = "Unchanged"
+	// NotFound means the object is stored in the inventory, but does not
+	// exist in the cluster.
+	NotFound Status = "NotFound"
+	// Failed means the upgrade failed. See Error.
+	Failed Status = "Failed"
+)
+
+// Result is the upgrade result for a single object.
+type Result struct {
+	// Identifier of the object.
+	Identifier object.ObjMetadata
+	// Status is the upgrade state of the object.
+	Status Status
+	// Error encountered while upgrading, if any.
+	Error error
+}
+
+// Results is the list of upgrade results for a set of objects.
+type Results []Result
+
+// ErrorCount returns the number of objects for which the upgrade failed.
+func (rs Results) ErrorCount() int {
+	count := 0
+	for _, r := range rs {
+		if r.Error != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Upgrader migrates the field ownership of the objects in an inventory from
+// client-side apply to server-side apply.
+type Upgrader struct {
+	InvClient inventory.Client
+	Client    dynamic.Interface
+	Mapper    meta.RESTMapper
+}
+
+// NewUpgrader returns a new Upgrader.
+// Returns an error if dependency injection fails using the factory.
+func NewUpgrader(factory util.Factory, invClient inventory.Client) (*Upgrader, error) {
+	client, err := factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	return &Upgrader{
+		InvClient: invClient,
+		Client:    client,
+		Mapper:    mapper,
+	}, nil
+}
+
+// Upgrade migrates the field ownership of every object in the inventory to
+// the passed server-side apply field manager, and returns a Result for each
+// object. In dry-run, the objects which would be upgraded are reported
+// without changing them. Returns an error if the inventory could not be read.
+func (u *Upgrader) Upgrade(ctx context.Context, inv inventory.Info, fieldManager string,
+	dryRun common.DryRunStrategy) (Results, error) {
+	ids, err := u.InvClient.GetClusterObjs(inv)
+	if err != nil {
+		return nil, err
+	}
+	results := make(Results, 0, len(ids))
+	for _, id := range ids {
+		result := u.upgrade(ctx, id, fieldManager, dryRun)
+		klog.V(4).Infof("ssa upgrade result (object: %s): %s", id, result.Status)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (u *Upgrader) upgrade(ctx context.Context, id object.ObjMetadata, fieldManager string,
+	dryRun common.DryRunStrategy) Result {
+	result := Result{Identifier: id, Status: Failed}
+	mapping, err := u.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			result.Status = NotFound
+			return result
+		}
+		result.Error = err
+		return result
+	}
+	client := u.Client.Resource(mapping.Resource).Namespace(id.Namespace)
+	live, err := client.Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			result.Status = NotFound
+			return result
+		}
+		result.Error = fmt.Errorf("failed to get live object: %w", err)
+		return result
+	}
+
+	var upgraded bool
+	if dryRun.ClientOrServerDryRun() {
+		var patch []byte
+		patch, err = Patch(live, fieldManager)
+		upgraded = patch != nil
+	} else {
+		upgraded, err = UpgradeObject(ctx, client, live, fieldManager)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if upgraded {
+		result.Status = Upgraded
+	} else {
+		result.Status = Unchanged
+	}
+	return result
+}
+
+// UpgradeObject migrates the field ownership of the live object to the
+// passed server-side apply field manager, and removes the
+// last-applied-configuration annotation. If the object was changed since it
+// was read, it is read again and the upgrade is retried.
+// Returns true if the object was upgraded, or false if there was nothing to
+// upgrade.
+func UpgradeObject(ctx context.Context, client dynamic.ResourceInterface, live *unstructured.Unstructured,
+	fieldManager string) (bool, error) {
+	for i := 0; ; i++ {
+		patch, err := Patch(live, fieldManager)
+		if err != nil {
+			return false, err
+		}
+		if patch == nil {
+			return false, nil
+		}
+		_, err = client.Patch(ctx, live.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsConflict(err) || i+1 >= maxPatchRetry {
+			return false, fmt.Errorf("failed to upgrade field managers: %w", err)
+		}
+		live, err = client.Get(ctx, live.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to get live object: %w", err)
+		}
+	}
+}
+
+// Patch returns the JSON patch which merges the client-side apply managers of
+// the object into the passed server-side apply field manager, and removes
+// the last-applied-configuration annotation. The patch is conditional on the
+// resourceVersion of the object.
+// Returns nil if there is nothing to upgrade.
+func Patch(obj *unstructured.Unstructured, fieldManager string) ([]byte, error) {
+	upgraded := obj.DeepCopy()
+	err := csaupgrade.UpgradeManagedFields(upgraded, clientSideManagers(obj, fieldManager), fieldManager)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []map[string]interface{}
+	if !reflect.DeepEqual(obj.GetManagedFields(), upgraded.GetManagedFields()) {
+		ops = append(ops, map[string]interface{}{
+			"op":    "replace",
+			"path":  "/metadata/managedFields",
+			"value": upgraded.GetManagedFields(),
+		})
+	}
+	if _, found := obj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; found {
+		ops = append(ops, map[string]interface{}{
+			"op":   "remove",
+			"path": "/metadata/annotations/" + escapeJSONPointer(corev1.LastAppliedConfigAnnotation),
+		})
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	// Use "replace" instead of "test" so that a changed object is rejected
+	// with a conflict, which can be retried.
+	ops = append(ops, map[string]interface{}{
+		"op":    "replace",
+		"path":  "/metadata/resourceVersion",
+		"value": obj.GetResourceVersion(),
+	})
+	return json.Marshal(ops)
+}
+
+// clientSideManagers returns the names of the managers to migrate: the
+// managers which own the last-applied-configuration annotation, the default
+// kubectl client-side apply manager, and the passed field manager, which
+// may have been used for client-side apply too. Only their Update
+// operations are migrated.
+func clientSideManagers(obj *unstructured.Unstructured, fieldManager string) sets.Set[string] {
+	managers := sets.New(apply.FieldManagerClientSideApply, fieldManager)
+	owners := csaupgrade.FindFieldsOwners(obj.GetManagedFields(),
+		metav1.ManagedFieldsOperationUpdate, lastAppliedFieldPath)
+	for _, owner := range owners {
+		managers.Insert(owner.Manager)
+	}
+	return managers
+}
+
+// escapeJSONPointer escapes a JSON pointer path segment (RFC 6901).
+func escapeJSONPointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
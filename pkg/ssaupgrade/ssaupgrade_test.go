@@ -0,0 +1,211 @@
+// Copyright 2022 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package ssaupgrade
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var (
+	configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+const (
+	csaFields = `{"f:data":{".":{},"f:key":{}},"f:metadata":{"f:annotations":{".":{},` +
+		`"f:kubectl.kubernetes.io/last-applied-configuration":{}}}}`
+	ssaFields = `{"f:data":{"f:other":{}}}`
+)
+
+func newConfigMap(name string, lastApplied bool, managedFields ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"namespace":       "default",
+				"resourceVersion": "1",
+			},
+			"data": map[string]interface{}{
+				"key": "value",
+			},
+		},
+	}
+	if lastApplied {
+		obj.SetAnnotations(map[string]string{
+			corev1.LastAppliedConfigAnnotation: `{"data":{"key":"value"}}`,
+		})
+	}
+	obj.SetManagedFields(managedFields)
+	return obj
+}
+
+func managedFieldsEntry(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  operation,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestPatch(t *testing.T) {
+	testCases := map[string]struct {
+		obj                   *unstructured.Unstructured
+		expectedNil           bool
+		expectedManagedFields []metav1.ManagedFieldsEntry
+	}{
+		"server-side applied object is unchanged": {
+			obj: newConfigMap("cm", false,
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationApply, ssaFields)),
+			expectedNil: true,
+		},
+		"client-side apply manager is converted": {
+			obj: newConfigMap("cm", true,
+				managedFieldsEntry("kubectl-client-side-apply", metav1.ManagedFieldsOperationUpdate, csaFields)),
+			expectedManagedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationApply, csaFields),
+			},
+		},
+		"client-side apply manager is merged": {
+			obj: newConfigMap("cm", true,
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationApply, ssaFields),
+				managedFieldsEntry("custom-csa", metav1.ManagedFieldsOperationUpdate, csaFields)),
+			expectedManagedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationApply,
+					`{"f:data":{".":{},"f:key":{},"f:other":{}},"f:metadata":{"f:annotations":{".":{},`+
+						`"f:kubectl.kubernetes.io/last-applied-configuration":{}}}}`),
+			},
+		},
+		"annotation without managed fields is removed": {
+			obj: newConfigMap("cm", true),
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			patch, err := Patch(tc.obj, "kubectl")
+			require.NoError(t, err)
+			if tc.expectedNil {
+				assert.Nil(t, patch)
+				return
+			}
+
+			var ops []struct {
+				Op    string          `json:"op"`
+				Path  string          `json:"path"`
+				Value json.RawMessage `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal(patch, &ops))
+			var managedFields []metav1.ManagedFieldsEntry
+			removed := false
+			for _, op := range ops {
+				switch op.Path {
+				case "/metadata/managedFields":
+					require.NoError(t, json.Unmarshal(op.Value, &managedFields))
+				case "/metadata/annotations/kubectl.kubernetes.io~1last-applied-configuration":
+					assert.Equal(t, "remove", op.Op)
+					removed = true
+				case "/metadata/resourceVersion":
+					assert.Equal(t, `"1"`, string(op.Value))
+				default:
+					t.Errorf("unexpected patch path: %s", op.Path)
+				}
+			}
+			assert.True(t, removed)
+			require.Len(t, managedFields, len(tc.expectedManagedFields))
+			for i := range managedFields {
+				// Compare the fields as parsed JSON.
+				var expected, actual interface{}
+				require.NoError(t, json.Unmarshal(tc.expectedManagedFields[i].FieldsV1.Raw, &expected))
+				require.NoError(t, json.Unmarshal(managedFields[i].FieldsV1.Raw, &actual))
+				assert.Equal(t, expected, actual)
+				managedFields[i].FieldsV1 = nil
+				tc.expectedManagedFields[i].FieldsV1 = nil
+			}
+			testutil.AssertEqual(t, tc.expectedManagedFields, managedFields)
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	csaObj := newConfigMap("csa", true,
+		managedFieldsEntry("kubectl-client-side-apply", metav1.ManagedFieldsOperationUpdate, csaFields))
+	ssaObj := newConfigMap("ssa", false,
+		managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationApply, ssaFields))
+	missingObj := newConfigMap("missing", false)
+
+	testCases := map[string]struct {
+		dryRun           common.DryRunStrategy
+		expectedResults  Results
+		expectedUpgraded bool
+	}{
+		"objects are upgraded": {
+			dryRun: common.DryRunNone,
+			expectedResults: Results{
+				{Identifier: object.UnstructuredToObjMetadata(csaObj), Status: Upgraded},
+				{Identifier: object.UnstructuredToObjMetadata(ssaObj), Status: Unchanged},
+				{Identifier: object.UnstructuredToObjMetadata(missingObj), Status: NotFound},
+			},
+			expectedUpgraded: true,
+		},
+		"dry-run does not change objects": {
+			dryRun: common.DryRunClient,
+			expectedResults: Results{
+				{Identifier: object.UnstructuredToObjMetadata(csaObj), Status: Upgraded},
+				{Identifier: object.UnstructuredToObjMetadata(ssaObj), Status: Unchanged},
+				{Identifier: object.UnstructuredToObjMetadata(missingObj), Status: NotFound},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+				csaObj.DeepCopy(), ssaObj.DeepCopy())
+			invClient := inventory.NewFakeClient(object.UnstructuredSetToObjMetadataSet(
+				object.UnstructuredSet{csaObj, ssaObj, missingObj}))
+			upgrader := &Upgrader{
+				InvClient: invClient,
+				Client:    client,
+				Mapper:    testutil.NewFakeRESTMapper(configMapGVK),
+			}
+
+			results, err := upgrader.Upgrade(context.TODO(), nil, "kubectl", tc.dryRun)
+			require.NoError(t, err)
+			testutil.AssertEqual(t, tc.expectedResults, results)
+			assert.Equal(t, 0, results.ErrorCount())
+
+			live, err := client.Resource(configMapGVR).Namespace("default").
+				Get(context.TODO(), "csa", metav1.GetOptions{})
+			require.NoError(t, err)
+			_, found := live.GetAnnotations()[corev1.LastAppliedConfigAnnotation]
+			assert.Equal(t, !tc.expectedUpgraded, found)
+			require.Len(t, live.GetManagedFields(), 1)
+			if tc.expectedUpgraded {
+				assert.Equal(t, "kubectl", live.GetManagedFields()[0].Manager)
+				assert.Equal(t, metav1.ManagedFieldsOperationApply, live.GetManagedFields()[0].Operation)
+			} else {
+				assert.Equal(t, "kubectl-client-side-apply", live.GetManagedFields()[0].Manager)
+			}
+		})
+	}
+}
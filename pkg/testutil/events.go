@@ -26,6 +26,7 @@ type ExpEvent struct {
 	DeleteEvent      *ExpDeleteEvent
 	WaitEvent        *ExpWaitEvent
 	ValidationEvent  *ExpValidationEvent
+	VerifyEvent      *ExpVerifyEvent
 }
 
 type ExpInitEvent struct {
@@ -76,6 +77,13 @@ type ExpWaitEvent struct {
 	Identifier object.ObjMetadata
 }
 
+type ExpVerifyEvent struct {
+	GroupName  string
+	Status     event.VerifyEventStatus
+	Identifier object.ObjMetadata
+	Error      error
+}
+
 type ExpValidationEvent struct {
 	Identifiers object.ObjMetadataSet
 	Error       error
@@ -295,6 +303,34 @@ func isMatch(ee ExpEvent, e event.Event) bool {
 		}
 		return ve.Error == nil
 
+	case event.VerifyType:
+		vee := ee.VerifyEvent
+		if vee == nil {
+			return true
+		}
+		ve := e.VerifyEvent
+
+		if vee.Identifier != object.NilObjMetadata {
+			if vee.Identifier != ve.Identifier {
+				return false
+			}
+		}
+
+		if vee.GroupName != "" {
+			if vee.GroupName != ve.GroupName {
+				return false
+			}
+		}
+
+		if vee.Status != ve.Status {
+			return false
+		}
+
+		if vee.Error != nil {
+			return ve.Error != nil
+		}
+		return ve.Error == nil
+
 	default:
 		return true
 	}
@@ -398,6 +434,17 @@ func EventToExpEvent(e event.Event) ExpEvent {
 				Error:       e.ValidationEvent.Error,
 			},
 		}
+
+	case event.VerifyType:
+		return ExpEvent{
+			EventType: event.VerifyType,
+			VerifyEvent: &ExpVerifyEvent{
+				GroupName:  e.VerifyEvent.GroupName,
+				Identifier: e.VerifyEvent.Identifier,
+				Status:     e.VerifyEvent.Status,
+				Error:      e.VerifyEvent.Error,
+			},
+		}
 	}
 	return ExpEvent{}
 }